@@ -1,15 +1,19 @@
 package main
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/passwall/passwall-server/internal/api"
 	"github.com/passwall/passwall-server/internal/app"
 	"github.com/passwall/passwall-server/internal/config"
 	"github.com/passwall/passwall-server/internal/router"
 	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/blob"
+	"github.com/passwall/passwall-server/internal/storage/event"
 )
 
 func main() {
@@ -25,9 +29,42 @@ func main() {
 		log.Fatal(err)
 	}
 
-	s := storage.New(db)
+	s := storage.New(db, blob.NewLocal(cfg.Attachment.Folder), event.NewLocal())
 
-	app.MigrateSystemTables(s)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(s, os.Args[2:]); err != nil {
+			logger.Fatal(err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "reindex" {
+		count, err := app.RebuildSearchIndexes(s)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		logger.Printf("reindexed %d items", count)
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "rotate" {
+		if err := rotate(s, os.Args[2:]); err != nil {
+			logger.Fatal(err)
+		}
+		return
+	}
+
+	// failed user schemas are logged, the server can't run without the
+	// system tables or while another instance migrates
+	var failed *app.SchemaMigrationError
+	if err := app.MigrateAll(s); errors.As(err, &failed) {
+		logger.Printf("migration: %v", err)
+	} else if err != nil {
+		logger.Fatalf("migration: %v", err)
+	}
+
+	app.StartTrashPurge(s)
+	app.StartTombstonePrune(s)
 
 	srv := &http.Server{
 		MaxHeaderBytes: 10, // 10 MB
@@ -36,6 +73,7 @@ func main() {
 		ReadTimeout:    time.Second * time.Duration(cfg.Server.Timeout),
 		IdleTimeout:    time.Second * 60,
 		Handler:        router.New(s),
+		ConnContext:    api.ConnContext,
 	}
 
 	logger.Printf("listening on %s", cfg.Server.Port)
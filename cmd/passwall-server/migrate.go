@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+const migrateUsage = `Usage: passwall-server migrate up|down|status [flags]
+
+  up      applies the pending migrations
+  down    rolls back the last applied migrations
+  status  lists the migrations and whether they are applied
+
+Flags:
+`
+
+// migrate runs the migrate command with its arguments
+func migrate(s storage.Store, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	schema := flags.String("schema", "", "user schema to migrate (e.g. user1), system tables if empty")
+	all := flags.Bool("all", false, "apply the pending migrations of the system tables and every user schema with up")
+	steps := flags.Int("steps", 1, "number of migrations to roll back with down")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), migrateUsage)
+		flags.PrintDefaults()
+	}
+
+	if len(args) == 0 {
+		flags.Usage()
+		return fmt.Errorf("missing migrate command")
+	}
+	command := args[0]
+	flags.Parse(args[1:])
+
+	name := *schema
+	if name == "" {
+		name = "system"
+	}
+
+	switch command {
+	case "up":
+		if *all {
+			return app.MigrateAll(s)
+		}
+		return app.WithMigrationLock(s, func(refresh func() error) error {
+			migrated, err := s.Migrations().Up(*schema)
+			printMigrations(name, "applied", migrated)
+			return err
+		})
+	case "down":
+		return app.WithMigrationLock(s, func(refresh func() error) error {
+			rolledBack, err := s.Migrations().Down(*schema, *steps)
+			printMigrations(name, "rolled back", rolledBack)
+			return err
+		})
+	case "status":
+		statuses, err := s.Migrations().Status(*schema)
+		if err != nil {
+			return err
+		}
+		printStatus(statuses)
+		return nil
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown migrate command %q", command)
+	}
+}
+
+func printMigrations(schema, action string, migrations []model.Migration) {
+	if len(migrations) == 0 {
+		fmt.Printf("%s: nothing %s\n", schema, action)
+	}
+	for _, m := range migrations {
+		fmt.Printf("%s: %s %d %s\n", schema, action, m.Version, m.Name)
+	}
+}
+
+func printStatus(statuses []model.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	w.Flush()
+}
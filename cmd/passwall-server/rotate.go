@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+)
+
+const rotateUsage = `Usage: passwall-server rotate [flags]
+
+  encrypts again with server.passphrase the values encrypted with one of
+  server.legacyPassphrases. Run it again if it is interrupted, values already
+  encrypted with server.passphrase are skipped. Remove the legacy passphrases
+  once it succeeds.
+
+Flags:
+`
+
+// rotate runs the rotate command with its arguments
+func rotate(s storage.Store, args []string) error {
+	flags := flag.NewFlagSet("rotate", flag.ExitOnError)
+	schema := flags.String("schema", "", "user schema to rotate (e.g. user1), every user schema if empty")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), rotateUsage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	var count int
+	var err error
+	if *schema != "" {
+		count, err = app.RotateSchema(s, *schema)
+	} else {
+		count, err = app.RotatePassphrase(s)
+	}
+	fmt.Printf("encrypted %d rows again with the current passphrase\n", count)
+	return err
+}
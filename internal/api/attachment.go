@@ -0,0 +1,200 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+const (
+	attachmentDeleteSuccess = "Attachment deleted successfully!"
+	// attachmentFormField is the multipart form field of uploaded files
+	attachmentFormField = "file"
+)
+
+// FindAttachments lists the attachments of an item
+func FindAttachments(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		attachments, err := s.Attachments().FindAll(vars["type"], uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		for i := range attachments {
+			decAttachment, err := app.DecryptModel(&attachments[i])
+			if err != nil {
+				RespondWithError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			attachments[i] = *decAttachment.(*model.Attachment)
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, model.ToAttachmentDTOs(attachments))
+	}
+}
+
+// UploadAttachment attaches the file in the "file" field of a multipart form to
+// an item. The file is streamed to the blob store, it isn't kept in memory.
+func UploadAttachment(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		defer r.Body.Close()
+
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				RespondWithError(w, http.StatusBadRequest, "there is no file in the \""+attachmentFormField+"\" field")
+				return
+			}
+			if err != nil {
+				RespondWithError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if part.FormName() != attachmentFormField {
+				continue
+			}
+
+			schema := r.Context().Value("schema").(string)
+			contentType := part.Header.Get("Content-Type")
+			createdAttachment, err := app.CreateAttachment(s, vars["type"], uint(id), part.FileName(), contentType, part, schema)
+			if err != nil {
+				respondWithAttachmentError(w, err)
+				return
+			}
+
+			decAttachment, err := app.DecryptModel(createdAttachment)
+			if err != nil {
+				RespondWithError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			RespondWithEncJSON(w, http.StatusOK, transmissionKey, model.ToAttachmentDTO(decAttachment.(*model.Attachment)))
+			return
+		}
+	}
+}
+
+// DownloadAttachment streams the decrypted content of an attachment
+func DownloadAttachment(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		attachment, ok := findAttachment(w, r, s)
+		if !ok {
+			return
+		}
+
+		content, err := app.OpenAttachment(s, attachment)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		defer content.Close()
+
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+		w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+
+		// The status is sent already, a failure can only be logged
+		if _, err := io.Copy(w, content); err != nil {
+			log.Printf("could not send attachment %d: %v", attachment.ID, err)
+		}
+	}
+}
+
+// DeleteAttachment removes an attachment with its content
+func DeleteAttachment(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		attachment, ok := findAttachment(w, r, s)
+		if !ok {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		if err := app.DeleteAttachment(s, attachment, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  Success,
+			Message: attachmentDeleteSuccess,
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
+
+// findAttachment finds the attachment with the id in the url and decrypts it
+func findAttachment(w http.ResponseWriter, r *http.Request, s storage.Store) (*model.Attachment, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	schema := r.Context().Value("schema").(string)
+	attachment, err := s.Attachments().FindByID(uint(id), schema)
+	if err != nil {
+		RespondWithError(w, http.StatusNotFound, err.Error())
+		return nil, false
+	}
+
+	decAttachment, err := app.DecryptModel(attachment)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return decAttachment.(*model.Attachment), true
+}
+
+func respondWithAttachmentError(w http.ResponseWriter, err error) {
+	switch {
+	case err == app.ErrUnknownItemType, err == app.ErrInvalidAttachment:
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, app.ErrAttachmentTooLarge), errors.Is(err, app.ErrAttachmentQuota):
+		RespondWithError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case gorm.IsRecordNotFoundError(err):
+		RespondWithError(w, http.StatusNotFound, err.Error())
+	default:
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -37,6 +37,13 @@ func FindAllBankAccounts(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		total, err := s.BankAccounts().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, bankAccountList, total, argsStr, argsInt)
+
 		// Decrypt server side encrypted fields
 		for i := range bankAccountList {
 			uBankAccount, err := app.DecryptModel(&bankAccountList[i])
@@ -47,6 +54,11 @@ func FindAllBankAccounts(s storage.Store) http.HandlerFunc {
 			bankAccountList[i] = *uBankAccount.(*model.BankAccount)
 		}
 
+		if err := app.LoadTags(s, model.BankAccountItem, bankAccountList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, bankAccountList)
 	}
 }
@@ -73,6 +85,11 @@ func FindBankAccountByID(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		if err := app.LoadTags(s, model.BankAccountItem, bankAccount, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		// Decrypt server side encrypted fields
 		uBankAccount, err := app.DecryptModel(bankAccount)
 		if err != nil {
@@ -83,6 +100,7 @@ func FindBankAccountByID(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		bankAccountDTO := model.ToBankAccountDTO(uBankAccount.(*model.BankAccount))
 
+		setETag(w, bankAccountDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, bankAccountDTO)
 	}
 }
@@ -116,7 +134,7 @@ func CreateBankAccount(s storage.Store) http.HandlerFunc {
 		schema := r.Context().Value("schema").(string)
 		createdBankAccount, err := app.CreateBankAccount(s, &bankAccountDTO, schema)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			respondWithItemError(w, err)
 			return
 		}
 
@@ -130,6 +148,7 @@ func CreateBankAccount(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		createdBankAccountDTO := model.ToBankAccountDTO(decBankAccount.(*model.BankAccount))
 
+		setETag(w, createdBankAccountDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdBankAccountDTO)
 	}
 }
@@ -163,6 +182,13 @@ func UpdateBankAccount(s storage.Store) http.HandlerFunc {
 		}
 		defer r.Body.Close()
 
+		// The update only depends on the revision of the If-Match header
+		bankAccountDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		// Find login defined by id
 		schema := r.Context().Value("schema").(string)
 		bankAccount, err := s.BankAccounts().FindByID(uint(id), schema)
@@ -174,7 +200,7 @@ func UpdateBankAccount(s storage.Store) http.HandlerFunc {
 		// Update login
 		updatedBankAccount, err := app.UpdateBankAccount(s, bankAccount, &bankAccountDTO, schema)
 		if err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithUpdateError(w, transmissionKey, err)
 			return
 		}
 
@@ -188,53 +214,63 @@ func UpdateBankAccount(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		updatedBankAccountDTO := model.ToBankAccountDTO(decBankAccount.(*model.BankAccount))
 
+		setETag(w, updatedBankAccountDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedBankAccountDTO)
 	}
 }
 
-// BulkUpdateBankAccounts updates bankAccounts in payload
-func BulkUpdateBankAccounts(s storage.Store) http.HandlerFunc {
+// BulkCreateBankAccounts creates the bank accounts in the payload, all of them or none
+func BulkCreateBankAccounts(s storage.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var bankAccountList []model.BankAccountDTO
 
 		// Setup variables
 		env := viper.GetString("server.env")
 		transmissionKey := r.Context().Value("transmissionKey").(string)
-		if err := ToBody(r, env, transmissionKey); err != nil {
-			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		if !decodeList(w, r, env, transmissionKey, &bankAccountList) {
 			return
 		}
-		defer r.Body.Close()
 
-		decoder := json.NewDecoder(r.Body)
-		if err := decoder.Decode(&bankAccountList); err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
-		defer r.Body.Close()
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkCreateBankAccounts(s, bankAccountList, schema)
+		respondWithBulkResults(w, "Bulk create", results, err)
+	}
+}
 
-		for _, bankAccountDTO := range bankAccountList {
-			// Find bankAccount defined by id
-			schema := r.Context().Value("schema").(string)
-			bankAccount, err := s.BankAccounts().FindByID(bankAccountDTO.ID, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusNotFound, err.Error())
-				return
-			}
+// BulkUpdateBankAccounts updates the bank accounts in the payload, all of them or none
+func BulkUpdateBankAccounts(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var bankAccountList []model.BankAccountDTO
 
-			// Update bankAccount
-			_, err = app.UpdateBankAccount(s, bankAccount, &bankAccountDTO, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusInternalServerError, err.Error())
-				return
-			}
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &bankAccountList) {
+			return
 		}
 
-		response := model.Response{
-			Code:    http.StatusOK,
-			Status:  "Success",
-			Message: "Bulk update completed successfully!",
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkUpdateBankAccounts(s, bankAccountList, schema)
+		respondWithBulkResults(w, "Bulk update", results, err)
+	}
+}
+
+// BulkDeleteBankAccounts moves the bank accounts with the ids in the payload to the trash,
+// all of them or none
+func BulkDeleteBankAccounts(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ids []uint
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &ids) {
+			return
 		}
-		RespondWithJSON(w, http.StatusOK, response)
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkDeleteBankAccounts(s, ids, schema)
+		respondWithBulkResults(w, "Bulk delete", results, err)
 	}
 }
 
@@ -255,7 +291,7 @@ func DeleteBankAccount(s storage.Store) http.HandlerFunc {
 			return
 		}
 
-		err = s.BankAccounts().Delete(bankAccount.ID, schema)
+		err = app.DeleteItem(s, model.BankAccountItem, bankAccount.ID, schema)
 		if err != nil {
 			RespondWithError(w, http.StatusNotFound, err.Error())
 			return
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/model"
+)
+
+// respondWithBulkResults responds with the result of every item of a bulk
+// request. When an item failed nothing was changed and the results tell why.
+func respondWithBulkResults(w http.ResponseWriter, action string, results []model.BulkResult, err error) {
+	response := model.BulkResponse{
+		Code:    http.StatusOK,
+		Status:  Success,
+		Message: action + " completed successfully!",
+		Results: results,
+	}
+
+	switch {
+	case err == app.ErrBulkFailed:
+		response.Code, response.Status, response.Message = http.StatusBadRequest, "Error", action+" failed, "+err.Error()
+	case err == app.ErrBulkConflict:
+		response.Code, response.Status, response.Message = http.StatusConflict, "Error", action+" failed, "+err.Error()
+	case err != nil:
+		response.Code, response.Status, response.Message = http.StatusInternalServerError, "Error", err.Error()
+	}
+
+	RespondWithJSON(w, response.Code, response)
+}
@@ -42,6 +42,13 @@ func FindAllCreditCards(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		total, err := s.CreditCards().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, creditCardList, total, argsStr, argsInt)
+
 		// Decrypt server side encrypted fields
 		for i := range creditCardList {
 			uCreditCard, err := app.DecryptModel(&creditCardList[i])
@@ -52,6 +59,11 @@ func FindAllCreditCards(s storage.Store) http.HandlerFunc {
 			creditCardList[i] = *uCreditCard.(*model.CreditCard)
 		}
 
+		if err := app.LoadTags(s, model.CreditCardItem, creditCardList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, creditCardList)
 	}
 }
@@ -79,6 +91,11 @@ func FindCreditCardByID(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		if err := app.LoadTags(s, model.CreditCardItem, creditCard, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		// Decrypt server side encrypted fields
 		uCreditCard, err := app.DecryptModel(creditCard)
 		if err != nil {
@@ -89,6 +106,7 @@ func FindCreditCardByID(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		creditCardDTO := model.ToCreditCardDTO(uCreditCard.(*model.CreditCard))
 
+		setETag(w, creditCardDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, creditCardDTO)
 	}
 }
@@ -123,7 +141,7 @@ func CreateCreditCard(s storage.Store) http.HandlerFunc {
 		schema := r.Context().Value("schema").(string)
 		createdCreditCard, err := app.CreateCreditCard(s, &creditCardDTO, schema)
 		if err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithItemError(w, err)
 			return
 		}
 
@@ -137,6 +155,7 @@ func CreateCreditCard(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		createdCreditCardDTO := model.ToCreditCardDTO(decCreditCard.(*model.CreditCard))
 
+		setETag(w, createdCreditCardDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdCreditCardDTO)
 	}
 }
@@ -171,6 +190,13 @@ func UpdateCreditCard(s storage.Store) http.HandlerFunc {
 		}
 		defer r.Body.Close()
 
+		// The update only depends on the revision of the If-Match header
+		creditCardDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		// Find credit card defined by id
 		schema := r.Context().Value("schema").(string)
 		creditCard, err := s.CreditCards().FindByID(uint(id), schema)
@@ -182,7 +208,7 @@ func UpdateCreditCard(s storage.Store) http.HandlerFunc {
 		// Update credit card
 		updatedCreditCard, err := app.UpdateCreditCard(s, creditCard, &creditCardDTO, schema)
 		if err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithUpdateError(w, transmissionKey, err)
 			return
 		}
 
@@ -196,53 +222,63 @@ func UpdateCreditCard(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		updatedCreditCardDTO := model.ToCreditCardDTO(decCreditCard.(*model.CreditCard))
 
+		setETag(w, updatedCreditCardDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedCreditCardDTO)
 	}
 }
 
-// BulkUpdateCreditCards updates creditCards in payload
-func BulkUpdateCreditCards(s storage.Store) http.HandlerFunc {
+// BulkCreateCreditCards creates the credit cards in the payload, all of them or none
+func BulkCreateCreditCards(s storage.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var creditCardList []model.CreditCardDTO
 
 		// Setup variables
 		env := viper.GetString("server.env")
 		transmissionKey := r.Context().Value("transmissionKey").(string)
-		if err := ToBody(r, env, transmissionKey); err != nil {
-			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		if !decodeList(w, r, env, transmissionKey, &creditCardList) {
 			return
 		}
-		defer r.Body.Close()
 
-		decoder := json.NewDecoder(r.Body)
-		if err := decoder.Decode(&creditCardList); err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
-		defer r.Body.Close()
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkCreateCreditCards(s, creditCardList, schema)
+		respondWithBulkResults(w, "Bulk create", results, err)
+	}
+}
 
-		for _, creditCardDTO := range creditCardList {
-			// Find creditCard defined by id
-			schema := r.Context().Value("schema").(string)
-			creditCard, err := s.CreditCards().FindByID(creditCardDTO.ID, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusNotFound, err.Error())
-				return
-			}
+// BulkUpdateCreditCards updates the credit cards in the payload, all of them or none
+func BulkUpdateCreditCards(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var creditCardList []model.CreditCardDTO
 
-			// Update creditCard
-			_, err = app.UpdateCreditCard(s, creditCard, &creditCardDTO, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusInternalServerError, err.Error())
-				return
-			}
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &creditCardList) {
+			return
 		}
 
-		response := model.Response{
-			Code:    http.StatusOK,
-			Status:  "Success",
-			Message: "Bulk update completed successfully!",
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkUpdateCreditCards(s, creditCardList, schema)
+		respondWithBulkResults(w, "Bulk update", results, err)
+	}
+}
+
+// BulkDeleteCreditCards moves the credit cards with the ids in the payload to the trash,
+// all of them or none
+func BulkDeleteCreditCards(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ids []uint
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &ids) {
+			return
 		}
-		RespondWithJSON(w, http.StatusOK, response)
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkDeleteCreditCards(s, ids, schema)
+		respondWithBulkResults(w, "Bulk delete", results, err)
 	}
 }
 
@@ -263,7 +299,7 @@ func DeleteCreditCard(s storage.Store) http.HandlerFunc {
 			return
 		}
 
-		err = s.CreditCards().Delete(creditCard.ID, schema)
+		err = app.DeleteItem(s, model.CreditCardItem, creditCard.ID, schema)
 		if err != nil {
 			RespondWithError(w, http.StatusNotFound, err.Error())
 			return
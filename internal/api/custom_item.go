@@ -0,0 +1,248 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+)
+
+// FindAllCustomItems finds the items of the template in the url
+func FindAllCustomItems(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		template, ok := findTemplate(w, r, s, schema)
+		if !ok {
+			return
+		}
+
+		fields := []string{"id", "created_at", "updated_at", "title"}
+		argsStr, argsInt := SetArgs(r, fields)
+		argsStr["type"] = template.Type
+
+		customItemList, err := s.CustomItems().FindAll(argsStr, argsInt, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		total, err := s.CustomItems().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, customItemList, total, argsStr, argsInt)
+
+		// Decrypt server side encrypted fields
+		for i := range customItemList {
+			decCustomItem, err := app.DecryptModel(&customItemList[i])
+			if err != nil {
+				RespondWithError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			customItemList[i] = *decCustomItem.(*model.CustomItem)
+		}
+
+		if err := app.LoadTags(s, model.CustomItemItem, customItemList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		customItemDTOs := make([]*model.CustomItemDTO, len(customItemList))
+		for i := range customItemList {
+			customItemDTOs[i] = model.ToCustomItemDTO(&customItemList[i])
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, customItemDTOs)
+	}
+}
+
+// FindCustomItemByID finds an item of the template in the url by id
+func FindCustomItemByID(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		customItem, ok := findCustomItem(w, r, s, schema)
+		if !ok {
+			return
+		}
+
+		if err := app.LoadTags(s, model.CustomItemItem, customItem, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decCustomItem, err := app.DecryptModel(customItem)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		customItemDTO := model.ToCustomItemDTO(decCustomItem.(*model.CustomItem))
+		setETag(w, customItemDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, customItemDTO)
+	}
+}
+
+// CreateCustomItem creates an item of the template in the url
+func CreateCustomItem(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		template, ok := findTemplate(w, r, s, schema)
+		if !ok {
+			return
+		}
+
+		var customItemDTO model.CustomItemDTO
+		if !decodePayload(w, r, env, transmissionKey, &customItemDTO) {
+			return
+		}
+
+		createdCustomItem, err := app.CreateCustomItem(s, template, &customItemDTO, schema)
+		if err != nil {
+			respondWithCustomItemError(w, err)
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decCustomItem, err := app.DecryptModel(createdCustomItem)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		createdCustomItemDTO := model.ToCustomItemDTO(decCustomItem.(*model.CustomItem))
+		setETag(w, createdCustomItemDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdCustomItemDTO)
+	}
+}
+
+// UpdateCustomItem updates an item of the template in the url
+func UpdateCustomItem(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		template, ok := findTemplate(w, r, s, schema)
+		if !ok {
+			return
+		}
+
+		var customItemDTO model.CustomItemDTO
+		if !decodePayload(w, r, env, transmissionKey, &customItemDTO) {
+			return
+		}
+
+		// The update only depends on the revision of the If-Match header
+		var err error
+		customItemDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		customItem, ok := findCustomItem(w, r, s, schema)
+		if !ok {
+			return
+		}
+
+		updatedCustomItem, err := app.UpdateCustomItem(s, template, customItem, &customItemDTO, schema)
+		if _, ok := err.(*app.RevisionConflictError); ok {
+			respondWithUpdateError(w, transmissionKey, err)
+			return
+		}
+		if err != nil {
+			respondWithCustomItemError(w, err)
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decCustomItem, err := app.DecryptModel(updatedCustomItem)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		updatedCustomItemDTO := model.ToCustomItemDTO(decCustomItem.(*model.CustomItem))
+		setETag(w, updatedCustomItemDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedCustomItemDTO)
+	}
+}
+
+// DeleteCustomItem moves an item of the template in the url to the trash
+func DeleteCustomItem(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		schema := r.Context().Value("schema").(string)
+
+		customItem, ok := findCustomItem(w, r, s, schema)
+		if !ok {
+			return
+		}
+
+		if err := app.DeleteItem(s, model.CustomItemItem, customItem.ID, schema); err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  Success,
+			Message: "Item deleted successfully!",
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
+
+// findTemplate finds the template of the type in the url, it responds with
+// not found when there is none
+func findTemplate(w http.ResponseWriter, r *http.Request, s storage.Store, schema string) (*model.Template, bool) {
+	template, err := s.Templates().FindByType(mux.Vars(r)["type"], schema)
+	if err != nil {
+		RespondWithError(w, http.StatusNotFound, err.Error())
+		return nil, false
+	}
+	return template, true
+}
+
+// findCustomItem finds the item with the id in the url, items of other
+// templates are not found
+func findCustomItem(w http.ResponseWriter, r *http.Request, s storage.Store, schema string) (*model.CustomItem, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	customItem, err := s.CustomItems().FindByID(uint(id), schema)
+	if err != nil {
+		RespondWithError(w, http.StatusNotFound, err.Error())
+		return nil, false
+	}
+	if customItem.Type != vars["type"] {
+		RespondWithError(w, http.StatusNotFound, "record not found")
+		return nil, false
+	}
+	return customItem, true
+}
+
+func respondWithCustomItemError(w http.ResponseWriter, err error) {
+	if errors.Is(err, app.ErrInvalidCustomItem) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithItemError(w, err)
+}
@@ -32,6 +32,13 @@ func FindAllEmails(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		total, err := s.Emails().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, emailList, total, argsStr, argsInt)
+
 		// Decrypt server side encrypted fields
 		for i := range emailList {
 			decEmail, err := app.DecryptModel(&emailList[i])
@@ -42,6 +49,11 @@ func FindAllEmails(s storage.Store) http.HandlerFunc {
 			emailList[i] = *decEmail.(*model.Email)
 		}
 
+		if err := app.LoadTags(s, model.EmailItem, emailList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, emailList)
 	}
 }
@@ -68,6 +80,11 @@ func FindEmailByID(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		if err := app.LoadTags(s, model.EmailItem, email, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		// Decrypt server side encrypted fields
 		decEmail, err := app.DecryptModel(email)
 		if err != nil {
@@ -77,6 +94,7 @@ func FindEmailByID(s storage.Store) http.HandlerFunc {
 
 		emailDTO := model.ToEmailDTO(decEmail.(*model.Email))
 
+		setETag(w, emailDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, emailDTO)
 	}
 }
@@ -111,7 +129,7 @@ func CreateEmail(s storage.Store) http.HandlerFunc {
 		schema := r.Context().Value("schema").(string)
 		createdEmail, err := app.CreateEmail(s, &emailDTO, schema)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			respondWithItemError(w, err)
 			return
 		}
 
@@ -125,6 +143,7 @@ func CreateEmail(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		createdEmailDTO := model.ToEmailDTO(decEmail.(*model.Email))
 
+		setETag(w, createdEmailDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdEmailDTO)
 	}
 }
@@ -158,6 +177,13 @@ func UpdateEmail(s storage.Store) http.HandlerFunc {
 		}
 		defer r.Body.Close()
 
+		// The update only depends on the revision of the If-Match header
+		emailDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		// Find email defined by id
 		schema := r.Context().Value("schema").(string)
 		email, err := s.Emails().FindByID(uint(id), schema)
@@ -169,7 +195,7 @@ func UpdateEmail(s storage.Store) http.HandlerFunc {
 		// Update email
 		updatedEmail, err := app.UpdateEmail(s, email, &emailDTO, schema)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			respondWithUpdateError(w, transmissionKey, err)
 			return
 		}
 
@@ -183,54 +209,64 @@ func UpdateEmail(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		updatedEmailDTO := model.ToEmailDTO(decEmail.(*model.Email))
 
+		setETag(w, updatedEmailDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedEmailDTO)
 
 	}
 }
 
-// BulkUpdateEmails updates emails in payload
-func BulkUpdateEmails(s storage.Store) http.HandlerFunc {
+// BulkCreateEmails creates the emails in the payload, all of them or none
+func BulkCreateEmails(s storage.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var emailList []model.EmailDTO
 
 		// Setup variables
 		env := viper.GetString("server.env")
 		transmissionKey := r.Context().Value("transmissionKey").(string)
-		if err := ToBody(r, env, transmissionKey); err != nil {
-			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		if !decodeList(w, r, env, transmissionKey, &emailList) {
 			return
 		}
-		defer r.Body.Close()
 
-		decoder := json.NewDecoder(r.Body)
-		if err := decoder.Decode(&emailList); err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
-		defer r.Body.Close()
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkCreateEmails(s, emailList, schema)
+		respondWithBulkResults(w, "Bulk create", results, err)
+	}
+}
 
-		for _, emailDTO := range emailList {
-			// Find email defined by id
-			schema := r.Context().Value("schema").(string)
-			email, err := s.Emails().FindByID(emailDTO.ID, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusNotFound, err.Error())
-				return
-			}
+// BulkUpdateEmails updates the emails in the payload, all of them or none
+func BulkUpdateEmails(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var emailList []model.EmailDTO
 
-			// Update email
-			_, err = app.UpdateEmail(s, email, &emailDTO, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusInternalServerError, err.Error())
-				return
-			}
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &emailList) {
+			return
 		}
 
-		response := model.Response{
-			Code:    http.StatusOK,
-			Status:  "Success",
-			Message: "Bulk update completed successfully!",
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkUpdateEmails(s, emailList, schema)
+		respondWithBulkResults(w, "Bulk update", results, err)
+	}
+}
+
+// BulkDeleteEmails moves the emails with the ids in the payload to the trash,
+// all of them or none
+func BulkDeleteEmails(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ids []uint
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &ids) {
+			return
 		}
-		RespondWithJSON(w, http.StatusOK, response)
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkDeleteEmails(s, ids, schema)
+		respondWithBulkResults(w, "Bulk delete", results, err)
 	}
 }
 
@@ -251,7 +287,7 @@ func DeleteEmail(s storage.Store) http.HandlerFunc {
 			return
 		}
 
-		err = s.Emails().Delete(email.ID, schema)
+		err = app.DeleteItem(s, model.EmailItem, email.ID, schema)
 		if err != nil {
 			RespondWithError(w, http.StatusNotFound, err.Error())
 			return
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+)
+
+const (
+	// eventHeartbeat keeps idle streams open through proxies
+	eventHeartbeat = 10 * time.Second
+	// eventRetry is the time clients wait before reconnecting, in milliseconds
+	eventRetry = 2000
+	// eventWriteTimeout is the write deadline of a stream, pushed back on
+	// every write instead of the write timeout of the server
+	eventWriteTimeout = 3 * eventHeartbeat
+)
+
+// connContextKey is the context key of the connection of a request
+type connContextKey struct{}
+
+// ConnContext keeps the connection of the requests in their context. The
+// server's write timeout would end the event streams, they extend the write
+// deadline of their connection like http.ResponseController does, which
+// can't reach the connection through the negroni response writer.
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
+// extendWriteDeadline pushes back the write deadline of the connection of
+// the request, when the server kept it
+func extendWriteDeadline(r *http.Request) {
+	if c, ok := r.Context().Value(connContextKey{}).(net.Conn); ok {
+		c.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+	}
+}
+
+// CreateEventToken creates a short-lived token which opens the event stream
+// of the session in the token query parameter
+func CreateEventToken(w http.ResponseWriter, r *http.Request) {
+	userUUID := r.Context().Value("uuid").(string)
+	tokenUUID := r.Context().Value("tokenUUID").(string)
+	authorized := r.Context().Value("authorized").(bool)
+
+	token, err := app.CreateEventToken(userUUID, tokenUUID, authorized)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, model.EventTokenDTO{Token: token})
+}
+
+// StreamEvents streams the item events of the user as Server-Sent Events
+// until the client leaves or its session ends. Events are hints: clients
+// apply the changes with a sync, after reconnecting too, so they don't miss
+// the events sent while they were away.
+func StreamEvents(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+		tokenUUID := r.Context().Value("tokenUUID").(string)
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			RespondWithError(w, http.StatusInternalServerError, "streaming is not supported")
+			return
+		}
+
+		events, cancel := s.Events().Subscribe(schema)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-Accel-Buffering", "no")
+		extendWriteDeadline(r)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", eventRetry)
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case e, ok := <-events:
+				if !ok {
+					// the subscriber fell behind, the client reconnects and syncs
+					return
+				}
+				data, err := eventData(transmissionKey, e)
+				if err != nil {
+					return
+				}
+				extendWriteDeadline(r)
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+			case <-heartbeat.C:
+				// the stream ends with the session, after a sign out too
+				if _, ok := s.Tokens().Any(tokenUUID); !ok {
+					return
+				}
+				extendWriteDeadline(r)
+				fmt.Fprint(w, ": heartbeat\n\n")
+			case <-r.Context().Done():
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// eventData returns the data of an event, encrypted like the responses
+func eventData(transmissionKey string, e model.Event) ([]byte, error) {
+	if viper.GetString("server.env") == "dev" {
+		return json.Marshal(e)
+	}
+
+	encrypted, err := app.EncryptJSON(transmissionKey, e)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(model.Payload{Data: string(encrypted)})
+}
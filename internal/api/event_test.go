@@ -0,0 +1,52 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamEventsOutlivesWriteTimeout(t *testing.T) {
+	viper.Set("server.env", "dev")
+	s := memory.New()
+	schema := "user1"
+
+	stream := StreamEvents(s)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), "schema", schema)
+		ctx = context.WithValue(ctx, "transmissionKey", "")
+		ctx = context.WithValue(ctx, "tokenUUID", "")
+		stream(w, r.WithContext(ctx))
+	}))
+	srv.Config.WriteTimeout = 200 * time.Millisecond
+	srv.Config.ConnContext = ConnContext
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	time.Sleep(3 * srv.Config.WriteTimeout)
+	require.Nil(t, s.Events().Publish(schema, model.Event{Type: model.ItemChangedEvent, ItemType: model.NoteItem, ItemID: 1}))
+
+	lines := bufio.NewScanner(resp.Body)
+	for lines.Scan() {
+		if strings.HasPrefix(lines.Text(), "event: ") {
+			assert.Equal(t, "event: "+model.ItemChangedEvent, lines.Text())
+			return
+		}
+	}
+	t.Fatalf("the stream ended: %v", lines.Err())
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+)
+
+// FindAllFavorites lists the favorite items of every type
+func FindAllFavorites(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		favorites, err := app.Favorites(s, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, favorites)
+	}
+}
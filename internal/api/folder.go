@@ -0,0 +1,186 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+)
+
+const (
+	folderDeleteSuccess = "Folder deleted successfully!"
+	folderMoveSuccess   = "Items moved successfully!"
+)
+
+// FindAllFolders finds all folders
+func FindAllFolders(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		folders, err := s.Folders().All(schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, folders)
+	}
+}
+
+// FindFolderByID finds a folder by id
+func FindFolderByID(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		folder, err := s.Folders().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, model.ToFolderDTO(folder))
+	}
+}
+
+// CreateFolder creates a folder
+func CreateFolder(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		var folderDTO model.FolderDTO
+		if !decodePayload(w, r, env, transmissionKey, &folderDTO) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		createdFolder, err := app.CreateFolder(s, &folderDTO, schema)
+		if err != nil {
+			respondWithFolderError(w, err)
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, model.ToFolderDTO(createdFolder))
+	}
+}
+
+// UpdateFolder renames a folder or moves it under another folder
+func UpdateFolder(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		var folderDTO model.FolderDTO
+		if !decodePayload(w, r, env, transmissionKey, &folderDTO) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		folder, err := s.Folders().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		updatedFolder, err := app.UpdateFolder(s, folder, &folderDTO, schema)
+		if err != nil {
+			respondWithFolderError(w, err)
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, model.ToFolderDTO(updatedFolder))
+	}
+}
+
+// DeleteFolder deletes a folder, its items and subfolders move to its parent
+func DeleteFolder(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		folder, err := s.Folders().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if err := app.DeleteFolder(s, folder, schema); err != nil {
+			respondWithFolderError(w, err)
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  Success,
+			Message: folderDeleteSuccess,
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
+
+// MoveItems moves items of any type into a folder
+func MoveItems(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		var moveDTO model.MoveItemsDTO
+		if !decodePayload(w, r, env, transmissionKey, &moveDTO) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		if err := app.MoveItems(s, &moveDTO, schema); err != nil {
+			respondWithFolderError(w, err)
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  Success,
+			Message: folderMoveSuccess,
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
+
+func respondWithFolderError(w http.ResponseWriter, err error) {
+	switch {
+	case err == app.ErrUnknownItemType, err == app.ErrFolderCycle:
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	case gorm.IsRecordNotFoundError(err):
+		RespondWithError(w, http.StatusNotFound, err.Error())
+	case errors.As(err, new(*app.RevisionConflictError)):
+		// an item was changed meanwhile, nothing was moved
+		RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -1,34 +1,15 @@
 package api
 
 import (
-	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"github.com/passwall/passwall-server/internal/config"
-	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/memory"
 )
 
 func TestHealthCheck(t *testing.T) {
-	// create valid database config
-	// should be same with the one on github actions
-
-	mockDBConfig := &config.DatabaseConfiguration{
-		Name:     "passwall",
-		Username: "postgres",
-		Password: "postgres",
-		Host:     "localhost",
-		Port:     "5432",
-		LogMode:  false,
-	}
-
-	mockDB, err := storage.DBConn(mockDBConfig)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	db := storage.New(mockDB)
+	db := memory.New()
 
 	req, err := http.NewRequest("GET", "/health", nil)
 	if err != nil {
@@ -2,15 +2,19 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/passwall/passwall-server/model"
 
 	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
 )
 
 // SetArgs ...
@@ -20,11 +24,22 @@ func SetArgs(r *http.Request, fields []string) (map[string]string, map[string]in
 	search := r.FormValue("Search")
 	sort := r.FormValue("Sort")
 	order := r.FormValue("Order")
+	favorite := r.FormValue("Favorite")
+	folder := r.FormValue("Folder")
+	tags := r.FormValue("Tags")
+	cursor := r.FormValue("Cursor")
 	argsStr := map[string]string{
-		"search": search,
-		"order":  setOrder(fields, sort, order),
+		"search":   search,
+		"order":    setOrder(fields, sort, order),
+		"favorite": setFavorite(favorite),
+		"folder":   setFolder(folder),
+		"tags":     setTags(tags),
+		"cursor":   setCursor(cursor),
 	}
 
+	// Encrypted fields are searched through their blind indexes
+	argsStr["search_exact"], argsStr["search_tokens"] = app.SearchIndexQuery(search)
+
 	// Integer type query params
 	offset := r.FormValue("Offset")
 	limit := r.FormValue("Limit")
@@ -36,6 +51,60 @@ func SetArgs(r *http.Request, fields []string) (map[string]string, map[string]in
 	return argsStr, argsInt
 }
 
+// setFavorite returns "true" or "false" to filter by the favorite flag,
+// empty when the value can't be parsed so no filter is applied
+func setFavorite(favorite string) string {
+	favoriteBool, err := strconv.ParseBool(favorite)
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatBool(favoriteBool)
+}
+
+// setFolder returns the id of the folder to filter by, "0" filters
+// the items which are not in any folder
+func setFolder(folder string) string {
+	folderID, err := strconv.ParseUint(folder, 10, 32)
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatUint(folderID, 10)
+}
+
+// setTags returns the valid tag ids in a comma separated list,
+// items having any of these tags are found
+func setTags(tags string) string {
+	tagIDs := []string{}
+	for _, tag := range strings.Split(tags, ",") {
+		tagID, err := strconv.ParseUint(strings.TrimSpace(tag), 10, 32)
+		if err != nil {
+			continue
+		}
+		tagIDs = append(tagIDs, strconv.FormatUint(tagID, 10))
+	}
+	return strings.Join(tagIDs, ",")
+}
+
+// setCursor returns the cursor of the page to find,
+// empty when it can't be decoded so the first page is found
+func setCursor(cursor string) string {
+	if _, err := pagination.Decode(cursor); err != nil {
+		return ""
+	}
+	return cursor
+}
+
+// setDate returns the date in RFC 3339 format, a day like 2020-12-31 starts
+// at midnight UTC. It's empty when the date can't be parsed so no filter is applied.
+func setDate(date string) string {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t.Format(time.RFC3339)
+		}
+	}
+	return ""
+}
+
 // Offset returns the starting number of result for pagination
 func setOffset(offset string) int {
 	offsetInt, err := strconv.Atoi(offset)
@@ -74,7 +143,22 @@ func setOrder(fields []string, sort, order string) string {
 		return ToSnakeCase(sort) + " " + ToSnakeCase(order)
 	}
 
-	return "updated_at desc"
+	return pagination.Order
+}
+
+// setPageHeaders sets the total count of a list and the cursor of its next
+// page. The cursor is only set for a full page in the default order.
+func setPageHeaders(w http.ResponseWriter, list interface{}, total int, argsStr map[string]string, argsInt map[string]int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+
+	items := reflect.ValueOf(list)
+	limit := argsInt["limit"]
+	if limit < 1 || items.Len() < limit || (argsStr["order"] != pagination.Order && argsStr["cursor"] == "") {
+		return
+	}
+	last := items.Index(items.Len() - 1)
+	updatedAt := last.FieldByName("UpdatedAt").Interface().(time.Time)
+	w.Header().Set("X-Next-Cursor", pagination.Encode(updatedAt, uint(last.FieldByName("ID").Uint())))
 }
 
 // include ...
@@ -129,3 +213,89 @@ func ToBody(r *http.Request, env, transmissionKey string) error {
 
 	return nil
 }
+
+// decodePayload reads the request body into dst and validates it
+func decodePayload(w http.ResponseWriter, r *http.Request, env, transmissionKey string, dst interface{}) bool {
+	if err := ToBody(r, env, transmissionKey); err != nil {
+		RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		return false
+	}
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		return false
+	}
+
+	if err := app.PayloadValidator(dst); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// respondWithItemError responds to an error of saving an item,
+// invalid custom fields, TOTPs and SSH keys are reported to the client
+func respondWithItemError(w http.ResponseWriter, err error) {
+	if errors.Is(err, app.ErrInvalidCustomField) || errors.Is(err, app.ErrInvalidTOTP) || errors.Is(err, app.ErrInvalidSSHKey) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	RespondWithError(w, http.StatusInternalServerError, err.Error())
+}
+
+// respondWithUpdateError responds with the error of an item update. When the
+// item was changed by another device it responds with the current item.
+func respondWithUpdateError(w http.ResponseWriter, transmissionKey string, err error) {
+	if conflict, ok := err.(*app.RevisionConflictError); ok {
+		setETag(w, conflict.Revision)
+		RespondWithEncJSON(w, http.StatusConflict, transmissionKey, conflict.Item)
+		return
+	}
+	respondWithItemError(w, err)
+}
+
+// setETag sets the ETag of the response to the revision of an item
+func setETag(w http.ResponseWriter, revision uint) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatUint(uint64(revision), 10)))
+}
+
+// errInvalidIfMatch represents message for an If-Match header which isn't a revision
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// ifMatch returns the revision in the If-Match header. It is zero when there
+// is no header or it is *, the update doesn't depend on the revision then.
+// Weak validators (W/"3") are taken as strong ones, a revision names a single
+// state of the item.
+func ifMatch(r *http.Request) (uint, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	value, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return 0, errInvalidIfMatch
+	}
+	revision, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || revision == 0 {
+		return 0, errInvalidIfMatch
+	}
+	return uint(revision), nil
+}
+
+// decodeList reads the list in the request body of a bulk request into dst,
+// failing items are reported one by one by the bulk operations
+func decodeList(w http.ResponseWriter, r *http.Request, env, transmissionKey string, dst interface{}) bool {
+	if err := ToBody(r, env, transmissionKey); err != nil {
+		RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		return false
+	}
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		return false
+	}
+	return true
+}
@@ -1,12 +1,21 @@
 package api_test
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/gorilla/mux"
 	"github.com/passwall/passwall-server/internal/api"
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
 )
 
 func TestToBody(t *testing.T) {
@@ -45,3 +54,136 @@ func TestToBody(t *testing.T) {
 	}
 
 }
+
+func TestSetArgsFilters(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/api/logins?Favorite=1&Folder=3&Tags=2,x,%205", nil)
+	argsStr, _ := api.SetArgs(r, []string{"id"})
+
+	if argsStr["favorite"] != "true" {
+		t.Errorf("favorite = %q, want true", argsStr["favorite"])
+	}
+	if argsStr["folder"] != "3" {
+		t.Errorf("folder = %q, want 3", argsStr["folder"])
+	}
+	if argsStr["tags"] != "2,5" {
+		t.Errorf("tags = %q, want 2,5", argsStr["tags"])
+	}
+
+	r, _ = http.NewRequest(http.MethodGet, "/api/logins?Favorite=maybe&Folder=-1", nil)
+	argsStr, _ = api.SetArgs(r, []string{"id"})
+	if argsStr["favorite"] != "" || argsStr["folder"] != "" || argsStr["tags"] != "" {
+		t.Errorf("invalid filters should be ignored, got %v", argsStr)
+	}
+}
+
+func TestFindAllPageHeaders(t *testing.T) {
+	viper.Set("server.env", "dev")
+	defer viper.Set("server.env", nil)
+
+	s := memory.New()
+	for _, title := range []string{"a", "b", "c"} {
+		if _, err := app.CreateLogin(s, &model.LoginDTO{Title: title}, "user1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	find := func(query string) (*httptest.ResponseRecorder, []string) {
+		r, _ := http.NewRequest(http.MethodGet, "/api/logins?"+query, nil)
+		ctx := context.WithValue(r.Context(), "transmissionKey", "")
+		ctx = context.WithValue(ctx, "schema", "user1")
+		w := httptest.NewRecorder()
+		api.FindAllLogins(s)(w, r.WithContext(ctx))
+
+		logins := []model.Login{}
+		if err := json.NewDecoder(w.Body).Decode(&logins); err != nil {
+			t.Fatal(err)
+		}
+		titles := []string{}
+		for _, login := range logins {
+			titles = append(titles, login.Title)
+		}
+		return w, titles
+	}
+
+	w, titles := find("Limit=2")
+	if total := w.Header().Get("X-Total-Count"); total != "3" {
+		t.Errorf("X-Total-Count = %q, want 3", total)
+	}
+	cursor := w.Header().Get("X-Next-Cursor")
+	if cursor == "" || strings.Join(titles, ",") != "c,b" {
+		t.Fatalf("first page = %v with cursor %q, want c,b with a cursor", titles, cursor)
+	}
+
+	w, titles = find("Limit=2&Cursor=" + cursor)
+	if strings.Join(titles, ",") != "a" {
+		t.Errorf("next page = %v, want a", titles)
+	}
+	if next := w.Header().Get("X-Next-Cursor"); next != "" {
+		t.Errorf("the last page shouldn't have a next cursor, got %q", next)
+	}
+
+	// lists in another order are paged by offset
+	w, _ = find("Limit=2&Sort=title&Order=asc")
+	if next := w.Header().Get("X-Next-Cursor"); next != "" {
+		t.Errorf("a list sorted by title shouldn't have a cursor, got %q", next)
+	}
+}
+
+func TestUpdateLoginIfMatch(t *testing.T) {
+	viper.Set("server.env", "dev")
+	defer viper.Set("server.env", nil)
+
+	s := memory.New()
+	login, err := app.CreateLogin(s, &model.LoginDTO{Title: "Mail", Password: "first"}, "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := func(ifMatch, password string) (*httptest.ResponseRecorder, *model.LoginDTO) {
+		body := `{"title":"Mail","password":"` + password + `"}`
+		r, _ := http.NewRequest(http.MethodPut, "/api/logins/"+fmt.Sprint(login.ID), strings.NewReader(body))
+		if ifMatch != "" {
+			r.Header.Set("If-Match", ifMatch)
+		}
+		r = mux.SetURLVars(r, map[string]string{"id": fmt.Sprint(login.ID)})
+		ctx := context.WithValue(r.Context(), "transmissionKey", "")
+		ctx = context.WithValue(ctx, "schema", "user1")
+		w := httptest.NewRecorder()
+		api.UpdateLogin(s)(w, r.WithContext(ctx))
+
+		dto := new(model.LoginDTO)
+		if w.Code == http.StatusOK || w.Code == http.StatusConflict {
+			if err := json.NewDecoder(w.Body).Decode(dto); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w, dto
+	}
+
+	w, dto := update(`"1"`, "second")
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"2"` || dto.Revision != 2 {
+		t.Fatalf("update from the current revision = %d with ETag %s, want 200 with ETag \"2\"", w.Code, w.Header().Get("ETag"))
+	}
+
+	// another device still has the first revision
+	w, dto = update(`"1"`, "third")
+	if w.Code != http.StatusConflict || w.Header().Get("ETag") != `"2"` {
+		t.Fatalf("update from a stale revision = %d with ETag %s, want 409 with ETag \"2\"", w.Code, w.Header().Get("ETag"))
+	}
+	if dto.Password != "second" {
+		t.Errorf("the conflict should carry the current login, got password %q", dto.Password)
+	}
+
+	if w, _ = update("*", "third"); w.Code != http.StatusOK {
+		t.Errorf("update with If-Match * = %d, want 200", w.Code)
+	}
+	if w, _ = update("", "fourth"); w.Code != http.StatusOK {
+		t.Errorf("update without If-Match = %d, want 200", w.Code)
+	}
+	if w, _ = update(`W/"4"`, "fifth"); w.Code != http.StatusOK || w.Header().Get("ETag") != `"5"` {
+		t.Errorf("update with a weak If-Match = %d with ETag %s, want 200 with ETag \"5\"", w.Code, w.Header().Get("ETag"))
+	}
+	if w, _ = update("W/5", "sixth"); w.Code != http.StatusBadRequest {
+		t.Errorf("update with an invalid If-Match = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,303 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+
+	"github.com/gorilla/mux"
+)
+
+// FindAllIdentities ...
+func FindAllIdentities(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		identityList := []model.Identity{}
+
+		// Setup variables
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		fields := []string{"id", "created_at", "updated_at", "title"}
+		argsStr, argsInt := SetArgs(r, fields)
+
+		schema := r.Context().Value("schema").(string)
+		identityList, err = s.Identities().FindAll(argsStr, argsInt, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		total, err := s.Identities().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, identityList, total, argsStr, argsInt)
+
+		// Decrypt server side encrypted fields
+		for i := range identityList {
+			decIdentity, err := app.DecryptModel(&identityList[i])
+			if err != nil {
+				RespondWithError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			identityList[i] = *decIdentity.(*model.Identity)
+		}
+
+		if err := app.LoadTags(s, model.IdentityItem, identityList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, identityList)
+	}
+}
+
+// FindIdentityByID ...
+func FindIdentityByID(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Setup variables
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		// Check if id is integer
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		identity, err := s.Identities().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if err := app.LoadTags(s, model.IdentityItem, identity, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decIdentity, err := app.DecryptModel(identity)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		identityDTO := model.ToIdentityDTO(decIdentity.(*model.Identity))
+
+		setETag(w, identityDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, identityDTO)
+	}
+}
+
+// CreateIdentity ...
+func CreateIdentity(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		// Update request body according to env.
+		// If env is dev, then do nothing
+		// If env is prod, then decrypt payload with transmission key
+		if err := ToBody(r, env, transmissionKey); err != nil {
+			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+			return
+		}
+		defer r.Body.Close()
+
+		// Unmarshal request body to identityDTO
+		var identityDTO model.IdentityDTO
+		decoder := json.NewDecoder(r.Body)
+		if err := decoder.Decode(&identityDTO); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid resquest payload")
+			return
+		}
+		defer r.Body.Close()
+
+		// Add new identity to db
+		schema := r.Context().Value("schema").(string)
+		createdIdentity, err := app.CreateIdentity(s, &identityDTO, schema)
+		if err != nil {
+			respondWithItemError(w, err)
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decIdentity, err := app.DecryptModel(createdIdentity)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Create DTO
+		createdIdentityDTO := model.ToIdentityDTO(decIdentity.(*model.Identity))
+
+		setETag(w, createdIdentityDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdIdentityDTO)
+	}
+}
+
+// UpdateIdentity ...
+func UpdateIdentity(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		if err := ToBody(r, env, transmissionKey); err != nil {
+			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+			return
+		}
+		defer r.Body.Close()
+
+		// Unmarshal request body to identityDTO
+		var identityDTO model.IdentityDTO
+		decoder := json.NewDecoder(r.Body)
+		if err := decoder.Decode(&identityDTO); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid resquest payload")
+			return
+		}
+		defer r.Body.Close()
+
+		// The update only depends on the revision of the If-Match header
+		identityDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Find identity defined by id
+		schema := r.Context().Value("schema").(string)
+		identity, err := s.Identities().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// Update identity
+		updatedIdentity, err := app.UpdateIdentity(s, identity, &identityDTO, schema)
+		if err != nil {
+			respondWithUpdateError(w, transmissionKey, err)
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decIdentity, err := app.DecryptModel(updatedIdentity)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Create DTO
+		updatedIdentityDTO := model.ToIdentityDTO(decIdentity.(*model.Identity))
+
+		setETag(w, updatedIdentityDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedIdentityDTO)
+
+	}
+}
+
+// BulkCreateIdentities creates the identities in the payload, all of them or none
+func BulkCreateIdentities(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var identityList []model.IdentityDTO
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &identityList) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkCreateIdentities(s, identityList, schema)
+		respondWithBulkResults(w, "Bulk create", results, err)
+	}
+}
+
+// BulkUpdateIdentities updates the identities in the payload, all of them or none
+func BulkUpdateIdentities(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var identityList []model.IdentityDTO
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &identityList) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkUpdateIdentities(s, identityList, schema)
+		respondWithBulkResults(w, "Bulk update", results, err)
+	}
+}
+
+// BulkDeleteIdentities moves the identities with the ids in the payload to the trash,
+// all of them or none
+func BulkDeleteIdentities(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ids []uint
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &ids) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkDeleteIdentities(s, ids, schema)
+		respondWithBulkResults(w, "Bulk delete", results, err)
+	}
+}
+
+// DeleteIdentity ...
+func DeleteIdentity(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		identity, err := s.Identities().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		err = app.DeleteItem(s, model.IdentityItem, identity.ID, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  "Success",
+			Message: "Identity deleted successfully!",
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,305 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+
+	"github.com/gorilla/mux"
+)
+
+// FindAllLicenseKeys ...
+func FindAllLicenseKeys(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		licenseKeyList := []model.LicenseKey{}
+
+		// Setup variables
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		fields := []string{"id", "created_at", "updated_at", "product", "seats", "purchase_date", "expires_at"}
+		argsStr, argsInt := SetArgs(r, fields)
+		argsStr["expires_after"] = setDate(r.FormValue("ExpiresAfter"))
+		argsStr["expires_before"] = setDate(r.FormValue("ExpiresBefore"))
+
+		schema := r.Context().Value("schema").(string)
+		licenseKeyList, err = s.LicenseKeys().FindAll(argsStr, argsInt, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		total, err := s.LicenseKeys().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, licenseKeyList, total, argsStr, argsInt)
+
+		// Decrypt server side encrypted fields
+		for i := range licenseKeyList {
+			decLicenseKey, err := app.DecryptModel(&licenseKeyList[i])
+			if err != nil {
+				RespondWithError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			licenseKeyList[i] = *decLicenseKey.(*model.LicenseKey)
+		}
+
+		if err := app.LoadTags(s, model.LicenseKeyItem, licenseKeyList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, licenseKeyList)
+	}
+}
+
+// FindLicenseKeyByID ...
+func FindLicenseKeyByID(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Setup variables
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		// Check if id is integer
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		licenseKey, err := s.LicenseKeys().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if err := app.LoadTags(s, model.LicenseKeyItem, licenseKey, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decLicenseKey, err := app.DecryptModel(licenseKey)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		licenseKeyDTO := model.ToLicenseKeyDTO(decLicenseKey.(*model.LicenseKey))
+
+		setETag(w, licenseKeyDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, licenseKeyDTO)
+	}
+}
+
+// CreateLicenseKey ...
+func CreateLicenseKey(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		// Update request body according to env.
+		// If env is dev, then do nothing
+		// If env is prod, then decrypt payload with transmission key
+		if err := ToBody(r, env, transmissionKey); err != nil {
+			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+			return
+		}
+		defer r.Body.Close()
+
+		// Unmarshal request body to license keyDTO
+		var licenseKeyDTO model.LicenseKeyDTO
+		decoder := json.NewDecoder(r.Body)
+		if err := decoder.Decode(&licenseKeyDTO); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid resquest payload")
+			return
+		}
+		defer r.Body.Close()
+
+		// Add new license key to db
+		schema := r.Context().Value("schema").(string)
+		createdLicenseKey, err := app.CreateLicenseKey(s, &licenseKeyDTO, schema)
+		if err != nil {
+			respondWithItemError(w, err)
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decLicenseKey, err := app.DecryptModel(createdLicenseKey)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Create DTO
+		createdLicenseKeyDTO := model.ToLicenseKeyDTO(decLicenseKey.(*model.LicenseKey))
+
+		setETag(w, createdLicenseKeyDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdLicenseKeyDTO)
+	}
+}
+
+// UpdateLicenseKey ...
+func UpdateLicenseKey(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		if err := ToBody(r, env, transmissionKey); err != nil {
+			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+			return
+		}
+		defer r.Body.Close()
+
+		// Unmarshal request body to license keyDTO
+		var licenseKeyDTO model.LicenseKeyDTO
+		decoder := json.NewDecoder(r.Body)
+		if err := decoder.Decode(&licenseKeyDTO); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid resquest payload")
+			return
+		}
+		defer r.Body.Close()
+
+		// The update only depends on the revision of the If-Match header
+		licenseKeyDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Find license key defined by id
+		schema := r.Context().Value("schema").(string)
+		licenseKey, err := s.LicenseKeys().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// Update license key
+		updatedLicenseKey, err := app.UpdateLicenseKey(s, licenseKey, &licenseKeyDTO, schema)
+		if err != nil {
+			respondWithUpdateError(w, transmissionKey, err)
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decLicenseKey, err := app.DecryptModel(updatedLicenseKey)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Create DTO
+		updatedLicenseKeyDTO := model.ToLicenseKeyDTO(decLicenseKey.(*model.LicenseKey))
+
+		setETag(w, updatedLicenseKeyDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedLicenseKeyDTO)
+
+	}
+}
+
+// BulkCreateLicenseKeys creates the license keys in the payload, all of them or none
+func BulkCreateLicenseKeys(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var licenseKeyList []model.LicenseKeyDTO
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &licenseKeyList) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkCreateLicenseKeys(s, licenseKeyList, schema)
+		respondWithBulkResults(w, "Bulk create", results, err)
+	}
+}
+
+// BulkUpdateLicenseKeys updates the license keys in the payload, all of them or none
+func BulkUpdateLicenseKeys(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var licenseKeyList []model.LicenseKeyDTO
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &licenseKeyList) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkUpdateLicenseKeys(s, licenseKeyList, schema)
+		respondWithBulkResults(w, "Bulk update", results, err)
+	}
+}
+
+// BulkDeleteLicenseKeys moves the license keys with the ids in the payload to the trash,
+// all of them or none
+func BulkDeleteLicenseKeys(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ids []uint
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &ids) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkDeleteLicenseKeys(s, ids, schema)
+		respondWithBulkResults(w, "Bulk delete", results, err)
+	}
+}
+
+// DeleteLicenseKey ...
+func DeleteLicenseKey(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		licenseKey, err := s.LicenseKeys().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		err = app.DeleteItem(s, model.LicenseKeyItem, licenseKey.ID, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  "Success",
+			Message: "LicenseKey deleted successfully!",
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/passwall/passwall-server/internal/app"
 	"github.com/passwall/passwall-server/internal/storage"
@@ -37,6 +38,13 @@ func FindAllLogins(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		total, err := s.Logins().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, loginList, total, argsStr, argsInt)
+
 		// Decrypt server side encrypted fields
 		for i := range loginList {
 			uLogin, err := app.DecryptModel(&loginList[i])
@@ -47,6 +55,11 @@ func FindAllLogins(s storage.Store) http.HandlerFunc {
 			loginList[i] = *uLogin.(*model.Login)
 		}
 
+		if err := app.LoadTags(s, model.LoginItem, loginList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, loginList)
 	}
 }
@@ -74,6 +87,11 @@ func FindLoginsByID(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		if err := app.LoadTags(s, model.LoginItem, login, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		// Decrypt server side encrypted fields
 		uLogin, err := app.DecryptModel(login)
 		if err != nil {
@@ -84,10 +102,53 @@ func FindLoginsByID(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		loginDTO := model.ToLoginDTO(uLogin.(*model.Login))
 
+		setETag(w, loginDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, loginDTO)
 	}
 }
 
+// FindLoginTOTP returns the current TOTP code of a login with the seconds it is valid for
+func FindLoginTOTP(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		login, err := s.Logins().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		uLogin, err := app.DecryptModel(login)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		code, err := app.TOTPCode(uLogin.(*model.Login).TOTP, time.Now())
+		if err == app.ErrNoTOTP {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// The code changes every period, it shouldn't be cached
+		w.Header().Set("Cache-Control", "no-store")
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, code)
+	}
+}
+
 // CreateLogin creates a login
 func CreateLogin(s storage.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -118,7 +179,7 @@ func CreateLogin(s storage.Store) http.HandlerFunc {
 		schema := r.Context().Value("schema").(string)
 		createdLogin, err := app.CreateLogin(s, &loginDTO, schema)
 		if err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithItemError(w, err)
 			return
 		}
 
@@ -132,6 +193,7 @@ func CreateLogin(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		createdLoginDTO := model.ToLoginDTO(decLogin.(*model.Login))
 
+		setETag(w, createdLoginDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdLoginDTO)
 	}
 }
@@ -165,6 +227,13 @@ func UpdateLogin(s storage.Store) http.HandlerFunc {
 		}
 		defer r.Body.Close()
 
+		// The update only depends on the revision of the If-Match header
+		loginDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		// Find login defined by id
 		schema := r.Context().Value("schema").(string)
 		login, err := s.Logins().FindByID(uint(id), schema)
@@ -176,7 +245,7 @@ func UpdateLogin(s storage.Store) http.HandlerFunc {
 		// Update login
 		updatedLogin, err := app.UpdateLogin(s, login, &loginDTO, schema)
 		if err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithUpdateError(w, transmissionKey, err)
 			return
 		}
 
@@ -190,54 +259,63 @@ func UpdateLogin(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		updatedLoginDTO := model.ToLoginDTO(decLogin.(*model.Login))
 
+		setETag(w, updatedLoginDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedLoginDTO)
 	}
 }
 
-// BulkUpdateLogins updates logins in payload
-func BulkUpdateLogins(s storage.Store) http.HandlerFunc {
+// BulkCreateLogins creates the logins in the payload, all of them or none
+func BulkCreateLogins(s storage.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var loginList []model.LoginDTO
-		// var loginDTO model.LoginDTO
 
 		// Setup variables
 		env := viper.GetString("server.env")
 		transmissionKey := r.Context().Value("transmissionKey").(string)
-		if err := ToBody(r, env, transmissionKey); err != nil {
-			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		if !decodeList(w, r, env, transmissionKey, &loginList) {
 			return
 		}
-		defer r.Body.Close()
 
-		decoder := json.NewDecoder(r.Body)
-		if err := decoder.Decode(&loginList); err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
-		defer r.Body.Close()
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkCreateLogins(s, loginList, schema)
+		respondWithBulkResults(w, "Bulk create", results, err)
+	}
+}
 
-		for _, loginDTO := range loginList {
-			// Find login defined by id
-			schema := r.Context().Value("schema").(string)
-			login, err := s.Logins().FindByID(loginDTO.ID, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusNotFound, err.Error())
-				return
-			}
+// BulkUpdateLogins updates the logins in the payload, all of them or none
+func BulkUpdateLogins(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var loginList []model.LoginDTO
 
-			// Update login
-			_, err = app.UpdateLogin(s, login, &loginDTO, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusInternalServerError, err.Error())
-				return
-			}
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &loginList) {
+			return
 		}
 
-		response := model.Response{
-			Code:    http.StatusOK,
-			Status:  "Success",
-			Message: "Bulk update completed successfully!",
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkUpdateLogins(s, loginList, schema)
+		respondWithBulkResults(w, "Bulk update", results, err)
+	}
+}
+
+// BulkDeleteLogins moves the logins with the ids in the payload to the trash,
+// all of them or none
+func BulkDeleteLogins(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ids []uint
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &ids) {
+			return
 		}
-		RespondWithJSON(w, http.StatusOK, response)
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkDeleteLogins(s, ids, schema)
+		respondWithBulkResults(w, "Bulk delete", results, err)
 	}
 }
 
@@ -260,7 +338,7 @@ func DeleteLogin(s storage.Store) http.HandlerFunc {
 		}
 
 		// Delete login defined by id
-		err = s.Logins().Delete(login.ID, schema)
+		err = app.DeleteItem(s, model.LoginItem, login.ID, schema)
 		if err != nil {
 			RespondWithError(w, http.StatusNotFound, err.Error())
 			return
@@ -15,8 +15,7 @@ import (
 
 func (suite *TestSuiteEnv) TestFindAllLogins() {
 
-	// db := suite.db
-	gorm := suite.gorm
+	db := suite.db
 
 	req, err := http.NewRequest("GET", "/api/logins", nil)
 	if err != nil {
@@ -26,7 +25,7 @@ func (suite *TestSuiteEnv) TestFindAllLogins() {
 	w := httptest.NewRecorder()
 	// handler := FindAllLogins(db)
 
-	r := routersSetup(gorm)
+	r := routersSetup(db)
 
 	r.ServeHTTP(w, req)
 	// more test cases could be added
@@ -157,10 +156,7 @@ func dbSetup() (*gorm.DB, sqlmock.Sqlmock) {
 	return DB, mock
 }
 
-func routersSetup(db *gorm.DB) *mux.Router {
-
-	// Create storage with mock db
-	store := storage.New(db)
+func routersSetup(store storage.Store) *mux.Router {
 
 	// Initialize router
 	apiRouter := mux.NewRouter().PathPrefix("/api").Subrouter()
@@ -37,6 +37,13 @@ func FindAllNotes(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		total, err := s.Notes().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, noteList, total, argsStr, argsInt)
+
 		// Decrypt server side encrypted fields
 		for i := range noteList {
 			uNote, err := app.DecryptModel(&noteList[i])
@@ -47,6 +54,11 @@ func FindAllNotes(s storage.Store) http.HandlerFunc {
 			noteList[i] = *uNote.(*model.Note)
 		}
 
+		if err := app.LoadTags(s, model.NoteItem, noteList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, noteList)
 	}
 }
@@ -74,6 +86,11 @@ func FindNoteByID(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		if err := app.LoadTags(s, model.NoteItem, note, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		// Decrypt server side encrypted fields
 		uNote, err := app.DecryptModel(note)
 		if err != nil {
@@ -84,6 +101,7 @@ func FindNoteByID(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		noteDTO := model.ToNoteDTO(uNote.(*model.Note))
 
+		setETag(w, noteDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, noteDTO)
 	}
 }
@@ -118,7 +136,7 @@ func CreateNote(s storage.Store) http.HandlerFunc {
 		schema := r.Context().Value("schema").(string)
 		createdNote, err := app.CreateNote(s, &noteDTO, schema)
 		if err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithItemError(w, err)
 			return
 		}
 
@@ -132,6 +150,7 @@ func CreateNote(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		createdNoteDTO := model.ToNoteDTO(decNote.(*model.Note))
 
+		setETag(w, createdNoteDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdNoteDTO)
 	}
 }
@@ -165,6 +184,13 @@ func UpdateNote(s storage.Store) http.HandlerFunc {
 		}
 		defer r.Body.Close()
 
+		// The update only depends on the revision of the If-Match header
+		noteDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		// Find note defined by id
 		schema := r.Context().Value("schema").(string)
 		note, err := s.Notes().FindByID(uint(id), schema)
@@ -176,7 +202,7 @@ func UpdateNote(s storage.Store) http.HandlerFunc {
 		// Update note
 		updatedNote, err := app.UpdateNote(s, note, &noteDTO, schema)
 		if err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithUpdateError(w, transmissionKey, err)
 			return
 		}
 
@@ -190,53 +216,63 @@ func UpdateNote(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		updatedNoteDTO := model.ToNoteDTO(decNote.(*model.Note))
 
+		setETag(w, updatedNoteDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedNoteDTO)
 	}
 }
 
-// BulkUpdateNotes updates notes in payload
-func BulkUpdateNotes(s storage.Store) http.HandlerFunc {
+// BulkCreateNotes creates the notes in the payload, all of them or none
+func BulkCreateNotes(s storage.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var noteList []model.NoteDTO
 
 		// Setup variables
 		env := viper.GetString("server.env")
 		transmissionKey := r.Context().Value("transmissionKey").(string)
-		if err := ToBody(r, env, transmissionKey); err != nil {
-			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		if !decodeList(w, r, env, transmissionKey, &noteList) {
 			return
 		}
-		defer r.Body.Close()
 
-		decoder := json.NewDecoder(r.Body)
-		if err := decoder.Decode(&noteList); err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
-		defer r.Body.Close()
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkCreateNotes(s, noteList, schema)
+		respondWithBulkResults(w, "Bulk create", results, err)
+	}
+}
 
-		for _, noteDTO := range noteList {
-			// Find note defined by id
-			schema := r.Context().Value("schema").(string)
-			note, err := s.Notes().FindByID(noteDTO.ID, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusNotFound, err.Error())
-				return
-			}
+// BulkUpdateNotes updates the notes in the payload, all of them or none
+func BulkUpdateNotes(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var noteList []model.NoteDTO
 
-			// Update note
-			_, err = app.UpdateNote(s, note, &noteDTO, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusInternalServerError, err.Error())
-				return
-			}
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &noteList) {
+			return
 		}
 
-		response := model.Response{
-			Code:    http.StatusOK,
-			Status:  "Success",
-			Message: "Bulk update completed successfully!",
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkUpdateNotes(s, noteList, schema)
+		respondWithBulkResults(w, "Bulk update", results, err)
+	}
+}
+
+// BulkDeleteNotes moves the notes with the ids in the payload to the trash,
+// all of them or none
+func BulkDeleteNotes(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ids []uint
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &ids) {
+			return
 		}
-		RespondWithJSON(w, http.StatusOK, response)
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkDeleteNotes(s, ids, schema)
+		respondWithBulkResults(w, "Bulk delete", results, err)
 	}
 }
 
@@ -257,7 +293,7 @@ func DeleteNote(s storage.Store) http.HandlerFunc {
 			return
 		}
 
-		err = s.Notes().Delete(note.ID, schema)
+		err = app.DeleteItem(s, model.NoteItem, note.ID, schema)
 		if err != nil {
 			RespondWithError(w, http.StatusNotFound, err.Error())
 			return
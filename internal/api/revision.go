@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+)
+
+// FindRevisions lists the revisions of an item
+func FindRevisions(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		revisions, err := app.FindRevisions(s, vars["type"], uint(id), schema)
+		if err != nil {
+			respondWithRevisionError(w, err)
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, revisions)
+	}
+}
+
+// FindRevision returns a revision of an item with the item as it was
+func FindRevision(s storage.Store) http.HandlerFunc {
+	return revisionHandler(func(s storage.Store, itemType string, id, revisionID uint, schema string) (interface{}, error) {
+		return app.FindRevision(s, itemType, id, revisionID, schema)
+	})(s)
+}
+
+// DiffRevision lists the fields of an item which changed since the revision
+func DiffRevision(s storage.Store) http.HandlerFunc {
+	return revisionHandler(func(s storage.Store, itemType string, id, revisionID uint, schema string) (interface{}, error) {
+		return app.DiffRevision(s, itemType, id, revisionID, schema)
+	})(s)
+}
+
+// RestoreRevision restores an item to the revision
+func RestoreRevision(s storage.Store) http.HandlerFunc {
+	return revisionHandler(app.RestoreRevision)(s)
+}
+
+type revisionFunc func(s storage.Store, itemType string, id, revisionID uint, schema string) (interface{}, error)
+
+// revisionHandler runs fn for the item and revision in the path
+// and responds with the encrypted result
+func revisionHandler(fn revisionFunc) func(s storage.Store) http.HandlerFunc {
+	return func(s storage.Store) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			transmissionKey := r.Context().Value("transmissionKey").(string)
+			schema := r.Context().Value("schema").(string)
+
+			vars := mux.Vars(r)
+			id, err := strconv.Atoi(vars["id"])
+			if err != nil {
+				RespondWithError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			revisionID, err := strconv.Atoi(vars["revision"])
+			if err != nil {
+				RespondWithError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			result, err := fn(s, vars["type"], uint(id), uint(revisionID), schema)
+			if err != nil {
+				respondWithRevisionError(w, err)
+				return
+			}
+
+			RespondWithEncJSON(w, http.StatusOK, transmissionKey, result)
+		}
+	}
+}
+
+func respondWithRevisionError(w http.ResponseWriter, err error) {
+	switch {
+	case err == app.ErrUnknownItemType:
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	case err == app.ErrRevisionNotFound, gorm.IsRecordNotFoundError(err):
+		RespondWithError(w, http.StatusNotFound, err.Error())
+	default:
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
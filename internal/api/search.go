@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+)
+
+// Search finds the items of every type matching the search
+func Search(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		argsStr, argsInt := SetArgs(r, nil)
+
+		hits, total, err := app.Search(s, argsStr, argsInt, schema)
+		if err == app.ErrEmptySearch {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, hits)
+	}
+}
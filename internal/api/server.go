@@ -38,6 +38,13 @@ func FindAllServers(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		total, err := s.Servers().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, serverList, total, argsStr, argsInt)
+
 		// Decrypt server side encrypted fields
 		for i := range serverList {
 			decServer, err := app.DecryptModel(&serverList[i])
@@ -48,6 +55,11 @@ func FindAllServers(s storage.Store) http.HandlerFunc {
 			serverList[i] = *decServer.(*model.Server)
 		}
 
+		if err := app.LoadTags(s, model.ServerItem, serverList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, serverList)
 	}
 }
@@ -75,6 +87,11 @@ func FindServerByID(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		if err := app.LoadTags(s, model.ServerItem, server, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		// Decrypt server side encrypted fields
 		decServer, err := app.DecryptModel(server)
 		if err != nil {
@@ -84,6 +101,7 @@ func FindServerByID(s storage.Store) http.HandlerFunc {
 
 		serverDTO := model.ToServerDTO(decServer.(*model.Server))
 
+		setETag(w, serverDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, serverDTO)
 	}
 }
@@ -118,7 +136,7 @@ func CreateServer(s storage.Store) http.HandlerFunc {
 		schema := r.Context().Value("schema").(string)
 		createdServer, err := app.CreateServer(s, &serverDTO, schema)
 		if err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithItemError(w, err)
 			return
 		}
 		// Decrypt server side encrypted fields
@@ -131,6 +149,7 @@ func CreateServer(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		createdServerDTO := model.ToServerDTO(decServer.(*model.Server))
 
+		setETag(w, createdServerDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdServerDTO)
 	}
 }
@@ -164,6 +183,13 @@ func UpdateServer(s storage.Store) http.HandlerFunc {
 		}
 		defer r.Body.Close()
 
+		// The update only depends on the revision of the If-Match header
+		serverDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		// Find server defined by id
 		schema := r.Context().Value("schema").(string)
 		server, err := s.Servers().FindByID(uint(id), schema)
@@ -175,7 +201,7 @@ func UpdateServer(s storage.Store) http.HandlerFunc {
 		// Update server
 		updatedServer, err := app.UpdateServer(s, server, &serverDTO, schema)
 		if err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithUpdateError(w, transmissionKey, err)
 			return
 		}
 
@@ -189,53 +215,63 @@ func UpdateServer(s storage.Store) http.HandlerFunc {
 		// Create DTO
 		updatedServerDTO := model.ToServerDTO(decServer.(*model.Server))
 
+		setETag(w, updatedServerDTO.Revision)
 		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedServerDTO)
 	}
 }
 
-// BulkUpdateServers updates servers in payload
-func BulkUpdateServers(s storage.Store) http.HandlerFunc {
+// BulkCreateServers creates the servers in the payload, all of them or none
+func BulkCreateServers(s storage.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var serverList []model.ServerDTO
 
 		// Setup variables
 		env := viper.GetString("server.env")
 		transmissionKey := r.Context().Value("transmissionKey").(string)
-		if err := ToBody(r, env, transmissionKey); err != nil {
-			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+		if !decodeList(w, r, env, transmissionKey, &serverList) {
 			return
 		}
-		defer r.Body.Close()
 
-		decoder := json.NewDecoder(r.Body)
-		if err := decoder.Decode(&serverList); err != nil {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
-		defer r.Body.Close()
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkCreateServers(s, serverList, schema)
+		respondWithBulkResults(w, "Bulk create", results, err)
+	}
+}
 
-		for _, serverDTO := range serverList {
-			// Find server defined by id
-			schema := r.Context().Value("schema").(string)
-			server, err := s.Servers().FindByID(serverDTO.ID, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusNotFound, err.Error())
-				return
-			}
+// BulkUpdateServers updates the servers in the payload, all of them or none
+func BulkUpdateServers(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var serverList []model.ServerDTO
 
-			// Update server
-			_, err = app.UpdateServer(s, server, &serverDTO, schema)
-			if err != nil {
-				RespondWithError(w, http.StatusInternalServerError, err.Error())
-				return
-			}
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &serverList) {
+			return
 		}
 
-		response := model.Response{
-			Code:    http.StatusOK,
-			Status:  "Success",
-			Message: "Bulk update completed successfully!",
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkUpdateServers(s, serverList, schema)
+		respondWithBulkResults(w, "Bulk update", results, err)
+	}
+}
+
+// BulkDeleteServers moves the servers with the ids in the payload to the trash,
+// all of them or none
+func BulkDeleteServers(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ids []uint
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &ids) {
+			return
 		}
-		RespondWithJSON(w, http.StatusOK, response)
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkDeleteServers(s, ids, schema)
+		respondWithBulkResults(w, "Bulk delete", results, err)
 	}
 }
 
@@ -256,7 +292,7 @@ func DeleteServer(s storage.Store) http.HandlerFunc {
 			return
 		}
 
-		err = s.Servers().Delete(server.ID, schema)
+		err = app.DeleteItem(s, model.ServerItem, server.ID, schema)
 		if err != nil {
 			RespondWithError(w, http.StatusNotFound, err.Error())
 			return
@@ -0,0 +1,334 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+
+	"github.com/gorilla/mux"
+)
+
+// FindAllSSHKeys ...
+func FindAllSSHKeys(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		sshKeyList := []model.SSHKey{}
+
+		// Setup variables
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		fields := []string{"id", "created_at", "updated_at", "title"}
+		argsStr, argsInt := SetArgs(r, fields)
+
+		schema := r.Context().Value("schema").(string)
+		sshKeyList, err = s.SSHKeys().FindAll(argsStr, argsInt, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		total, err := s.SSHKeys().Count(argsStr, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, sshKeyList, total, argsStr, argsInt)
+
+		// Decrypt server side encrypted fields
+		for i := range sshKeyList {
+			decSSHKey, err := app.DecryptModel(&sshKeyList[i])
+			if err != nil {
+				RespondWithError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			sshKeyList[i] = *decSSHKey.(*model.SSHKey)
+		}
+
+		if err := app.LoadTags(s, model.SSHKeyItem, sshKeyList, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, sshKeyList)
+	}
+}
+
+// FindSSHKeyByID ...
+func FindSSHKeyByID(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Setup variables
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		// Check if id is integer
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		sshKey, err := s.SSHKeys().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if err := app.LoadTags(s, model.SSHKeyItem, sshKey, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decSSHKey, err := app.DecryptModel(sshKey)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		sshKeyDTO := model.ToSSHKeyDTO(decSSHKey.(*model.SSHKey))
+
+		setETag(w, sshKeyDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, sshKeyDTO)
+	}
+}
+
+// CreateSSHKey ...
+func CreateSSHKey(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		// Update request body according to env.
+		// If env is dev, then do nothing
+		// If env is prod, then decrypt payload with transmission key
+		if err := ToBody(r, env, transmissionKey); err != nil {
+			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+			return
+		}
+		defer r.Body.Close()
+
+		// Unmarshal request body to SSH keyDTO
+		var sshKeyDTO model.SSHKeyDTO
+		decoder := json.NewDecoder(r.Body)
+		if err := decoder.Decode(&sshKeyDTO); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid resquest payload")
+			return
+		}
+		defer r.Body.Close()
+
+		// Add new SSH key to db
+		schema := r.Context().Value("schema").(string)
+		createdSSHKey, err := app.CreateSSHKey(s, &sshKeyDTO, schema)
+		if err != nil {
+			respondWithItemError(w, err)
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decSSHKey, err := app.DecryptModel(createdSSHKey)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Create DTO
+		createdSSHKeyDTO := model.ToSSHKeyDTO(decSSHKey.(*model.SSHKey))
+
+		setETag(w, createdSSHKeyDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdSSHKeyDTO)
+	}
+}
+
+// GenerateSSHKey generates a key pair and saves it as a new SSH key
+func GenerateSSHKey(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		var generateDTO model.SSHKeyGenerateDTO
+		if !decodePayload(w, r, env, transmissionKey, &generateDTO) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		createdSSHKey, err := app.GenerateSSHKey(s, &generateDTO, schema)
+		if err != nil {
+			respondWithItemError(w, err)
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decSSHKey, err := app.DecryptModel(createdSSHKey)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		createdSSHKeyDTO := model.ToSSHKeyDTO(decSSHKey.(*model.SSHKey))
+		setETag(w, createdSSHKeyDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, createdSSHKeyDTO)
+	}
+}
+
+// UpdateSSHKey ...
+func UpdateSSHKey(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		if err := ToBody(r, env, transmissionKey); err != nil {
+			RespondWithError(w, http.StatusBadRequest, InvalidRequestPayload)
+			return
+		}
+		defer r.Body.Close()
+
+		// Unmarshal request body to SSH keyDTO
+		var sshKeyDTO model.SSHKeyDTO
+		decoder := json.NewDecoder(r.Body)
+		if err := decoder.Decode(&sshKeyDTO); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid resquest payload")
+			return
+		}
+		defer r.Body.Close()
+
+		// The update only depends on the revision of the If-Match header
+		sshKeyDTO.Revision, err = ifMatch(r)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Find SSH key defined by id
+		schema := r.Context().Value("schema").(string)
+		sshKey, err := s.SSHKeys().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// Update SSH key
+		updatedSSHKey, err := app.UpdateSSHKey(s, sshKey, &sshKeyDTO, schema)
+		if err != nil {
+			respondWithUpdateError(w, transmissionKey, err)
+			return
+		}
+
+		// Decrypt server side encrypted fields
+		decSSHKey, err := app.DecryptModel(updatedSSHKey)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Create DTO
+		updatedSSHKeyDTO := model.ToSSHKeyDTO(decSSHKey.(*model.SSHKey))
+
+		setETag(w, updatedSSHKeyDTO.Revision)
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, updatedSSHKeyDTO)
+
+	}
+}
+
+// BulkCreateSSHKeys creates the SSH keys in the payload, all of them or none
+func BulkCreateSSHKeys(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var sshKeyList []model.SSHKeyDTO
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &sshKeyList) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkCreateSSHKeys(s, sshKeyList, schema)
+		respondWithBulkResults(w, "Bulk create", results, err)
+	}
+}
+
+// BulkUpdateSSHKeys updates the SSH keys in the payload, all of them or none
+func BulkUpdateSSHKeys(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var sshKeyList []model.SSHKeyDTO
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &sshKeyList) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkUpdateSSHKeys(s, sshKeyList, schema)
+		respondWithBulkResults(w, "Bulk update", results, err)
+	}
+}
+
+// BulkDeleteSSHKeys moves the SSH keys with the ids in the payload to the trash,
+// all of them or none
+func BulkDeleteSSHKeys(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ids []uint
+
+		// Setup variables
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		if !decodeList(w, r, env, transmissionKey, &ids) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		results, err := app.BulkDeleteSSHKeys(s, ids, schema)
+		respondWithBulkResults(w, "Bulk delete", results, err)
+	}
+}
+
+// DeleteSSHKey ...
+func DeleteSSHKey(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		sshKey, err := s.SSHKeys().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		err = app.DeleteItem(s, model.SSHKeyItem, sshKey.ID, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  "Success",
+			Message: "SSHKey deleted successfully!",
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
@@ -87,6 +87,13 @@ func FindAllSubscriptions(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		total, err := s.Subscriptions().Count(argsStr)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, subscriptionList, total, argsStr, argsInt)
+
 		// Encrypt payload
 		var payload model.Payload
 		key := r.Context().Value("transmissionKey").(string)
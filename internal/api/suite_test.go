@@ -1,13 +1,11 @@
 package api
 
 import (
-	"log"
 	"testing"
 
-	"github.com/jinzhu/gorm"
 	"github.com/passwall/passwall-server/internal/app"
-	"github.com/passwall/passwall-server/internal/config"
 	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/memory"
 	"github.com/passwall/passwall-server/model"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
@@ -15,8 +13,7 @@ import (
 
 type TestSuiteEnv struct {
 	suite.Suite
-	db   *storage.Database
-	gorm *gorm.DB
+	db storage.Store
 	// conf *config.Configuration
 }
 
@@ -25,27 +22,11 @@ func (suite *TestSuiteEnv) SetupSuite() {
 
 	// 1. Set config env variable to "dev"
 	viper.Set("server.env", "dev")
+	viper.Set("server.generatedPasswordLength", 16)
 
-	// 2. Set mock db credentials
-	mockDBConfig := &config.DatabaseConfiguration{
-		Name:     "passwall",
-		Username: "postgres",
-		Password: "postgres",
-		Host:     "localhost",
-		Port:     "5432",
-		LogMode:  true,
-	}
-
-	// 3. Create db connection
-	mockDB, err := storage.DBConn(mockDBConfig)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// 4. Create new storage
-	s := storage.New(mockDB)
+	// 2. Create new in-memory storage
+	s := memory.New()
 	suite.db = s
-	suite.gorm = mockDB
 
 	// 5. Migrate system tables: subscriptions, tokens, users
 	app.MigrateSystemTables(s)
@@ -87,7 +68,7 @@ func (suite *TestSuiteEnv) TearDownTest() {
 
 // Bütün testler bittikten sonra çalıştırılıyor
 func (suite *TestSuiteEnv) TearDownSuite() {
-	suite.gorm.Close()
+	// nothing to close for the in-memory storage
 }
 
 // This gets run automatically by `go test` so we call `suite.Run` inside it
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+)
+
+// Sync returns the items changed since the sync of the token in the query,
+// every item when there is no token
+func Sync(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		sync, err := app.Sync(s, r.URL.Query().Get("token"), schema)
+		if err == app.ErrInvalidSyncToken {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, sync)
+	}
+}
@@ -1,8 +1,8 @@
 package api
 
 import (
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -134,7 +134,6 @@ func Language(s storage.Store) http.HandlerFunc {
 func Import(s storage.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var payloadList []model.Payload
-		var loginDTO model.LoginDTO
 
 		decoder := json.NewDecoder(r.Body)
 		if err := decoder.Decode(&payloadList); err != nil {
@@ -144,6 +143,7 @@ func Import(s storage.Store) http.HandlerFunc {
 
 		for i := range payloadList {
 			// Decrypt payload
+			var loginDTO model.LoginDTO
 			key := r.Context().Value("transmissionKey").(string)
 			if err := app.DecryptJSON(key, []byte(payloadList[i].Data), &loginDTO); err != nil {
 				RespondWithError(w, http.StatusInternalServerError, err.Error())
@@ -154,7 +154,7 @@ func Import(s storage.Store) http.HandlerFunc {
 			schema := r.Context().Value("schema").(string)
 			_, err := app.CreateLogin(s, &loginDTO, schema)
 			if err != nil {
-				RespondWithError(w, http.StatusInternalServerError, err.Error())
+				respondWithItemError(w, err)
 				return
 			}
 		}
@@ -222,20 +222,31 @@ func Restore(s storage.Store) http.HandlerFunc {
 			return
 		}
 
-		loginsByte := app.DecryptFile(backupPath, viper.GetString("server.passphrase"))
+		loginsByte, err := app.DecryptBackup(backupPath)
+		if errors.Is(err, app.ErrBackupPassphrase) {
+			RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
 
 		var loginDTOs []model.LoginDTO
-		json.Unmarshal(loginsByte, &loginDTOs)
+		if err := json.Unmarshal(loginsByte, &loginDTOs); err != nil {
+			RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
 		schema := r.Context().Value("schema").(string)
 		for i := range loginDTOs {
 
 			login := &model.Login{
 				URL:      loginDTOs[i].URL,
 				Username: loginDTOs[i].Username,
-				Password: base64.StdEncoding.EncodeToString(app.Encrypt(loginDTOs[i].Password, viper.GetString("server.passphrase"))),
+				Password: loginDTOs[i].Password,
 			}
 
-			s.Logins().Save(login, schema)
+			s.Logins().Save(app.EncryptModel(login).(*model.Login), schema)
 		}
 
 		response := model.Response{Code: http.StatusOK, Status: Success, Message: RestoreBackupSuccess}
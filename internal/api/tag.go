@@ -0,0 +1,184 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+)
+
+const (
+	tagDeleteSuccess = "Tag deleted successfully!"
+	tagMergeSuccess  = "Tag merged successfully!"
+)
+
+// FindAllTags finds all tags
+func FindAllTags(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		tags, err := app.FindAllTags(s, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, tags)
+	}
+}
+
+// CreateTag creates a tag
+func CreateTag(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		var tagDTO model.TagDTO
+		if !decodePayload(w, r, env, transmissionKey, &tagDTO) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		createdTag, err := app.CreateTag(s, &tagDTO, schema)
+		if err != nil {
+			respondWithTagError(w, err)
+			return
+		}
+
+		respondWithTag(w, transmissionKey, createdTag)
+	}
+}
+
+// UpdateTag renames a tag
+func UpdateTag(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		var tagDTO model.TagDTO
+		if !decodePayload(w, r, env, transmissionKey, &tagDTO) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		tag, err := s.Tags().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		updatedTag, err := app.UpdateTag(s, tag, &tagDTO, schema)
+		if err != nil {
+			respondWithTagError(w, err)
+			return
+		}
+
+		respondWithTag(w, transmissionKey, updatedTag)
+	}
+}
+
+// MergeTag moves the items of a tag to the target tag and deletes the tag
+func MergeTag(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		var mergeDTO model.MergeTagDTO
+		if !decodePayload(w, r, env, transmissionKey, &mergeDTO) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		tag, err := s.Tags().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if err := app.MergeTag(s, tag, mergeDTO.TargetID, schema); err != nil {
+			respondWithTagError(w, err)
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  Success,
+			Message: tagMergeSuccess,
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
+
+// DeleteTag deletes a tag and removes it from its items
+func DeleteTag(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		tag, err := s.Tags().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if err := s.Tags().Delete(tag.ID, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  Success,
+			Message: tagDeleteSuccess,
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
+
+func respondWithTag(w http.ResponseWriter, transmissionKey string, tag *model.Tag) {
+	decTag, err := app.DecryptModel(tag)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	RespondWithEncJSON(w, http.StatusOK, transmissionKey, model.ToTagDTO(decTag.(*model.Tag)))
+}
+
+func respondWithTagError(w http.ResponseWriter, err error) {
+	switch {
+	case err == app.ErrTagExists:
+		RespondWithError(w, http.StatusConflict, err.Error())
+	case err == app.ErrTagMergeItself:
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	case gorm.IsRecordNotFoundError(err):
+		RespondWithError(w, http.StatusNotFound, err.Error())
+	default:
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+)
+
+const (
+	templateDeleteSuccess = "Template deleted successfully!"
+)
+
+// FindAllTemplates finds all templates
+func FindAllTemplates(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		templates, err := s.Templates().All(schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, templates)
+	}
+}
+
+// FindTemplateByID finds a template by id
+func FindTemplateByID(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		template, err := s.Templates().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, model.ToTemplateDTO(template))
+	}
+}
+
+// CreateTemplate creates a template
+func CreateTemplate(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		var templateDTO model.TemplateDTO
+		if !decodePayload(w, r, env, transmissionKey, &templateDTO) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		createdTemplate, err := app.CreateTemplate(s, &templateDTO, schema)
+		if err != nil {
+			respondWithTemplateError(w, err)
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, model.ToTemplateDTO(createdTemplate))
+	}
+}
+
+// UpdateTemplate renames a template or changes its fields
+func UpdateTemplate(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		env := viper.GetString("server.env")
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+
+		var templateDTO model.TemplateDTO
+		if !decodePayload(w, r, env, transmissionKey, &templateDTO) {
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		template, err := s.Templates().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		updatedTemplate, err := app.UpdateTemplate(s, template, &templateDTO, schema)
+		if err != nil {
+			respondWithTemplateError(w, err)
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, model.ToTemplateDTO(updatedTemplate))
+	}
+}
+
+// DeleteTemplate deletes a template which has no items
+func DeleteTemplate(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		template, err := s.Templates().FindByID(uint(id), schema)
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if err := app.DeleteTemplate(s, template, schema); err != nil {
+			respondWithTemplateError(w, err)
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  Success,
+			Message: templateDeleteSuccess,
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
+
+func respondWithTemplateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, app.ErrInvalidTemplate):
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	case err == app.ErrTemplateExists, err == app.ErrTemplateInUse:
+		RespondWithError(w, http.StatusConflict, err.Error())
+	case gorm.IsRecordNotFoundError(err):
+		RespondWithError(w, http.StatusNotFound, err.Error())
+	default:
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+const (
+	trashItemRestored = "Item restored successfully!"
+	trashItemPurged   = "Item deleted permanently!"
+	trashEmptied      = "Trash emptied successfully!"
+)
+
+// FindAllTrash lists the deleted items of every type
+func FindAllTrash(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transmissionKey := r.Context().Value("transmissionKey").(string)
+		schema := r.Context().Value("schema").(string)
+
+		items, err := app.Trash(s, schema)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		RespondWithEncJSON(w, http.StatusOK, transmissionKey, items)
+	}
+}
+
+// RestoreTrashItem restores a deleted item
+func RestoreTrashItem(s storage.Store) http.HandlerFunc {
+	return trashItemHandler(s, app.RestoreItem, trashItemRestored)
+}
+
+// PurgeTrashItem deletes an item in the trash permanently
+func PurgeTrashItem(s storage.Store) http.HandlerFunc {
+	return trashItemHandler(s, app.PurgeItem, trashItemPurged)
+}
+
+// EmptyTrash deletes every item in the trash permanently
+func EmptyTrash(s storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		schema := r.Context().Value("schema").(string)
+
+		if err := app.EmptyTrash(s, schema); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  Success,
+			Message: trashEmptied,
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
+
+// trashItemHandler runs fn on the item defined by the type and id in the path
+func trashItemHandler(s storage.Store, fn func(storage.Store, string, uint, string) error, message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		id, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		schema := r.Context().Value("schema").(string)
+		err = fn(s, vars["type"], uint(id), schema)
+		if err == app.ErrUnknownItemType {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		response := model.Response{
+			Code:    http.StatusOK,
+			Status:  Success,
+			Message: message,
+		}
+		RespondWithJSON(w, http.StatusOK, response)
+	}
+}
@@ -30,6 +30,13 @@ func FindAllUsers(s storage.Store) http.HandlerFunc {
 			return
 		}
 
+		total, err := s.Users().Count(argsStr)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		setPageHeaders(w, users, total, argsStr, argsInt)
+
 		usersDTOs := model.ToUserDTOs(users)
 
 		// users = app.DecryptUserPasswords(users)
@@ -0,0 +1,205 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+)
+
+var (
+	// ErrInvalidAttachment is returned when an attachment has no name
+	ErrInvalidAttachment = errors.New("attachments should have a file name")
+	// ErrAttachmentTooLarge is returned when a file is larger than the size limit of attachments
+	ErrAttachmentTooLarge = errors.New("the file is larger than the attachment size limit")
+	// ErrAttachmentQuota is returned when a file doesn't fit in the attachment quota of the user
+	ErrAttachmentQuota = errors.New("the attachment quota is exceeded")
+)
+
+const megabyte = 1024 * 1024
+
+// CreateAttachment encrypts the content with a new random key, streams it to
+// the blob store and saves it as an attachment of the item. The size limit
+// and the quota are checked while the content is read.
+func CreateAttachment(s storage.Store, itemType string, itemID uint, name, contentType string, content io.Reader, schema string) (*model.Attachment, error) {
+	item, ok := itemTypes[itemType]
+	if !ok {
+		return nil, ErrUnknownItemType
+	}
+	if _, err := item.find(s, itemID, schema); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, ErrInvalidAttachment
+	}
+
+	limited, err := limitAttachment(s, content, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	encrypted, err := newEncryptReader(limited, key)
+	if err != nil {
+		return nil, err
+	}
+
+	blobKey := schema + "/" + uuid.NewV4().String()
+	if _, err := s.Blobs().Put(blobKey, encrypted); err != nil {
+		return nil, err
+	}
+
+	attachment := &model.Attachment{
+		ItemType:    itemType,
+		ItemID:      itemID,
+		Name:        name,
+		ContentType: contentType,
+		Size:        limited.n,
+		BlobKey:     blobKey,
+		Key:         base64.StdEncoding.EncodeToString(key),
+	}
+	var createdAttachment *model.Attachment
+	err = s.Transaction(func(tx storage.Store) error {
+		var err error
+		createdAttachment, err = tx.Attachments().Save(EncryptModel(attachment).(*model.Attachment), schema)
+		if err != nil {
+			return err
+		}
+		// uploads running at the same time were all checked against the usage
+		// before them, the one which goes over the quota is rolled back
+		return checkAttachmentQuota(tx, schema)
+	})
+	if err != nil {
+		s.Blobs().Delete(blobKey)
+		return nil, err
+	}
+	return createdAttachment, nil
+}
+
+// OpenAttachment opens the decrypted content of a decrypted attachment,
+// the caller closes it
+func OpenAttachment(s storage.Store, attachment *model.Attachment) (io.ReadCloser, error) {
+	key, err := base64.StdEncoding.DecodeString(attachment.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := s.Blobs().Get(attachment.BlobKey)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := newDecryptReader(blob, key)
+	if err != nil {
+		blob.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{decrypted, blob}, nil
+}
+
+// DeleteAttachment removes an attachment with its content
+func DeleteAttachment(s storage.Store, attachment *model.Attachment, schema string) error {
+	if err := s.Attachments().Delete(attachment.ID, schema); err != nil {
+		return err
+	}
+	return s.Blobs().Delete(attachment.BlobKey)
+}
+
+// deleteAttachments removes the attachments of the items and returns the keys
+// of their contents, which are deleted once the removal is committed
+func deleteAttachments(s storage.Store, itemType string, itemIDs []uint, schema string) ([]string, error) {
+	attachments, err := s.Attachments().FindAllByItems(itemType, itemIDs, schema)
+	if err != nil {
+		return nil, err
+	}
+	blobKeys := []string{}
+	for i := range attachments {
+		if err := s.Attachments().Delete(attachments[i].ID, schema); err != nil {
+			return nil, err
+		}
+		blobKeys = append(blobKeys, attachments[i].BlobKey)
+	}
+	return blobKeys, nil
+}
+
+// deleteBlobs deletes the contents of removed attachments. The rows are
+// already gone, so a content which can't be deleted is only logged.
+func deleteBlobs(s storage.Store, blobKeys []string) {
+	for _, key := range blobKeys {
+		if err := s.Blobs().Delete(key); err != nil {
+			log.Printf("could not delete attachment content %s: %v", key, err)
+		}
+	}
+}
+
+// limitAttachment returns a reader of the content which fails when the
+// content exceeds the size limit of attachments or the quota of the user
+func limitAttachment(s storage.Store, content io.Reader, schema string) (*attachmentLimit, error) {
+	limited := &attachmentLimit{r: content, limit: -1}
+	if maxSize := viper.GetInt64("attachment.maxSize"); maxSize > 0 {
+		limited.limit, limited.err = maxSize*megabyte, ErrAttachmentTooLarge
+	}
+
+	if quota := viper.GetInt64("attachment.quota"); quota > 0 {
+		used, err := s.Attachments().TotalSize(schema)
+		if err != nil {
+			return nil, err
+		}
+		// usage can exceed a lowered quota, a negative limit would be no limit
+		left := quota*megabyte - used
+		if left <= 0 {
+			return nil, ErrAttachmentQuota
+		}
+		if limited.limit < 0 || left < limited.limit {
+			limited.limit, limited.err = left, ErrAttachmentQuota
+		}
+	}
+	return limited, nil
+}
+
+// checkAttachmentQuota fails when the attachments of the user use more than
+// the quota
+func checkAttachmentQuota(s storage.Store, schema string) error {
+	quota := viper.GetInt64("attachment.quota")
+	if quota <= 0 {
+		return nil
+	}
+	used, err := s.Attachments().TotalSize(schema)
+	if err != nil {
+		return err
+	}
+	if used > quota*megabyte {
+		return ErrAttachmentQuota
+	}
+	return nil
+}
+
+// attachmentLimit counts the bytes read and fails with err after limit bytes,
+// a negative limit is no limit
+type attachmentLimit struct {
+	r     io.Reader
+	n     int64
+	limit int64
+	err   error
+}
+
+func (l *attachmentLimit) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.limit >= 0 && l.n > l.limit {
+		return n, l.err
+	}
+	return n, err
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Attachments are encrypted in chunks with AES-GCM so they can be streamed.
+// Every file has a key of its own, so the nonces are the chunk numbers.
+// The last chunk is marked in its nonce, a truncated file can't be decrypted.
+const attachmentChunkSize = 64 * 1024
+
+// errCorruptAttachment is returned when an attachment can't be decrypted
+var errCorruptAttachment = errors.New("attachment is corrupt or its key is wrong")
+
+func newAttachmentAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of the nth chunk
+func chunkNonce(aead cipher.AEAD, n uint64, last bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce, n)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// chunkReader reads the chunks of src one at a time. It reads a byte
+// more than a chunk to find out whether the chunk is the last one.
+type chunkReader struct {
+	src       io.Reader
+	chunkSize int
+	buf       []byte
+	buffered  int
+	out       []byte
+	n         uint64
+	done      bool
+	process   func(chunk []byte, last bool) ([]byte, error)
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	for len(c.out) == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		if err := c.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, c.out)
+	c.out = c.out[n:]
+	return n, nil
+}
+
+func (c *chunkReader) next() error {
+	n, err := io.ReadFull(c.src, c.buf[c.buffered:])
+	c.buffered += n
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	if c.buffered <= c.chunkSize {
+		c.done = true
+		out, err := c.process(c.buf[:c.buffered], true)
+		c.out = out
+		return err
+	}
+
+	out, err := c.process(c.buf[:c.chunkSize], false)
+	if err != nil {
+		return err
+	}
+	c.out = out
+	c.buffered = copy(c.buf, c.buf[c.chunkSize:c.buffered])
+	return nil
+}
+
+// newEncryptReader returns a reader of the encrypted content of src
+func newEncryptReader(src io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAttachmentAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &chunkReader{src: src, chunkSize: attachmentChunkSize, buf: make([]byte, attachmentChunkSize+1)}
+	c.process = func(chunk []byte, last bool) ([]byte, error) {
+		sealed := aead.Seal(nil, chunkNonce(aead, c.n, last), chunk, nil)
+		c.n++
+		return sealed, nil
+	}
+	return c, nil
+}
+
+// newDecryptReader returns a reader of the decrypted content of src
+func newDecryptReader(src io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAttachmentAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSize := attachmentChunkSize + aead.Overhead()
+	c := &chunkReader{src: src, chunkSize: chunkSize, buf: make([]byte, chunkSize+1)}
+	c.process = func(chunk []byte, last bool) ([]byte, error) {
+		opened, err := aead.Open(nil, chunkNonce(aead, c.n, last), chunk, nil)
+		if err != nil {
+			return nil, errCorruptAttachment
+		}
+		c.n++
+		return opened, nil
+	}
+	return c, nil
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/blob"
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachments(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	server, err := CreateServer(s, &model.ServerDTO{Title: "Web"}, schema)
+	require.Nil(t, err)
+
+	// Larger than a chunk and not a multiple of it
+	content := make([]byte, 3*attachmentChunkSize+100)
+	_, err = rand.Read(content)
+	require.Nil(t, err)
+
+	attachment, err := CreateAttachment(s, model.ServerItem, server.ID, "id_ed25519", "text/plain", bytes.NewReader(content), schema)
+	require.Nil(t, err)
+	assert.Equal(t, int64(len(content)), attachment.Size)
+	assert.NotEqual(t, "id_ed25519", attachment.Name, "names should be encrypted")
+
+	stored, err := s.Blobs().Get(attachment.BlobKey)
+	require.Nil(t, err)
+	encrypted, err := ioutil.ReadAll(stored)
+	require.Nil(t, err)
+	assert.False(t, bytes.Contains(encrypted, content[:64]), "contents should be encrypted")
+
+	found, err := s.Attachments().FindByID(attachment.ID, schema)
+	require.Nil(t, err)
+	decrypted, err := DecryptModel(found)
+	require.Nil(t, err)
+	assert.Equal(t, "id_ed25519", decrypted.(*model.Attachment).Name)
+
+	opened, err := OpenAttachment(s, decrypted.(*model.Attachment))
+	require.Nil(t, err)
+	read, err := ioutil.ReadAll(opened)
+	opened.Close()
+	require.Nil(t, err)
+	assert.Equal(t, content, read)
+
+	// Truncated contents can't be decrypted
+	_, err = s.Blobs().Put(attachment.BlobKey, bytes.NewReader(encrypted[:attachmentChunkSize+16]))
+	require.Nil(t, err)
+	opened, err = OpenAttachment(s, decrypted.(*model.Attachment))
+	require.Nil(t, err)
+	_, err = ioutil.ReadAll(opened)
+	assert.Equal(t, errCorruptAttachment, err)
+
+	_, err = CreateAttachment(s, model.ServerItem, server.ID+1, "file", "", strings.NewReader("x"), schema)
+	assert.NotNil(t, err, "items which don't exist can't have attachments")
+	_, err = CreateAttachment(s, "unknown", server.ID, "file", "", strings.NewReader("x"), schema)
+	assert.Equal(t, ErrUnknownItemType, err)
+
+	// Purging the item removes its attachments with their contents
+	require.Nil(t, s.Servers().Delete(server.ID, schema))
+	require.Nil(t, PurgeItem(s, model.ServerItem, server.ID, schema))
+	attachments, err := s.Attachments().FindAll(model.ServerItem, server.ID, schema)
+	require.Nil(t, err)
+	assert.Empty(t, attachments)
+	_, err = s.Blobs().Get(attachment.BlobKey)
+	assert.Equal(t, blob.ErrNotFound, err)
+}
+
+func TestAttachmentLimits(t *testing.T) {
+	viper.Set("attachment.maxSize", 1)
+	viper.Set("attachment.quota", 2)
+	defer viper.Set("attachment.maxSize", nil)
+	defer viper.Set("attachment.quota", nil)
+
+	s := memory.New()
+	schema := "user1"
+	note, err := CreateNote(s, &model.NoteDTO{Title: "Scans"}, schema)
+	require.Nil(t, err)
+
+	upload := func(size int) error {
+		_, err := CreateAttachment(s, model.NoteItem, note.ID, "scan.pdf", "application/pdf", bytes.NewReader(make([]byte, size)), schema)
+		return err
+	}
+
+	assert.Equal(t, ErrAttachmentTooLarge, upload(megabyte+1))
+	assert.Nil(t, upload(megabyte))
+	assert.Nil(t, upload(megabyte-10))
+	assert.Equal(t, ErrAttachmentQuota, upload(11))
+	assert.Nil(t, upload(10))
+
+	total, err := s.Attachments().TotalSize(schema)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2*megabyte), total, "failed uploads shouldn't be saved")
+
+	// An exhausted quota takes no upload, empty ones included
+	assert.Equal(t, ErrAttachmentQuota, upload(0))
+	viper.Set("attachment.quota", 1)
+	assert.Equal(t, ErrAttachmentQuota, upload(1), "usage above a lowered quota shouldn't lift the limit")
+
+	// Uploads at the same time pass the check before them, the one finishing
+	// over the quota is rejected once it is written
+	viper.Set("attachment.quota", 3)
+	reader, writer := io.Pipe()
+	done := make(chan error)
+	go func() {
+		_, err := CreateAttachment(s, model.NoteItem, note.ID, "slow.pdf", "application/pdf", reader, schema)
+		done <- err
+	}()
+	_, err = writer.Write(make([]byte, 10))
+	require.Nil(t, err)
+	assert.Nil(t, upload(megabyte-10))
+	_, err = writer.Write(make([]byte, megabyte-20))
+	require.Nil(t, err)
+	writer.Close()
+	assert.Equal(t, ErrAttachmentQuota, <-done)
+
+	total, err = s.Attachments().TotalSize(schema)
+	require.Nil(t, err)
+	assert.Equal(t, int64(3*megabyte-10), total)
+}
@@ -21,6 +21,13 @@ var (
 	ErrUnauthorized = errors.New("unauthorized")
 )
 
+// EventTokenPurpose is the purpose claim of the tokens opening event streams
+const EventTokenPurpose = "events"
+
+// eventTokenExpireDuration is the lifetime of an event token, long enough
+// to open a stream right after it is created
+const eventTokenExpireDuration = time.Minute
+
 // CreateCache
 func CreateCache(defaultExpiration, cleanupInterval time.Duration) *cache.Cache {
 	return cache.New(defaultExpiration, cleanupInterval)
@@ -80,6 +87,21 @@ func CreateToken(user *model.User) (*model.TokenDetailsDTO, error) {
 	return td, nil
 }
 
+// CreateEventToken creates a short-lived token which opens the event stream
+// of the session of an access token. Browsers can't send headers with
+// EventSource, the token goes in the query string.
+func CreateEventToken(userUUID, tokenUUID string, authorized bool) (string, error) {
+	claims := jwt.MapClaims{}
+	claims["authorized"] = authorized
+	claims["user_uuid"] = userUUID
+	claims["uuid"] = tokenUUID
+	claims["purpose"] = EventTokenPurpose
+	claims["exp"] = time.Now().Add(eventTokenExpireDuration).Unix()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(viper.GetString("server.secret")))
+}
+
 //TokenValid ...
 func TokenValid(bearerToken string) (*jwt.Token, error) {
 	token, err := verifyToken(bearerToken)
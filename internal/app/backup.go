@@ -18,8 +18,27 @@ const (
 var (
 	errBackup           = errors.New("error occurred while backing up data")
 	errNoBackupFilesErr = errors.New("no backup file  provided")
+
+	// ErrBackupPassphrase is returned when none of the passphrases decrypts a backup
+	ErrBackupPassphrase = errors.New("backup is encrypted with an unknown passphrase")
 )
 
+// DecryptBackup decrypts a backup file. Backups have no key id, so the
+// current passphrase is tried first and then the legacy ones, backups taken
+// before the passphrase was rotated can be restored as well.
+func DecryptBackup(filename string) ([]byte, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	for _, passphrase := range passphrases() {
+		if plain, err := decrypt(data, passphrase); err == nil {
+			return plain, nil
+		}
+	}
+	return nil, ErrBackupPassphrase
+}
+
 // BackupData ...
 /* func BackupData(s storage.Store) error {
 	backupFolder := viper.GetString("backup.folder")
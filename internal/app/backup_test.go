@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func tearDown(pattern string) {
@@ -88,3 +90,28 @@ func TestRotateBackup(t *testing.T) {
 	}
 
 }
+
+func TestDecryptBackup(t *testing.T) {
+	current := viper.GetString("server.passphrase")
+	defer viper.Set("server.passphrase", current)
+	defer viper.Set("server.legacyPassphrases", nil)
+	viper.Set("server.passphrase", "new passphrase")
+	viper.Set("server.legacyPassphrases", []string{"old passphrase"})
+
+	backup, err := ioutil.TempFile("/tmp", "passwall-restore.*.bak")
+	require.Nil(t, err)
+	backup.Close()
+	defer os.Remove(backup.Name())
+
+	EncryptFile(backup.Name(), []byte(`[]`), "old passphrase")
+	data, err := DecryptBackup(backup.Name())
+	require.Nil(t, err, "backups taken before the rotation should decrypt")
+	assert.Equal(t, `[]`, string(data))
+
+	EncryptFile(backup.Name(), []byte(`[]`), "other passphrase")
+	_, err = DecryptBackup(backup.Name())
+	assert.Equal(t, ErrBackupPassphrase, err)
+
+	_, err = DecryptBackup(backup.Name() + ".missing")
+	assert.NotNil(t, err)
+}
@@ -7,6 +7,10 @@ import (
 
 // CreateBankAccount creates a new bank account and saves it to the store
 func CreateBankAccount(s storage.Store, dto *model.BankAccountDTO, schema string) (*model.BankAccount, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToBankAccount(dto)
 	encModel := EncryptModel(rawModel)
 
@@ -14,12 +18,24 @@ func CreateBankAccount(s storage.Store, dto *model.BankAccountDTO, schema string
 	if err != nil {
 		return nil, err
 	}
+	if err := recordRevision(s, model.BankAccountItem, createdBankAccount.ID, createdBankAccount, schema); err != nil {
+		return nil, err
+	}
+
+	createdBankAccount.TagIDs, err = tagItem(s, model.BankAccountItem, createdBankAccount.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
 
 	return createdBankAccount, nil
 }
 
 // UpdateBankAccount updates the account with the dto and applies the changes in the store
 func UpdateBankAccount(s storage.Store, bankAccount *model.BankAccount, dto *model.BankAccountDTO, schema string) (*model.BankAccount, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToBankAccount(dto)
 	encModel := EncryptModel(rawModel).(*model.BankAccount)
 
@@ -30,11 +46,60 @@ func UpdateBankAccount(s storage.Store, bankAccount *model.BankAccount, dto *mod
 	bankAccount.IBAN = encModel.IBAN
 	bankAccount.Currency = encModel.Currency
 	bankAccount.Password = encModel.Password
+	bankAccount.CustomFields = encModel.CustomFields
+	bankAccount.Favorite = encModel.Favorite
+	bankAccount.FolderID = encModel.FolderID
+	bankAccount.SearchIndex = encModel.SearchIndex
 
-	updatedBankAccount, err := s.BankAccounts().Save(bankAccount, schema)
+	updatedBankAccount, err := s.BankAccounts().Update(bankAccount, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.BankAccountItem, bankAccount.ID, err, schema)
+	}
+	if err := recordRevision(s, model.BankAccountItem, updatedBankAccount.ID, updatedBankAccount, schema); err != nil {
+		return nil, err
+	}
+
+	updatedBankAccount.TagIDs, err = tagItem(s, model.BankAccountItem, updatedBankAccount.ID, dto.TagIDs, schema)
 	if err != nil {
 		return nil, err
 	}
 
 	return updatedBankAccount, nil
 }
+
+// BulkUpdateBankAccounts updates the bank accounts with the dtos in a single transaction,
+// all of them or none
+func BulkUpdateBankAccounts(s storage.Store, dtos []model.BankAccountDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		bankAccount, err := tx.BankAccounts().FindByID(dtos[i].ID, schema)
+		if err != nil {
+			return dtos[i].ID, err
+		}
+		_, err = UpdateBankAccount(tx, bankAccount, &dtos[i], schema)
+		return bankAccount.ID, err
+	})
+}
+
+// BulkCreateBankAccounts creates the bank accounts of the dtos in a single transaction,
+// all of them or none
+func BulkCreateBankAccounts(s storage.Store, dtos []model.BankAccountDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		createdBankAccount, err := CreateBankAccount(tx, &dtos[i], schema)
+		if err != nil {
+			return 0, err
+		}
+		return createdBankAccount.ID, nil
+	})
+}
+
+// BulkDeleteBankAccounts moves the bank accounts with the ids to the trash in a single
+// transaction, all of them or none
+func BulkDeleteBankAccounts(s storage.Store, ids []uint, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(ids), func(tx storage.Store, i int) (uint, error) {
+		bankAccount, err := tx.BankAccounts().FindByID(ids[i], schema)
+		if err != nil {
+			return ids[i], err
+		}
+		return bankAccount.ID, DeleteItem(tx, model.BankAccountItem, bankAccount.ID, schema)
+	})
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// ErrBulkFailed represents message for a bulk request which was rolled back
+var ErrBulkFailed = errors.New("some items failed, no item was changed")
+
+// ErrBulkConflict represents message for a bulk request which was rolled back
+// because some items were changed by another device
+var ErrBulkConflict = errors.New("some items were changed by another device, no item was changed")
+
+// bulk runs fn for every item of a bulk request in a single transaction.
+// Every item is tried so the results tell all the failures, the changes
+// are only committed when every item succeeds. The results of the items
+// changed by another device have their current revision.
+func bulk(s storage.Store, count int, fn func(tx storage.Store, i int) (uint, error)) ([]model.BulkResult, error) {
+	results := make([]model.BulkResult, count)
+	err := s.Transaction(func(tx storage.Store) error {
+		failed, conflict := false, false
+		for i := range results {
+			id, err := fn(tx, i)
+			results[i] = model.BulkResult{Index: i, ID: id}
+			if err != nil {
+				results[i].Error = err.Error()
+				failed = true
+			}
+			if conflictErr, ok := err.(*RevisionConflictError); ok {
+				results[i].Revision = conflictErr.Revision
+				conflict = true
+			}
+		}
+		if conflict {
+			return ErrBulkConflict
+		}
+		if failed {
+			return ErrBulkFailed
+		}
+		return nil
+	})
+	return results, err
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkUpdate(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	first, err := CreateLogin(s, &model.LoginDTO{Title: "First", Password: "first"}, schema)
+	require.Nil(t, err)
+	second, err := CreateLogin(s, &model.LoginDTO{Title: "Second", Password: "second"}, schema)
+	require.Nil(t, err)
+
+	// A missing item rolls back the whole request
+	results, err := BulkUpdateLogins(s, []model.LoginDTO{
+		{ID: first.ID, Title: "First", Password: "changed"},
+		{ID: 99, Title: "Missing"},
+	}, schema)
+	assert.Equal(t, ErrBulkFailed, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, model.BulkResult{Index: 0, ID: first.ID}, results[0])
+	assert.Equal(t, uint(99), results[1].ID)
+	assert.NotEmpty(t, results[1].Error)
+
+	stored, err := s.Logins().FindByID(first.ID, schema)
+	require.Nil(t, err)
+	decrypted, err := DecryptModel(stored)
+	require.Nil(t, err)
+	assert.Equal(t, "first", decrypted.(*model.Login).Password)
+	revisions, err := FindRevisions(s, model.LoginItem, first.ID, schema)
+	require.Nil(t, err)
+	assert.Len(t, revisions, 1, "the revisions should be rolled back too")
+
+	results, err = BulkUpdateLogins(s, []model.LoginDTO{
+		{ID: first.ID, Title: "First", Password: "changed"},
+		{ID: second.ID, Title: "Second", Password: "changed"},
+	}, schema)
+	require.Nil(t, err)
+	assert.Len(t, results, 2)
+
+	logins, err := s.Logins().All(schema)
+	require.Nil(t, err)
+	for i := range logins {
+		decrypted, err := DecryptModel(&logins[i])
+		require.Nil(t, err)
+		assert.Equal(t, "changed", decrypted.(*model.Login).Password)
+	}
+}
+
+func TestBulkCreateAndDelete(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	// An unknown tag fails the second note, so the first isn't created either
+	results, err := BulkCreateNotes(s, []model.NoteDTO{
+		{Title: "First", Note: "text"},
+		{Title: "Second", Note: "text", TagIDs: []uint{99}},
+	}, schema)
+	assert.Equal(t, ErrBulkFailed, err)
+	require.Len(t, results, 2)
+	assert.NotEmpty(t, results[1].Error)
+	notes, err := s.Notes().All(schema)
+	require.Nil(t, err)
+	assert.Empty(t, notes)
+
+	results, err = BulkCreateNotes(s, []model.NoteDTO{
+		{Title: "First", Note: "text"},
+		{Title: "Second", Note: "text"},
+	}, schema)
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	ids := []uint{results[0].ID, results[1].ID}
+	assert.NotZero(t, ids[0])
+	assert.NotEqual(t, ids[0], ids[1])
+
+	_, err = BulkDeleteNotes(s, []uint{ids[0], 99}, schema)
+	assert.Equal(t, ErrBulkFailed, err)
+	notes, err = s.Notes().All(schema)
+	require.Nil(t, err)
+	assert.Len(t, notes, 2)
+
+	_, err = BulkDeleteNotes(s, ids, schema)
+	require.Nil(t, err)
+	trash, err := Trash(s, schema)
+	require.Nil(t, err)
+	assert.Len(t, trash, 2)
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/passwall/passwall-server/internal/storage"
+)
+
+// RevisionConflictError is returned when an item is updated from a revision
+// which isn't its current one, another device changed it meanwhile.
+// Item is the decrypted DTO of the current item so the client can merge.
+type RevisionConflictError struct {
+	Revision uint
+	Item     interface{}
+}
+
+func (e *RevisionConflictError) Error() string {
+	return fmt.Sprintf("item was changed by another device, its current revision is %d", e.Revision)
+}
+
+// revisionConflict turns the failure of an update because another device
+// moved the item past the revision of the update into a RevisionConflictError
+// with the current item. Other errors are returned as they are.
+func revisionConflict(s storage.Store, itemType string, id uint, err error, schema string) error {
+	if !errors.Is(err, storage.ErrRevisionChanged) {
+		return err
+	}
+
+	dto, err := currentItem(s, itemType, id, schema)
+	if err != nil {
+		return err
+	}
+	revision := uint(reflect.ValueOf(dto).Elem().FieldByName("Revision").Uint())
+	return &RevisionConflictError{Revision: revision, Item: dto}
+}
+
+// currentItem returns the decrypted DTO of the item as it is stored
+func currentItem(s storage.Store, itemType string, id uint, schema string) (interface{}, error) {
+	t, err := findItemType(itemType)
+	if err != nil {
+		return nil, err
+	}
+	item, err := t.find(s, id, schema)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := DecryptModel(item); err != nil {
+		return nil, err
+	}
+	if err := LoadTags(s, itemType, item, schema); err != nil {
+		return nil, err
+	}
+	return t.toDTO(item), nil
+}
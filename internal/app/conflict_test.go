@@ -0,0 +1,97 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisionConflict(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	login, err := CreateLogin(s, &model.LoginDTO{Title: "Mail", Password: "first"}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, uint(1), login.Revision)
+
+	// a device updates from the current revision
+	login, err = UpdateLogin(s, login, &model.LoginDTO{Revision: 1, Title: "Mail", Password: "second"}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, uint(2), login.Revision)
+
+	// another device still has the first revision
+	stale, err := s.Logins().FindByID(login.ID, schema)
+	require.Nil(t, err)
+	_, err = UpdateLogin(s, stale, &model.LoginDTO{Revision: 1, Title: "Mail", Password: "third"}, schema)
+	conflict, ok := err.(*RevisionConflictError)
+	require.True(t, ok, "a stale revision should conflict")
+	assert.Equal(t, uint(2), conflict.Revision)
+	current := conflict.Item.(*model.LoginDTO)
+	assert.Equal(t, "second", current.Password, "the conflict should carry the decrypted current item")
+	assert.Equal(t, []uint{}, current.TagIDs)
+
+	// two devices read the item at the same revision, only the first update wins
+	first, err := s.Logins().FindByID(login.ID, schema)
+	require.Nil(t, err)
+	second, err := s.Logins().FindByID(login.ID, schema)
+	require.Nil(t, err)
+	_, err = UpdateLogin(s, first, &model.LoginDTO{Revision: 2, Title: "Mail", Password: "first device"}, schema)
+	require.Nil(t, err)
+	_, err = UpdateLogin(s, second, &model.LoginDTO{Revision: 2, Title: "Mail", Password: "second device"}, schema)
+	conflict, ok = err.(*RevisionConflictError)
+	require.True(t, ok, "the second update shouldn't overwrite the first")
+	assert.Equal(t, uint(3), conflict.Revision)
+	assert.Equal(t, "first device", conflict.Item.(*model.LoginDTO).Password)
+
+	// updates without a revision overwrite the item
+	login, err = UpdateLogin(s, stale, &model.LoginDTO{Title: "Mail", Password: "third"}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, uint(4), login.Revision)
+
+	// moves and restored revisions are changes too
+	folder, err := CreateFolder(s, &model.FolderDTO{Name: "Work"}, schema)
+	require.Nil(t, err)
+	require.Nil(t, MoveItems(s, &model.MoveItemsDTO{FolderID: &folder.ID, Items: []model.ItemRef{{Type: model.LoginItem, ID: login.ID}}}, schema))
+	revisions, err := FindRevisions(s, model.LoginItem, login.ID, schema)
+	require.Nil(t, err)
+	restored, err := RestoreRevision(s, model.LoginItem, login.ID, revisions[len(revisions)-1].ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, uint(6), restored.(*model.LoginDTO).Revision)
+	assert.Equal(t, "first", restored.(*model.LoginDTO).Password)
+
+	changes, err := DiffRevision(s, model.LoginItem, login.ID, revisions[0].ID, schema)
+	require.Nil(t, err)
+	for _, change := range changes {
+		assert.NotEqual(t, "revision", change.Field, "revisions shouldn't differ by their number")
+	}
+}
+
+func TestBulkRevisionConflict(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	first, err := CreateNote(s, &model.NoteDTO{Title: "First"}, schema)
+	require.Nil(t, err)
+	second, err := CreateNote(s, &model.NoteDTO{Title: "Second"}, schema)
+	require.Nil(t, err)
+	_, err = UpdateNote(s, second, &model.NoteDTO{Title: "Second changed"}, schema)
+	require.Nil(t, err)
+
+	results, err := BulkUpdateNotes(s, []model.NoteDTO{
+		{ID: first.ID, Revision: 1, Title: "First again"},
+		{ID: second.ID, Revision: 1, Title: "Second again"},
+	}, schema)
+	assert.Equal(t, ErrBulkConflict, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, model.BulkResult{Index: 0, ID: first.ID}, results[0])
+	assert.Equal(t, uint(2), results[1].Revision)
+	assert.NotEmpty(t, results[1].Error)
+
+	stored, err := s.Notes().FindByID(first.ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "First", stored.Title, "a conflict should roll back the whole request")
+	assert.Equal(t, uint(1), stored.Revision)
+}
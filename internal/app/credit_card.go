@@ -7,6 +7,10 @@ import (
 
 // CreateCreditCard creates a new credit card and saves it to the store
 func CreateCreditCard(s storage.Store, dto *model.CreditCardDTO, schema string) (*model.CreditCard, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToCreditCard(dto)
 	encModel := EncryptModel(rawModel)
 
@@ -14,12 +18,24 @@ func CreateCreditCard(s storage.Store, dto *model.CreditCardDTO, schema string)
 	if err != nil {
 		return nil, err
 	}
+	if err := recordRevision(s, model.CreditCardItem, createdCreditCard.ID, createdCreditCard, schema); err != nil {
+		return nil, err
+	}
+
+	createdCreditCard.TagIDs, err = tagItem(s, model.CreditCardItem, createdCreditCard.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
 
 	return createdCreditCard, nil
 }
 
 // UpdateCreditCard updates the credit card with the dto and applies the changes in the store
 func UpdateCreditCard(s storage.Store, creditCard *model.CreditCard, dto *model.CreditCardDTO, schema string) (*model.CreditCard, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToCreditCard(dto)
 	encModel := EncryptModel(rawModel).(*model.CreditCard)
 
@@ -29,11 +45,60 @@ func UpdateCreditCard(s storage.Store, creditCard *model.CreditCard, dto *model.
 	creditCard.Number = encModel.Number
 	creditCard.VerificationNumber = encModel.VerificationNumber
 	creditCard.ExpiryDate = encModel.ExpiryDate
+	creditCard.CustomFields = encModel.CustomFields
+	creditCard.Favorite = encModel.Favorite
+	creditCard.FolderID = encModel.FolderID
+	creditCard.SearchIndex = encModel.SearchIndex
 
-	updatedCreditCard, err := s.CreditCards().Save(creditCard, schema)
+	updatedCreditCard, err := s.CreditCards().Update(creditCard, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.CreditCardItem, creditCard.ID, err, schema)
+	}
+	if err := recordRevision(s, model.CreditCardItem, updatedCreditCard.ID, updatedCreditCard, schema); err != nil {
+		return nil, err
+	}
+
+	updatedCreditCard.TagIDs, err = tagItem(s, model.CreditCardItem, updatedCreditCard.ID, dto.TagIDs, schema)
 	if err != nil {
 		return nil, err
 	}
 
 	return updatedCreditCard, nil
 }
+
+// BulkUpdateCreditCards updates the credit cards with the dtos in a single transaction,
+// all of them or none
+func BulkUpdateCreditCards(s storage.Store, dtos []model.CreditCardDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		creditCard, err := tx.CreditCards().FindByID(dtos[i].ID, schema)
+		if err != nil {
+			return dtos[i].ID, err
+		}
+		_, err = UpdateCreditCard(tx, creditCard, &dtos[i], schema)
+		return creditCard.ID, err
+	})
+}
+
+// BulkCreateCreditCards creates the credit cards of the dtos in a single transaction,
+// all of them or none
+func BulkCreateCreditCards(s storage.Store, dtos []model.CreditCardDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		createdCreditCard, err := CreateCreditCard(tx, &dtos[i], schema)
+		if err != nil {
+			return 0, err
+		}
+		return createdCreditCard.ID, nil
+	})
+}
+
+// BulkDeleteCreditCards moves the credit cards with the ids to the trash in a single
+// transaction, all of them or none
+func BulkDeleteCreditCards(s storage.Store, ids []uint, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(ids), func(tx storage.Store, i int) (uint, error) {
+		creditCard, err := tx.CreditCards().FindByID(ids[i], schema)
+		if err != nil {
+			return ids[i], err
+		}
+		return creditCard.ID, DeleteItem(tx, model.CreditCardItem, creditCard.ID, schema)
+	})
+}
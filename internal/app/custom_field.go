@@ -0,0 +1,139 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/passwall/passwall-server/model"
+)
+
+// ErrInvalidCustomField is returned when a custom field of an item is not valid
+var ErrInvalidCustomField = errors.New("invalid custom field")
+
+// validateCustomFields checks that every custom field has a name, a known
+// type and a value matching the type
+func validateCustomFields(fields model.CustomFields) error {
+	for i, field := range fields {
+		if strings.TrimSpace(field.Name) == "" {
+			return fmt.Errorf("%w: field %d has no name", ErrInvalidCustomField, i)
+		}
+		if !isCustomFieldType(field.Type) {
+			return fmt.Errorf("%w: %q has unknown type %q, it should be one of %s",
+				ErrInvalidCustomField, field.Name, field.Type, strings.Join(model.CustomFieldTypes, ", "))
+		}
+		if err := checkFieldValue(field.Type, field.Value); err != nil {
+			return fmt.Errorf("%w: %q %s", ErrInvalidCustomField, field.Name, err)
+		}
+	}
+	return nil
+}
+
+// isCustomFieldType reports whether the type is one of the custom field types
+func isCustomFieldType(fieldType string) bool {
+	for _, t := range model.CustomFieldTypes {
+		if t == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFieldValue checks that a value matches the type of its field,
+// empty values match every type
+func checkFieldValue(fieldType, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	switch fieldType {
+	case model.BooleanField:
+		if value != "true" && value != "false" {
+			return errors.New("should be true or false")
+		}
+	case model.LinkField:
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" {
+			return errors.New("should be a link")
+		}
+	}
+	return nil
+}
+
+// encryptFieldList encrypts a field holding a list of fields, like the custom
+// fields, and reports whether the field was one
+func encryptFieldList(field reflect.Value) bool {
+	switch fields := field.Interface().(type) {
+	case model.CustomFields:
+		field.Set(reflect.ValueOf(encryptCustomFields(fields)))
+	case model.ItemFields:
+		field.Set(reflect.ValueOf(encryptItemFields(fields)))
+	default:
+		return false
+	}
+	return true
+}
+
+// decryptFieldList decrypts a field holding a list of fields, like the custom
+// fields, and reports whether the field was one
+func decryptFieldList(field reflect.Value) (bool, error) {
+	var decrypted interface{}
+	var err error
+	switch fields := field.Interface().(type) {
+	case model.CustomFields:
+		decrypted, err = decryptCustomFields(fields)
+	case model.ItemFields:
+		decrypted, err = decryptItemFields(fields)
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	field.Set(reflect.ValueOf(decrypted))
+	return true, nil
+}
+
+// encryptCustomFields returns the custom fields with their names and values
+// encrypted. The fields are copied, the fields of the DTO are left as they are.
+func encryptCustomFields(fields model.CustomFields) model.CustomFields {
+	if fields == nil {
+		return nil
+	}
+
+	encrypted := make(model.CustomFields, len(fields))
+	for i, field := range fields {
+		encrypted[i] = model.CustomField{
+			Name:  encryptValue(field.Name),
+			Value: encryptValue(field.Value),
+			Type:  field.Type,
+		}
+	}
+	return encrypted
+}
+
+// decryptCustomFields returns a decrypted copy of the custom fields
+func decryptCustomFields(fields model.CustomFields) (model.CustomFields, error) {
+	if fields == nil {
+		return nil, nil
+	}
+
+	decrypted := make(model.CustomFields, len(fields))
+	for i, field := range fields {
+		name, err := decryptValue(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := decryptValue(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		decrypted[i] = model.CustomField{
+			Name:  name,
+			Value: value,
+			Type:  field.Type,
+		}
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFields(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	fields := model.CustomFields{
+		{Name: "Recovery code", Value: "abcd-efgh", Type: model.HiddenField},
+		{Name: "Console", Value: "https://console.passwall.io", Type: model.LinkField},
+	}
+	dto := &model.ServerDTO{Title: "Web", CustomFields: fields}
+	server, err := CreateServer(s, dto, schema)
+	require.Nil(t, err)
+	require.Len(t, server.CustomFields, 2)
+	assert.NotEqual(t, "abcd-efgh", server.CustomFields[0].Value, "values should be encrypted")
+	assert.NotEqual(t, "Recovery code", server.CustomFields[0].Name, "names should be encrypted")
+	assert.Equal(t, "abcd-efgh", dto.CustomFields[0].Value, "the dto should be left as it is")
+
+	found, err := s.Servers().FindByID(server.ID, schema)
+	require.Nil(t, err)
+	decrypted, err := DecryptModel(found)
+	require.Nil(t, err)
+	assert.Equal(t, fields, decrypted.(*model.Server).CustomFields)
+
+	for _, invalid := range []model.CustomField{
+		{Name: "", Value: "x", Type: model.TextField},
+		{Name: "Enabled", Value: "yes", Type: model.BooleanField},
+		{Name: "Site", Value: "passwall", Type: model.LinkField},
+		{Name: "Color", Value: "red", Type: "color"},
+	} {
+		_, err := CreateNote(s, &model.NoteDTO{Title: "invalid", CustomFields: model.CustomFields{invalid}}, schema)
+		assert.True(t, errors.Is(err, ErrInvalidCustomField), "%+v should be invalid", invalid)
+	}
+
+	// Invalid fields fail the bulk operations item by item
+	results, err := BulkCreateNotes(s, []model.NoteDTO{
+		{Title: "valid", CustomFields: model.CustomFields{{Name: "Enabled", Value: "true", Type: model.BooleanField}}},
+		{Title: "invalid", CustomFields: model.CustomFields{{Name: "Enabled", Value: "yes", Type: model.BooleanField}}},
+	}, schema)
+	assert.Equal(t, ErrBulkFailed, err)
+	assert.Empty(t, results[0].Error)
+	assert.Contains(t, results[1].Error, "invalid custom field")
+}
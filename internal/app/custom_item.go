@@ -0,0 +1,127 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// ErrInvalidCustomItem is returned when the fields of an item don't match its template
+var ErrInvalidCustomItem = errors.New("invalid item")
+
+// CreateCustomItem creates a new item of the template and saves it to the store
+func CreateCustomItem(s storage.Store, template *model.Template, dto *model.CustomItemDTO, schema string) (*model.CustomItem, error) {
+	if err := validateCustomItem(template, dto); err != nil {
+		return nil, err
+	}
+
+	rawModel := model.ToCustomItem(dto, template)
+	encModel := EncryptModel(rawModel)
+
+	createdCustomItem, err := s.CustomItems().Save(encModel.(*model.CustomItem), schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordRevision(s, model.CustomItemItem, createdCustomItem.ID, createdCustomItem, schema); err != nil {
+		return nil, err
+	}
+
+	createdCustomItem.TagIDs, err = tagItem(s, model.CustomItemItem, createdCustomItem.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return createdCustomItem, nil
+}
+
+// UpdateCustomItem updates the item with the dto and applies the changes in the store,
+// the fields are encrypted according to the current fields of the template
+func UpdateCustomItem(s storage.Store, template *model.Template, customItem *model.CustomItem, dto *model.CustomItemDTO, schema string) (*model.CustomItem, error) {
+	if err := validateCustomItem(template, dto); err != nil {
+		return nil, err
+	}
+
+	rawModel := model.ToCustomItem(dto, template)
+	encModel := EncryptModel(rawModel).(*model.CustomItem)
+
+	customItem.Title = encModel.Title
+	customItem.Fields = encModel.Fields
+	customItem.CustomFields = encModel.CustomFields
+	customItem.Favorite = encModel.Favorite
+	customItem.FolderID = encModel.FolderID
+	customItem.SearchIndex = encModel.SearchIndex
+
+	updatedCustomItem, err := s.CustomItems().Update(customItem, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.CustomItemItem, customItem.ID, err, schema)
+	}
+	if err := recordRevision(s, model.CustomItemItem, updatedCustomItem.ID, updatedCustomItem, schema); err != nil {
+		return nil, err
+	}
+
+	updatedCustomItem.TagIDs, err = tagItem(s, model.CustomItemItem, updatedCustomItem.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedCustomItem, nil
+}
+
+// validateCustomItem checks the fields of the dto against the template
+func validateCustomItem(template *model.Template, dto *model.CustomItemDTO) error {
+	for name := range dto.Fields {
+		if _, ok := template.Field(name); !ok {
+			return fmt.Errorf("%w: %q is not a field of %s", ErrInvalidCustomItem, name, template.Type)
+		}
+	}
+
+	for _, field := range template.Fields {
+		value := dto.Fields[field.Name]
+		if field.Required && value == "" {
+			return fmt.Errorf("%w: %q is required", ErrInvalidCustomItem, field.Name)
+		}
+		if err := checkFieldValue(field.Type, value); err != nil {
+			return fmt.Errorf("%w: %q %s", ErrInvalidCustomItem, field.Name, err)
+		}
+	}
+
+	return validateCustomFields(dto.CustomFields)
+}
+
+// encryptItemFields returns a copy of the fields with the secret values encrypted
+func encryptItemFields(fields model.ItemFields) model.ItemFields {
+	if fields == nil {
+		return nil
+	}
+
+	encrypted := make(model.ItemFields, len(fields))
+	for i, field := range fields {
+		encrypted[i] = field
+		if field.Secret {
+			encrypted[i].Value = encryptValue(field.Value)
+		}
+	}
+	return encrypted
+}
+
+// decryptItemFields returns a copy of the fields with the secret values decrypted
+func decryptItemFields(fields model.ItemFields) (model.ItemFields, error) {
+	if fields == nil {
+		return nil, nil
+	}
+
+	decrypted := make(model.ItemFields, len(fields))
+	for i, field := range fields {
+		decrypted[i] = field
+		if field.Secret {
+			value, err := decryptValue(field.Value)
+			if err != nil {
+				return nil, err
+			}
+			decrypted[i].Value = value
+		}
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomItems(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	template, err := CreateTemplate(s, &model.TemplateDTO{
+		Type: "wifi-network",
+		Name: "Wi-Fi",
+		Fields: []model.TemplateField{
+			{Name: "ssid", Type: model.TextField, Required: true},
+			{Name: "password", Type: model.HiddenField, Secret: true},
+		},
+	}, schema)
+	require.Nil(t, err)
+
+	_, err = CreateTemplate(s, &model.TemplateDTO{Type: "wifi-network", Name: "Again"}, schema)
+	assert.Equal(t, ErrTemplateExists, err)
+	_, err = CreateTemplate(s, &model.TemplateDTO{Type: "Wi Fi", Name: "Invalid"}, schema)
+	assert.True(t, errors.Is(err, ErrInvalidTemplate))
+
+	dto := &model.CustomItemDTO{Title: "Office", Fields: map[string]string{"ssid": "passwall", "password": "dummypassword"}}
+	item, err := CreateCustomItem(s, template, dto, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "wifi-network", item.Type)
+	require.Len(t, item.Fields, 2)
+	assert.Equal(t, "passwall", item.Fields[0].Value, "plain fields should be left as they are")
+	assert.NotEqual(t, "dummypassword", item.Fields[1].Value, "secret fields should be encrypted")
+
+	found, err := s.CustomItems().FindByID(item.ID, schema)
+	require.Nil(t, err)
+	decrypted, err := DecryptModel(found)
+	require.Nil(t, err)
+	assert.Equal(t, dto.Fields, model.ToCustomItemDTO(decrypted.(*model.CustomItem)).Fields)
+
+	for _, fields := range []map[string]string{
+		{"password": "dummypassword"},
+		{"ssid": "passwall", "channel": "6"},
+	} {
+		_, err := CreateCustomItem(s, template, &model.CustomItemDTO{Title: "invalid", Fields: fields}, schema)
+		assert.True(t, errors.Is(err, ErrInvalidCustomItem), "%v should be invalid", fields)
+	}
+
+	// Templates with items, trashed ones included, can't be deleted
+	require.Nil(t, s.CustomItems().Delete(item.ID, schema))
+	assert.Equal(t, ErrTemplateInUse, DeleteTemplate(s, template, schema))
+	require.Nil(t, s.CustomItems().Purge(item.ID, schema))
+	assert.Nil(t, DeleteTemplate(s, template, schema))
+}
@@ -7,6 +7,10 @@ import (
 
 // CreateEmail creates a new bank account and saves it to the store
 func CreateEmail(s storage.Store, dto *model.EmailDTO, schema string) (*model.Email, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToEmail(dto)
 	encModel := EncryptModel(rawModel)
 
@@ -14,23 +18,84 @@ func CreateEmail(s storage.Store, dto *model.EmailDTO, schema string) (*model.Em
 	if err != nil {
 		return nil, err
 	}
+	if err := recordRevision(s, model.EmailItem, createdEmail.ID, createdEmail, schema); err != nil {
+		return nil, err
+	}
+
+	createdEmail.TagIDs, err = tagItem(s, model.EmailItem, createdEmail.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
 
 	return createdEmail, nil
 }
 
 // UpdateEmail updates the account with the dto and applies the changes in the store
 func UpdateEmail(s storage.Store, email *model.Email, dto *model.EmailDTO, schema string) (*model.Email, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToEmail(dto)
 	encModel := EncryptModel(rawModel).(*model.Email)
 
 	email.Title = encModel.Title
 	email.Email = encModel.Email
 	email.Password = encModel.Password
+	email.CustomFields = encModel.CustomFields
+	email.Favorite = encModel.Favorite
+	email.FolderID = encModel.FolderID
+	email.SearchIndex = encModel.SearchIndex
 
-	updatedEmail, err := s.Emails().Save(email, schema)
+	updatedEmail, err := s.Emails().Update(email, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.EmailItem, email.ID, err, schema)
+	}
+	if err := recordRevision(s, model.EmailItem, updatedEmail.ID, updatedEmail, schema); err != nil {
+		return nil, err
+	}
+
+	updatedEmail.TagIDs, err = tagItem(s, model.EmailItem, updatedEmail.ID, dto.TagIDs, schema)
 	if err != nil {
 		return nil, err
 	}
 
 	return updatedEmail, nil
 }
+
+// BulkUpdateEmails updates the emails with the dtos in a single transaction,
+// all of them or none
+func BulkUpdateEmails(s storage.Store, dtos []model.EmailDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		email, err := tx.Emails().FindByID(dtos[i].ID, schema)
+		if err != nil {
+			return dtos[i].ID, err
+		}
+		_, err = UpdateEmail(tx, email, &dtos[i], schema)
+		return email.ID, err
+	})
+}
+
+// BulkCreateEmails creates the emails of the dtos in a single transaction,
+// all of them or none
+func BulkCreateEmails(s storage.Store, dtos []model.EmailDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		createdEmail, err := CreateEmail(tx, &dtos[i], schema)
+		if err != nil {
+			return 0, err
+		}
+		return createdEmail.ID, nil
+	})
+}
+
+// BulkDeleteEmails moves the emails with the ids to the trash in a single
+// transaction, all of them or none
+func BulkDeleteEmails(s storage.Store, ids []uint, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(ids), func(tx storage.Store, i int) (uint, error) {
+		email, err := tx.Emails().FindByID(ids[i], schema)
+		if err != nil {
+			return ids[i], err
+		}
+		return email.ID, DeleteItem(tx, model.EmailItem, email.ID, schema)
+	})
+}
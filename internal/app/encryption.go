@@ -17,13 +17,13 @@ import (
 	"time"
 
 	"github.com/Luzifer/go-openssl/v4"
-	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var (
 	minSecureKeyLength = 8
 	errShortSecureKey  = errors.New("length of secure key does not meet with minimum requirements")
+	errShortCiphertext = errors.New("ciphertext is too short")
 )
 
 // FindIndex ...
@@ -112,24 +112,30 @@ func Encrypt(dataStr string, passphrase string) []byte {
 
 // Decrypt ...
 func Decrypt(dataStr string, passphrase string) []byte {
-	dataByte := []byte(dataStr)
+	plainByte, err := decrypt([]byte(dataStr), passphrase)
+	if err != nil {
+		panic(err.Error())
+	}
+	return plainByte
+}
+
+// decrypt decrypts data encrypted by Encrypt, it fails when the passphrase is wrong
+func decrypt(dataByte []byte, passphrase string) ([]byte, error) {
 	key := []byte(CreateHash(passphrase))
 	block, err := aes.NewCipher(key)
 	if err != nil {
-		panic(err.Error())
+		return nil, err
 	}
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		panic(err.Error())
+		return nil, err
 	}
 	nonceSize := gcm.NonceSize()
-	nonce, ciphertext := dataByte[:nonceSize], dataByte[nonceSize:]
-	plainByte, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		panic(err.Error())
+	if len(dataByte) < nonceSize {
+		return nil, errShortCiphertext
 	}
-	return plainByte
-	// return string(plainByte[:])
+	nonce, ciphertext := dataByte[:nonceSize], dataByte[nonceSize:]
+	return gcm.Open(nil, nonce, ciphertext, nil)
 }
 
 // EncryptFile ...
@@ -145,8 +151,11 @@ func DecryptFile(filename string, passphrase string) []byte {
 	return Decrypt(string(data[:]), passphrase)
 }
 
-// EncryptModel encrypts struct pointer according to struct tags
+// EncryptModel encrypts struct pointer according to struct tags,
+// the search index is built from the plain values before
 func EncryptModel(rawModel interface{}) interface{} {
+	setSearchIndex(rawModel)
+
 	num := reflect.ValueOf(rawModel).Elem().NumField()
 
 	var tagVal string
@@ -155,9 +164,12 @@ func EncryptModel(rawModel interface{}) interface{} {
 		tagVal = reflect.TypeOf(rawModel).Elem().Field(i).Tag.Get("encrypt")
 		value := reflect.ValueOf(rawModel).Elem().Field(i).String()
 
+		if tagVal == "true" && encryptFieldList(reflect.ValueOf(rawModel).Elem().Field(i)) {
+			continue
+		}
+
 		if tagVal == "true" {
-			value = base64.StdEncoding.EncodeToString(Encrypt(value, viper.GetString("server.passphrase")))
-			reflect.ValueOf(rawModel).Elem().Field(i).SetString(value)
+			reflect.ValueOf(rawModel).Elem().Field(i).SetString(encryptValue(value))
 		}
 	}
 
@@ -166,8 +178,6 @@ func EncryptModel(rawModel interface{}) interface{} {
 
 // DecryptModel decrypts struct pointer according to struct tags
 func DecryptModel(rawModel interface{}) (interface{}, error) {
-	var err error
-	var valueByte []byte
 	num := reflect.ValueOf(rawModel).Elem().NumField()
 
 	var tagVal string
@@ -177,13 +187,25 @@ func DecryptModel(rawModel interface{}) (interface{}, error) {
 		value := reflect.ValueOf(rawModel).Elem().Field(i).String()
 
 		if tagVal == "true" {
-			valueByte, err = base64.StdEncoding.DecodeString(value)
-			value = string(Decrypt(string(valueByte[:]), viper.GetString("server.passphrase")))
+			ok, err := decryptFieldList(reflect.ValueOf(rawModel).Elem().Field(i))
+			if err != nil {
+				return rawModel, err
+			}
+			if ok {
+				continue
+			}
+		}
+
+		if tagVal == "true" {
+			value, err := decryptValue(value)
+			if err != nil {
+				return rawModel, err
+			}
 			reflect.ValueOf(rawModel).Elem().Field(i).SetString(value)
 		}
 	}
 
-	return rawModel, err
+	return rawModel, nil
 }
 
 // DecryptPayload ...
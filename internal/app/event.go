@@ -0,0 +1,19 @@
+package app
+
+import (
+	"log"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// publish tells the devices of the user that an item changed. Events are
+// hints for the devices to sync, so a change doesn't fail when its event
+// can't be published.
+func publish(s storage.Store, eventType, itemType string, id uint, schema string) {
+	e := model.Event{Type: eventType, ItemType: itemType, ItemID: id, Time: time.Now()}
+	if err := s.Events().Publish(schema, e); err != nil {
+		log.Printf("%s: could not publish %s of %s %d: %v", schema, eventType, itemType, id, err)
+	}
+}
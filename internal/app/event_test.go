@@ -0,0 +1,55 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemEvents(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	events, cancel := s.Events().Subscribe(schema)
+	defer cancel()
+	next := func() (string, string, uint) {
+		select {
+		case e := <-events:
+			return e.Type, e.ItemType, e.ItemID
+		default:
+			return "", "", 0
+		}
+	}
+
+	note, err := CreateNote(s, &model.NoteDTO{Title: "Note"}, schema)
+	require.Nil(t, err)
+	eventType, itemType, id := next()
+	assert.Equal(t, []interface{}{model.ItemChangedEvent, model.NoteItem, note.ID}, []interface{}{eventType, itemType, id})
+
+	require.Nil(t, DeleteItem(s, model.NoteItem, note.ID, schema))
+	eventType, _, id = next()
+	assert.Equal(t, model.ItemDeletedEvent, eventType)
+	assert.Equal(t, note.ID, id)
+
+	require.Nil(t, RestoreItem(s, model.NoteItem, note.ID, schema))
+	eventType, _, _ = next()
+	assert.Equal(t, model.ItemChangedEvent, eventType)
+
+	// the events of a transaction are published once it is committed
+	results, err := BulkCreateNotes(s, []model.NoteDTO{{Title: "First"}, {Title: "Second", CustomFields: model.CustomFields{{Name: "", Type: "text"}}}}, schema)
+	require.NotNil(t, err, "%v", results)
+	eventType, _, _ = next()
+	assert.Empty(t, eventType, "a rolled back transaction shouldn't publish its events")
+
+	_, err = BulkDeleteNotes(s, []uint{note.ID}, schema)
+	require.Nil(t, err)
+	eventType, _, id = next()
+	assert.Equal(t, model.ItemDeletedEvent, eventType)
+	assert.Equal(t, note.ID, id)
+
+	assert.True(t, errors.Is(DeleteItem(s, "unknown", 1, schema), ErrUnknownItemType))
+}
@@ -0,0 +1,155 @@
+package app
+
+import (
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Favorites finds the decrypted favorite items of every type
+func Favorites(s storage.Store, schema string) (*model.Favorites, error) {
+	argsStr := map[string]string{"favorite": "true", "order": "updated_at desc"}
+	argsInt := map[string]int{"limit": -1, "offset": -1}
+	favorites := &model.Favorites{}
+
+	logins, err := s.Logins().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range logins {
+		if _, err := DecryptModel(&logins[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.LoginItem, logins, schema); err != nil {
+		return nil, err
+	}
+	favorites.Logins = logins
+
+	cards, err := s.CreditCards().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cards {
+		if _, err := DecryptModel(&cards[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.CreditCardItem, cards, schema); err != nil {
+		return nil, err
+	}
+	favorites.CreditCards = cards
+
+	accounts, err := s.BankAccounts().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		if _, err := DecryptModel(&accounts[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.BankAccountItem, accounts, schema); err != nil {
+		return nil, err
+	}
+	favorites.BankAccounts = accounts
+
+	notes, err := s.Notes().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range notes {
+		if _, err := DecryptModel(&notes[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.NoteItem, notes, schema); err != nil {
+		return nil, err
+	}
+	favorites.Notes = notes
+
+	emails, err := s.Emails().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range emails {
+		if _, err := DecryptModel(&emails[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.EmailItem, emails, schema); err != nil {
+		return nil, err
+	}
+	favorites.Emails = emails
+
+	servers, err := s.Servers().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range servers {
+		if _, err := DecryptModel(&servers[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.ServerItem, servers, schema); err != nil {
+		return nil, err
+	}
+	favorites.Servers = servers
+
+	identities, err := s.Identities().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range identities {
+		if _, err := DecryptModel(&identities[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.IdentityItem, identities, schema); err != nil {
+		return nil, err
+	}
+	favorites.Identities = identities
+
+	licenseKeys, err := s.LicenseKeys().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range licenseKeys {
+		if _, err := DecryptModel(&licenseKeys[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.LicenseKeyItem, licenseKeys, schema); err != nil {
+		return nil, err
+	}
+	favorites.LicenseKeys = licenseKeys
+
+	customItems, err := s.CustomItems().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range customItems {
+		if _, err := DecryptModel(&customItems[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.CustomItemItem, customItems, schema); err != nil {
+		return nil, err
+	}
+	favorites.CustomItems = customItems
+
+	sshKeys, err := s.SSHKeys().FindAll(argsStr, argsInt, schema)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sshKeys {
+		if _, err := DecryptModel(&sshKeys[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := LoadTags(s, model.SSHKeyItem, sshKeys, schema); err != nil {
+		return nil, err
+	}
+	favorites.SSHKeys = sshKeys
+
+	return favorites, nil
+}
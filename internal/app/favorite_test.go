@@ -0,0 +1,32 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavorites(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	_, err := CreateLogin(s, &model.LoginDTO{Title: "PassWall", Password: "secret", Favorite: true}, schema)
+	require.Nil(t, err)
+	_, err = CreateLogin(s, &model.LoginDTO{Title: "Other"}, schema)
+	require.Nil(t, err)
+	note, err := CreateNote(s, &model.NoteDTO{Title: "Note", Note: "text"}, schema)
+	require.Nil(t, err)
+	_, err = UpdateNote(s, note, &model.NoteDTO{Title: "Note", Note: "text", Favorite: true}, schema)
+	require.Nil(t, err)
+
+	favorites, err := Favorites(s, schema)
+	require.Nil(t, err)
+	require.Len(t, favorites.Logins, 1)
+	assert.Equal(t, "secret", favorites.Logins[0].Password, "favorites should be decrypted")
+	require.Len(t, favorites.Notes, 1)
+	assert.Equal(t, "text", favorites.Notes[0].Note)
+	assert.Empty(t, favorites.Servers)
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// ErrFolderCycle represents message for moving a folder into itself or its subfolders
+var ErrFolderCycle = errors.New("a folder can't be moved into itself or its subfolders")
+
+// CreateFolder creates a new folder and saves it to the store
+func CreateFolder(s storage.Store, dto *model.FolderDTO, schema string) (*model.Folder, error) {
+	if dto.ParentID != nil {
+		if _, err := s.Folders().FindByID(*dto.ParentID, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.Folders().Save(model.ToFolder(dto), schema)
+}
+
+// UpdateFolder renames a folder or moves it under another folder
+func UpdateFolder(s storage.Store, folder *model.Folder, dto *model.FolderDTO, schema string) (*model.Folder, error) {
+	if dto.ParentID != nil {
+		if _, err := s.Folders().FindByID(*dto.ParentID, schema); err != nil {
+			return nil, err
+		}
+
+		folders, err := s.Folders().All(schema)
+		if err != nil {
+			return nil, err
+		}
+
+		parents := map[uint]*uint{}
+		for _, f := range folders {
+			parents[f.ID] = f.ParentID
+		}
+
+		// Walk up from the new parent, reaching the folder means a cycle
+		for id := dto.ParentID; id != nil; id = parents[*id] {
+			if *id == folder.ID {
+				return nil, ErrFolderCycle
+			}
+		}
+	}
+
+	folder.Name = dto.Name
+	folder.ParentID = dto.ParentID
+	return s.Folders().Save(folder, schema)
+}
+
+// DeleteFolder deletes a folder, its items and subfolders move to its parent.
+// Items in the trash move along, so they are restored in the right folder.
+func DeleteFolder(s storage.Store, folder *model.Folder, schema string) error {
+	return s.Transaction(func(tx storage.Store) error {
+		folders, err := tx.Folders().All(schema)
+		if err != nil {
+			return err
+		}
+		for i := range folders {
+			if folders[i].ParentID != nil && *folders[i].ParentID == folder.ID {
+				folders[i].ParentID = folder.ParentID
+				if _, err := tx.Folders().Save(&folders[i], schema); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, name := range model.ItemTypes {
+			t, err := findItemType(name)
+			if err != nil {
+				return err
+			}
+			// every item, deleted ones included, is changed since the beginning of time
+			items, err := t.changed(tx, time.Time{}, schema)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				folderID := reflect.ValueOf(item).Elem().FieldByName("FolderID").Interface().(*uint)
+				if folderID == nil || *folderID != folder.ID {
+					continue
+				}
+				if err := moveItem(tx, name, t, item, folder.ParentID, schema); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Folders().Delete(folder.ID, schema)
+	})
+}
+
+// MoveItems moves items of any type into a folder. Every item is checked
+// before anything is moved, so a missing item doesn't leave a half done move.
+func MoveItems(s storage.Store, dto *model.MoveItemsDTO, schema string) error {
+	if dto.FolderID != nil {
+		if _, err := s.Folders().FindByID(*dto.FolderID, schema); err != nil {
+			return err
+		}
+	}
+
+	return s.Transaction(func(tx storage.Store) error {
+		found := make([]interface{}, len(dto.Items))
+		types := make([]itemType, len(dto.Items))
+		for i, ref := range dto.Items {
+			t, err := findItemType(ref.Type)
+			if err != nil {
+				return err
+			}
+			item, err := t.find(tx, ref.ID, schema)
+			if err != nil {
+				return err
+			}
+			found[i], types[i] = item, t
+		}
+
+		for i, item := range found {
+			if err := moveItem(tx, dto.Items[i].Type, types[i], item, dto.FolderID, schema); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// moveItem moves the item into the folder like any other update: it fails if
+// the item changed since it was read, and records a revision and an event.
+func moveItem(s storage.Store, itemType string, t itemType, item interface{}, folderID *uint, schema string) error {
+	value := reflect.ValueOf(item).Elem()
+	id := uint(value.FieldByName("ID").Uint())
+	value.FieldByName("FolderID").Set(reflect.ValueOf(folderID))
+
+	moved, err := t.update(s, item, uint(value.FieldByName("Revision").Uint()), schema)
+	if err != nil {
+		return revisionConflict(s, itemType, id, err, schema)
+	}
+	return recordRevision(s, itemType, id, moved, schema)
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolders(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	missing := uint(42)
+	_, err := CreateFolder(s, &model.FolderDTO{Name: "Orphan", ParentID: &missing}, schema)
+	assert.NotNil(t, err, "parent folder should exist")
+
+	work, err := CreateFolder(s, &model.FolderDTO{Name: "Work"}, schema)
+	require.Nil(t, err)
+	servers, err := CreateFolder(s, &model.FolderDTO{Name: "Servers", ParentID: &work.ID}, schema)
+	require.Nil(t, err)
+
+	_, err = UpdateFolder(s, work, &model.FolderDTO{Name: "Work", ParentID: &servers.ID}, schema)
+	assert.Equal(t, ErrFolderCycle, err)
+	_, err = UpdateFolder(s, work, &model.FolderDTO{Name: "Work", ParentID: &work.ID}, schema)
+	assert.Equal(t, ErrFolderCycle, err)
+
+	login, err := CreateLogin(s, &model.LoginDTO{Title: "PassWall"}, schema)
+	require.Nil(t, err)
+	server, err := CreateServer(s, &model.ServerDTO{Title: "Web"}, schema)
+	require.Nil(t, err)
+
+	err = MoveItems(s, &model.MoveItemsDTO{FolderID: &servers.ID, Items: []model.ItemRef{
+		{Type: model.LoginItem, ID: login.ID},
+		{Type: model.ServerItem, ID: server.ID + 100},
+	}}, schema)
+	assert.NotNil(t, err)
+	login, _ = s.Logins().FindByID(login.ID, schema)
+	assert.Nil(t, login.FolderID, "nothing should move when an item is missing")
+
+	events, cancel := s.Events().Subscribe(schema)
+	defer cancel()
+	err = MoveItems(s, &model.MoveItemsDTO{FolderID: &servers.ID, Items: []model.ItemRef{
+		{Type: model.LoginItem, ID: login.ID},
+		{Type: model.ServerItem, ID: server.ID},
+	}}, schema)
+	require.Nil(t, err)
+	server, _ = s.Servers().FindByID(server.ID, schema)
+	require.NotNil(t, server.FolderID)
+	assert.Equal(t, servers.ID, *server.FolderID)
+	assert.Equal(t, uint(2), server.Revision)
+
+	// moves are updates like the others, with a revision and an event
+	revisions, err := FindRevisions(s, model.ServerItem, server.ID, schema)
+	require.Nil(t, err)
+	assert.Len(t, revisions, 2)
+	for _, id := range []uint{login.ID, server.ID} {
+		e := <-events
+		assert.Equal(t, []interface{}{model.ItemChangedEvent, id}, []interface{}{e.Type, e.ItemID})
+	}
+
+	// Deleting a folder moves its content to the parent, items in the trash too
+	require.Nil(t, DeleteItem(s, model.LoginItem, login.ID, schema))
+	<-events
+	require.Nil(t, DeleteFolder(s, servers, schema))
+	server, _ = s.Servers().FindByID(server.ID, schema)
+	require.NotNil(t, server.FolderID)
+	assert.Equal(t, work.ID, *server.FolderID)
+	require.Nil(t, RestoreItem(s, model.LoginItem, login.ID, schema))
+	login, _ = s.Logins().FindByID(login.ID, schema)
+	require.NotNil(t, login.FolderID)
+	assert.Equal(t, work.ID, *login.FolderID)
+	revisions, err = FindRevisions(s, model.ServerItem, server.ID, schema)
+	require.Nil(t, err)
+	assert.Len(t, revisions, 3)
+
+	sub, err := CreateFolder(s, &model.FolderDTO{Name: "Sub", ParentID: &work.ID}, schema)
+	require.Nil(t, err)
+	require.Nil(t, DeleteFolder(s, work, schema))
+	sub, _ = s.Folders().FindByID(sub.ID, schema)
+	assert.Nil(t, sub.ParentID, "subfolders of a top folder should become top folders")
+	login, _ = s.Logins().FindByID(login.ID, schema)
+	assert.Nil(t, login.FolderID)
+}
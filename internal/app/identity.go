@@ -0,0 +1,116 @@
+package app
+
+import (
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// CreateIdentity creates a new identity and saves it to the store
+func CreateIdentity(s storage.Store, dto *model.IdentityDTO, schema string) (*model.Identity, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
+	rawModel := model.ToIdentity(dto)
+	encModel := EncryptModel(rawModel)
+
+	createdIdentity, err := s.Identities().Save(encModel.(*model.Identity), schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordRevision(s, model.IdentityItem, createdIdentity.ID, createdIdentity, schema); err != nil {
+		return nil, err
+	}
+
+	createdIdentity.TagIDs, err = tagItem(s, model.IdentityItem, createdIdentity.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return createdIdentity, nil
+}
+
+// UpdateIdentity updates the identity with the dto and applies the changes in the store
+func UpdateIdentity(s storage.Store, identity *model.Identity, dto *model.IdentityDTO, schema string) (*model.Identity, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
+	rawModel := model.ToIdentity(dto)
+	encModel := EncryptModel(rawModel).(*model.Identity)
+
+	identity.Title = encModel.Title
+	identity.FirstName = encModel.FirstName
+	identity.MiddleName = encModel.MiddleName
+	identity.LastName = encModel.LastName
+	identity.BirthDate = encModel.BirthDate
+	identity.Company = encModel.Company
+	identity.Email = encModel.Email
+	identity.Phone = encModel.Phone
+	identity.Address1 = encModel.Address1
+	identity.Address2 = encModel.Address2
+	identity.City = encModel.City
+	identity.State = encModel.State
+	identity.PostalCode = encModel.PostalCode
+	identity.Country = encModel.Country
+	identity.PassportNumber = encModel.PassportNumber
+	identity.IDNumber = encModel.IDNumber
+	identity.LicenseNumber = encModel.LicenseNumber
+	identity.Extra = encModel.Extra
+	identity.CustomFields = encModel.CustomFields
+	identity.Favorite = encModel.Favorite
+	identity.FolderID = encModel.FolderID
+	identity.SearchIndex = encModel.SearchIndex
+
+	updatedIdentity, err := s.Identities().Update(identity, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.IdentityItem, identity.ID, err, schema)
+	}
+	if err := recordRevision(s, model.IdentityItem, updatedIdentity.ID, updatedIdentity, schema); err != nil {
+		return nil, err
+	}
+
+	updatedIdentity.TagIDs, err = tagItem(s, model.IdentityItem, updatedIdentity.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedIdentity, nil
+}
+
+// BulkUpdateIdentities updates the identities with the dtos in a single transaction,
+// all of them or none
+func BulkUpdateIdentities(s storage.Store, dtos []model.IdentityDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		identity, err := tx.Identities().FindByID(dtos[i].ID, schema)
+		if err != nil {
+			return dtos[i].ID, err
+		}
+		_, err = UpdateIdentity(tx, identity, &dtos[i], schema)
+		return identity.ID, err
+	})
+}
+
+// BulkCreateIdentities creates the identities of the dtos in a single transaction,
+// all of them or none
+func BulkCreateIdentities(s storage.Store, dtos []model.IdentityDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		createdIdentity, err := CreateIdentity(tx, &dtos[i], schema)
+		if err != nil {
+			return 0, err
+		}
+		return createdIdentity.ID, nil
+	})
+}
+
+// BulkDeleteIdentities moves the identities with the ids to the trash in a single
+// transaction, all of them or none
+func BulkDeleteIdentities(s storage.Store, ids []uint, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(ids), func(tx storage.Store, i int) (uint, error) {
+		identity, err := tx.Identities().FindByID(ids[i], schema)
+		if err != nil {
+			return ids[i], err
+		}
+		return identity.ID, DeleteItem(tx, model.IdentityItem, identity.ID, schema)
+	})
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentity(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+	argsInt := map[string]int{"limit": -1, "offset": -1}
+
+	dto := &model.IdentityDTO{Title: "Personal", FirstName: "John", LastName: "Doe", PassportNumber: "U12345678"}
+	identity, err := CreateIdentity(s, dto, schema)
+	require.Nil(t, err)
+	assert.NotEqual(t, "U12345678", identity.PassportNumber, "the passport number should be encrypted")
+
+	identities, err := s.Identities().FindAll(searchArgs("u12345678"), argsInt, schema)
+	require.Nil(t, err)
+	assert.Len(t, identities, 1)
+
+	dto.Country = "Turkey"
+	identity, err = UpdateIdentity(s, identity, dto, schema)
+	require.Nil(t, err)
+	decrypted, err := DecryptModel(identity)
+	require.Nil(t, err)
+	assert.Equal(t, "Turkey", decrypted.(*model.Identity).Country)
+	assert.Equal(t, "John", decrypted.(*model.Identity).FirstName)
+}
@@ -0,0 +1,310 @@
+package app
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// itemType binds an item type to its model and repository for
+// the operations which work the same way on every type of item
+type itemType struct {
+	titleField string
+	newModel   func() interface{}
+	all        func(s storage.Store, schema string) ([]interface{}, error)
+	findAll    func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error)
+	changed    func(s storage.Store, since time.Time, schema string) ([]interface{}, error)
+	find       func(s storage.Store, id uint, schema string) (interface{}, error)
+	save       func(s storage.Store, item interface{}, schema string) (interface{}, error)
+	update     func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error)
+	// saveEncrypted stores the encrypted fields and the search index only
+	saveEncrypted func(s storage.Store, item interface{}, schema string) (interface{}, error)
+	toDTO         func(item interface{}) interface{}
+}
+
+var itemTypes = map[string]itemType{
+	model.LoginItem: {
+		titleField: "Title",
+		newModel:   func() interface{} { return new(model.Login) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.Logins().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.Logins().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.Logins().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.Logins().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Logins().Save(item.(*model.Login), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.Logins().Update(item.(*model.Login), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Logins().SaveEncrypted(item.(*model.Login), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToLoginDTO(item.(*model.Login)) },
+	},
+	model.CreditCardItem: {
+		titleField: "CardName",
+		newModel:   func() interface{} { return new(model.CreditCard) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.CreditCards().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.CreditCards().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.CreditCards().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.CreditCards().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.CreditCards().Save(item.(*model.CreditCard), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.CreditCards().Update(item.(*model.CreditCard), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.CreditCards().SaveEncrypted(item.(*model.CreditCard), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToCreditCardDTO(item.(*model.CreditCard)) },
+	},
+	model.BankAccountItem: {
+		titleField: "BankName",
+		newModel:   func() interface{} { return new(model.BankAccount) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.BankAccounts().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.BankAccounts().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.BankAccounts().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.BankAccounts().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.BankAccounts().Save(item.(*model.BankAccount), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.BankAccounts().Update(item.(*model.BankAccount), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.BankAccounts().SaveEncrypted(item.(*model.BankAccount), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToBankAccountDTO(item.(*model.BankAccount)) },
+	},
+	model.NoteItem: {
+		titleField: "Title",
+		newModel:   func() interface{} { return new(model.Note) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.Notes().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.Notes().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.Notes().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.Notes().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Notes().Save(item.(*model.Note), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.Notes().Update(item.(*model.Note), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Notes().SaveEncrypted(item.(*model.Note), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToNoteDTO(item.(*model.Note)) },
+	},
+	model.EmailItem: {
+		titleField: "Title",
+		newModel:   func() interface{} { return new(model.Email) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.Emails().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.Emails().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.Emails().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.Emails().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Emails().Save(item.(*model.Email), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.Emails().Update(item.(*model.Email), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Emails().SaveEncrypted(item.(*model.Email), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToEmailDTO(item.(*model.Email)) },
+	},
+	model.ServerItem: {
+		titleField: "Title",
+		newModel:   func() interface{} { return new(model.Server) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.Servers().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.Servers().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.Servers().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.Servers().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Servers().Save(item.(*model.Server), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.Servers().Update(item.(*model.Server), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Servers().SaveEncrypted(item.(*model.Server), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToServerDTO(item.(*model.Server)) },
+	},
+	model.IdentityItem: {
+		titleField: "Title",
+		newModel:   func() interface{} { return new(model.Identity) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.Identities().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.Identities().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.Identities().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.Identities().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Identities().Save(item.(*model.Identity), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.Identities().Update(item.(*model.Identity), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.Identities().SaveEncrypted(item.(*model.Identity), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToIdentityDTO(item.(*model.Identity)) },
+	},
+	model.LicenseKeyItem: {
+		titleField: "Product",
+		newModel:   func() interface{} { return new(model.LicenseKey) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.LicenseKeys().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.LicenseKeys().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.LicenseKeys().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.LicenseKeys().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.LicenseKeys().Save(item.(*model.LicenseKey), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.LicenseKeys().Update(item.(*model.LicenseKey), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.LicenseKeys().SaveEncrypted(item.(*model.LicenseKey), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToLicenseKeyDTO(item.(*model.LicenseKey)) },
+	},
+	model.CustomItemItem: {
+		titleField: "Title",
+		newModel:   func() interface{} { return new(model.CustomItem) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.CustomItems().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.CustomItems().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.CustomItems().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.CustomItems().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.CustomItems().Save(item.(*model.CustomItem), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.CustomItems().Update(item.(*model.CustomItem), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.CustomItems().SaveEncrypted(item.(*model.CustomItem), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToCustomItemDTO(item.(*model.CustomItem)) },
+	},
+	model.SSHKeyItem: {
+		titleField: "Title",
+		newModel:   func() interface{} { return new(model.SSHKey) },
+		all: func(s storage.Store, schema string) ([]interface{}, error) {
+			return pointers(s.SSHKeys().All(schema))
+		},
+		findAll: func(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]interface{}, error) {
+			return pointers(s.SSHKeys().FindAll(argsStr, argsInt, schema))
+		},
+		changed: func(s storage.Store, since time.Time, schema string) ([]interface{}, error) {
+			return pointers(s.SSHKeys().FindAllChanged(since, schema))
+		},
+		find: func(s storage.Store, id uint, schema string) (interface{}, error) {
+			return s.SSHKeys().FindByID(id, schema)
+		},
+		save: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.SSHKeys().Save(item.(*model.SSHKey), schema)
+		},
+		update: func(s storage.Store, item interface{}, revision uint, schema string) (interface{}, error) {
+			return s.SSHKeys().Update(item.(*model.SSHKey), revision, schema)
+		},
+		saveEncrypted: func(s storage.Store, item interface{}, schema string) (interface{}, error) {
+			return s.SSHKeys().SaveEncrypted(item.(*model.SSHKey), schema)
+		},
+		toDTO: func(item interface{}) interface{} { return model.ToSSHKeyDTO(item.(*model.SSHKey)) },
+	},
+}
+
+// pointers returns pointers to the items in a slice
+func pointers(items interface{}, err error) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	value := reflect.ValueOf(items)
+	result := make([]interface{}, value.Len())
+	for i := range result {
+		result[i] = value.Index(i).Addr().Interface()
+	}
+	return result, nil
+}
+
+// findItemType returns the itemType registered for the type name
+func findItemType(name string) (itemType, error) {
+	t, ok := itemTypes[name]
+	if !ok {
+		return itemType{}, ErrUnknownItemType
+	}
+	return t, nil
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Encrypted values are stored as $<key id>$<base64 ciphertext>. The key id
+// is server.passphraseID, set in the configuration next to the passphrase
+// and unrelated to it, so the passphrase can be rotated: values are encrypted
+// with server.passphrase and decrypted with it or with one of
+// server.legacyPassphrases. Values stored before key ids were added are plain
+// base64, values of older ids were tagged with a hash of their passphrase.
+const keyIDSeparator = "$"
+
+// defaultKeyID is the key id of the configurations created before
+// server.passphraseID, new configurations get a random one
+const defaultKeyID = "0"
+
+// errUnknownKey represents message for a value none of the passphrases decrypts
+var errUnknownKey = errors.New("value is encrypted with an unknown passphrase")
+
+// currentKeyID returns the id of the passphrase new values are encrypted with
+func currentKeyID() string {
+	if id := viper.GetString("server.passphraseID"); id != "" {
+		return id
+	}
+	return defaultKeyID
+}
+
+// passphrases returns the current passphrase followed by the legacy ones
+func passphrases() []string {
+	return append([]string{viper.GetString("server.passphrase")}, viper.GetStringSlice("server.legacyPassphrases")...)
+}
+
+// encryptValue encrypts a value with the current passphrase and tags it with its key id
+func encryptValue(value string) string {
+	ciphertext := base64.StdEncoding.EncodeToString(Encrypt(value, viper.GetString("server.passphrase")))
+	return keyIDSeparator + currentKeyID() + keyIDSeparator + ciphertext
+}
+
+// decryptValue decrypts a value with the first passphrase which opens it. The
+// ids of the legacy passphrases aren't configured, the key id only tells
+// whether the value is encrypted with the current passphrase.
+func decryptValue(value string) (string, error) {
+	_, encoded, _ := splitKeyID(value)
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	for _, passphrase := range passphrases() {
+		plain, err := decrypt(ciphertext, passphrase)
+		if err == nil {
+			return string(plain), nil
+		}
+	}
+	return "", errUnknownKey
+}
+
+// encryptedWithCurrentKey reports whether the value is encrypted with the current passphrase
+func encryptedWithCurrentKey(value string) bool {
+	id, _, tagged := splitKeyID(value)
+	return tagged && id == currentKeyID()
+}
+
+// splitKeyID splits a value into its key id and its ciphertext,
+// tagged is false for the values stored without a key id
+func splitKeyID(value string) (id, ciphertext string, tagged bool) {
+	if !strings.HasPrefix(value, keyIDSeparator) {
+		return "", value, false
+	}
+	parts := strings.SplitN(value[len(keyIDSeparator):], keyIDSeparator, 2)
+	if len(parts) != 2 {
+		return "", value, false
+	}
+	return parts[0], parts[1], true
+}
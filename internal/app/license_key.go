@@ -0,0 +1,107 @@
+package app
+
+import (
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// CreateLicenseKey creates a new license key and saves it to the store
+func CreateLicenseKey(s storage.Store, dto *model.LicenseKeyDTO, schema string) (*model.LicenseKey, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
+	rawModel := model.ToLicenseKey(dto)
+	encModel := EncryptModel(rawModel)
+
+	createdLicenseKey, err := s.LicenseKeys().Save(encModel.(*model.LicenseKey), schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordRevision(s, model.LicenseKeyItem, createdLicenseKey.ID, createdLicenseKey, schema); err != nil {
+		return nil, err
+	}
+
+	createdLicenseKey.TagIDs, err = tagItem(s, model.LicenseKeyItem, createdLicenseKey.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return createdLicenseKey, nil
+}
+
+// UpdateLicenseKey updates the license key with the dto and applies the changes in the store
+func UpdateLicenseKey(s storage.Store, licenseKey *model.LicenseKey, dto *model.LicenseKeyDTO, schema string) (*model.LicenseKey, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
+	rawModel := model.ToLicenseKey(dto)
+	encModel := EncryptModel(rawModel).(*model.LicenseKey)
+
+	licenseKey.Product = encModel.Product
+	licenseKey.Version = encModel.Version
+	licenseKey.Licensee = encModel.Licensee
+	licenseKey.Key = encModel.Key
+	licenseKey.Seats = encModel.Seats
+	licenseKey.PurchaseDate = encModel.PurchaseDate
+	licenseKey.ExpiresAt = encModel.ExpiresAt
+	licenseKey.VendorURL = encModel.VendorURL
+	licenseKey.Extra = encModel.Extra
+	licenseKey.CustomFields = encModel.CustomFields
+	licenseKey.Favorite = encModel.Favorite
+	licenseKey.FolderID = encModel.FolderID
+	licenseKey.SearchIndex = encModel.SearchIndex
+
+	updatedLicenseKey, err := s.LicenseKeys().Update(licenseKey, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.LicenseKeyItem, licenseKey.ID, err, schema)
+	}
+	if err := recordRevision(s, model.LicenseKeyItem, updatedLicenseKey.ID, updatedLicenseKey, schema); err != nil {
+		return nil, err
+	}
+
+	updatedLicenseKey.TagIDs, err = tagItem(s, model.LicenseKeyItem, updatedLicenseKey.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedLicenseKey, nil
+}
+
+// BulkUpdateLicenseKeys updates the license keys with the dtos in a single transaction,
+// all of them or none
+func BulkUpdateLicenseKeys(s storage.Store, dtos []model.LicenseKeyDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		licenseKey, err := tx.LicenseKeys().FindByID(dtos[i].ID, schema)
+		if err != nil {
+			return dtos[i].ID, err
+		}
+		_, err = UpdateLicenseKey(tx, licenseKey, &dtos[i], schema)
+		return licenseKey.ID, err
+	})
+}
+
+// BulkCreateLicenseKeys creates the license keys of the dtos in a single transaction,
+// all of them or none
+func BulkCreateLicenseKeys(s storage.Store, dtos []model.LicenseKeyDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		createdLicenseKey, err := CreateLicenseKey(tx, &dtos[i], schema)
+		if err != nil {
+			return 0, err
+		}
+		return createdLicenseKey.ID, nil
+	})
+}
+
+// BulkDeleteLicenseKeys moves the license keys with the ids to the trash in a single
+// transaction, all of them or none
+func BulkDeleteLicenseKeys(s storage.Store, ids []uint, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(ids), func(tx storage.Store, i int) (uint, error) {
+		licenseKey, err := tx.LicenseKeys().FindByID(ids[i], schema)
+		if err != nil {
+			return ids[i], err
+		}
+		return licenseKey.ID, DeleteItem(tx, model.LicenseKeyItem, licenseKey.ID, schema)
+	})
+}
@@ -7,6 +7,13 @@ import (
 
 // CreateLogin creates a login and saves it to the store
 func CreateLogin(s storage.Store, dto *model.LoginDTO, schema string) (*model.Login, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+	if err := validateTOTP(dto.TOTP); err != nil {
+		return nil, err
+	}
+
 	rawLogin := model.ToLogin(dto)
 	encLogin := EncryptModel(rawLogin)
 
@@ -14,6 +21,14 @@ func CreateLogin(s storage.Store, dto *model.LoginDTO, schema string) (*model.Lo
 	if err != nil {
 		return nil, err
 	}
+	if err := recordRevision(s, model.LoginItem, createdLogin.ID, createdLogin, schema); err != nil {
+		return nil, err
+	}
+
+	createdLogin.TagIDs, err = tagItem(s, model.LoginItem, createdLogin.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
 
 	return createdLogin, nil
 }
@@ -21,13 +36,23 @@ func CreateLogin(s storage.Store, dto *model.LoginDTO, schema string) (*model.Lo
 // CreateLogins is needed for import
 func CreateLogins(s storage.Store, dtos []model.LoginDTO, schema string) error {
 	for i := range dtos {
+		if err := validateCustomFields(dtos[i].CustomFields); err != nil {
+			return err
+		}
+		if err := validateTOTP(dtos[i].TOTP); err != nil {
+			return err
+		}
+
 		rawLogin := model.ToLogin(&dtos[i])
 		encLogin := EncryptModel(rawLogin)
 
-		_, err := s.Logins().Save(encLogin.(*model.Login), schema)
+		createdLogin, err := s.Logins().Save(encLogin.(*model.Login), schema)
 		if err != nil {
 			return err
 		}
+		if err := recordRevision(s, model.LoginItem, createdLogin.ID, createdLogin, schema); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -35,6 +60,13 @@ func CreateLogins(s storage.Store, dtos []model.LoginDTO, schema string) error {
 
 // UpdateLogin updates the login with the dto and applies the changes in the store
 func UpdateLogin(s storage.Store, login *model.Login, dto *model.LoginDTO, schema string) (*model.Login, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+	if err := validateTOTP(dto.TOTP); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToLogin(dto)
 	encModel := EncryptModel(rawModel).(*model.Login)
 
@@ -42,12 +74,62 @@ func UpdateLogin(s storage.Store, login *model.Login, dto *model.LoginDTO, schem
 	login.URL = encModel.URL
 	login.Username = encModel.Username
 	login.Password = encModel.Password
+	login.TOTP = encModel.TOTP
 	login.Extra = encModel.Extra
+	login.CustomFields = encModel.CustomFields
+	login.Favorite = encModel.Favorite
+	login.FolderID = encModel.FolderID
+	login.SearchIndex = encModel.SearchIndex
+
+	updatedLogin, err := s.Logins().Update(login, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.LoginItem, login.ID, err, schema)
+	}
+	if err := recordRevision(s, model.LoginItem, updatedLogin.ID, updatedLogin, schema); err != nil {
+		return nil, err
+	}
 
-	updatedLogin, err := s.Logins().Save(login, schema)
+	updatedLogin.TagIDs, err = tagItem(s, model.LoginItem, updatedLogin.ID, dto.TagIDs, schema)
 	if err != nil {
 		return nil, err
 	}
 
 	return updatedLogin, nil
 }
+
+// BulkUpdateLogins updates the logins with the dtos in a single transaction,
+// all of them or none
+func BulkUpdateLogins(s storage.Store, dtos []model.LoginDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		login, err := tx.Logins().FindByID(dtos[i].ID, schema)
+		if err != nil {
+			return dtos[i].ID, err
+		}
+		_, err = UpdateLogin(tx, login, &dtos[i], schema)
+		return login.ID, err
+	})
+}
+
+// BulkCreateLogins creates the logins of the dtos in a single transaction,
+// all of them or none
+func BulkCreateLogins(s storage.Store, dtos []model.LoginDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		createdLogin, err := CreateLogin(tx, &dtos[i], schema)
+		if err != nil {
+			return 0, err
+		}
+		return createdLogin.ID, nil
+	})
+}
+
+// BulkDeleteLogins moves the logins with the ids to the trash in a single
+// transaction, all of them or none
+func BulkDeleteLogins(s storage.Store, ids []uint, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(ids), func(tx storage.Store, i int) (uint, error) {
+		login, err := tx.Logins().FindByID(ids[i], schema)
+		if err != nil {
+			return ids[i], err
+		}
+		return login.ID, DeleteItem(tx, model.LoginItem, login.ID, schema)
+	})
+}
@@ -1,44 +1,124 @@
 package app
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"github.com/passwall/passwall-server/internal/storage"
 )
 
-// MigrateSystemTables runs auto migration for the system models (Token and User),
-// will only add missing fields won't delete/change current data in the store.
+// migrationLockTTL is how long a migration lock is honoured. A lock older
+// than this is considered left behind by a crashed instance.
+const migrationLockTTL = 30 * time.Minute
+
+// MigrateSystemTables applies the pending versioned migrations
+// of the system tables (tokens, users and subscriptions).
 func MigrateSystemTables(s storage.Store) {
-	if err := s.Tokens().Migrate(); err != nil {
-		log.Println(err)
-	}
-	if err := s.Users().Migrate(); err != nil {
-		log.Println(err)
+	migrated, err := s.Migrations().Up("")
+	for _, m := range migrated {
+		log.Printf("system tables migrated to version %d (%s)", m.Version, m.Name)
 	}
-	if err := s.Subscriptions().Migrate(); err != nil {
+	if err != nil {
 		log.Println(err)
 	}
 }
 
-// MigrateUserTables runs auto migration for user models in user schema,
-// will only add missing fields won't delete/change current data in the store.
+// MigrateUserTables applies the pending versioned migrations
+// of the item tables in user schema.
 func MigrateUserTables(s storage.Store, schema string) {
-	if err := s.Logins().Migrate(schema); err != nil {
-		log.Println(err)
+	migrated, err := s.Migrations().Up(schema)
+	for _, m := range migrated {
+		log.Printf("%s migrated to version %d (%s)", schema, m.Version, m.Name)
 	}
-	if err := s.CreditCards().Migrate(schema); err != nil {
-		log.Println(err)
+	if err != nil {
+		log.Printf("%s: %v", schema, err)
 	}
-	if err := s.BankAccounts().Migrate(schema); err != nil {
-		log.Println(err)
+}
+
+// SchemaMigrationError reports the user schemas which failed to migrate,
+// the system tables and the other schemas are migrated
+type SchemaMigrationError struct {
+	Failed []string
+	Total  int
+}
+
+func (e *SchemaMigrationError) Error() string {
+	return fmt.Sprintf("%d of %d user schemas failed to migrate: %v", len(e.Failed), e.Total, e.Failed)
+}
+
+// MigrateAll applies the pending migrations of the system tables and of every
+// user schema. A failing schema doesn't stop the others, the failures are
+// reported in a *SchemaMigrationError. Only one server instance migrates at
+// a time, the others return an error without migrating.
+func MigrateAll(s storage.Store) error {
+	return WithMigrationLock(s, func(refresh func() error) error {
+		return migrateAll(s, refresh)
+	})
+}
+
+// WithMigrationLock runs fn while holding the migration lock, so it doesn't
+// race the migrations of another instance. It returns an error without
+// running fn when another instance holds the lock. Long runs call refresh
+// between their steps so the lock doesn't expire meanwhile.
+func WithMigrationLock(s storage.Store, fn func(refresh func() error) error) error {
+	owner := migrationLockOwner()
+	if err := s.Migrations().Lock(owner, migrationLockTTL); err != nil {
+		return err
 	}
-	if err := s.Notes().Migrate(schema); err != nil {
-		log.Println(err)
+	defer func() {
+		if err := s.Migrations().Unlock(owner); err != nil {
+			log.Printf("could not release migration lock: %v", err)
+		}
+	}()
+
+	return fn(func() error { return s.Migrations().Refresh(owner) })
+}
+
+// migrateAll migrates the system tables and every user schema. It stops when
+// the lock can't be refreshed, another instance may be migrating then.
+func migrateAll(s storage.Store, refresh func() error) error {
+	if _, err := s.Migrations().Up(""); err != nil {
+		return fmt.Errorf("system tables: %w", err)
 	}
-	if err := s.Emails().Migrate(schema); err != nil {
-		log.Println(err)
+
+	users, err := s.Users().All()
+	if err != nil {
+		return err
 	}
-	if err := s.Servers().Migrate(schema); err != nil {
-		log.Println(err)
+
+	log.Printf("migrating %d user schemas", len(users))
+	failed := []string{}
+	for i, user := range users {
+		if user.Schema == "" {
+			continue
+		}
+		if err := refresh(); err != nil {
+			return err
+		}
+
+		migrated, err := s.Migrations().Up(user.Schema)
+		if err != nil {
+			log.Printf("[%d/%d] %s: %v", i+1, len(users), user.Schema, err)
+			failed = append(failed, user.Schema)
+			continue
+		}
+		if len(migrated) > 0 {
+			last := migrated[len(migrated)-1]
+			log.Printf("[%d/%d] %s: %d migrations applied, now at version %d", i+1, len(users), user.Schema, len(migrated), last.Version)
+		}
 	}
+
+	if len(failed) > 0 {
+		return &SchemaMigrationError{Failed: failed, Total: len(users)}
+	}
+	log.Printf("all user schemas are up to date")
+	return nil
+}
+
+// migrationLockOwner identifies this server instance in the migration lock
+func migrationLockOwner() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
 }
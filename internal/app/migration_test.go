@@ -0,0 +1,55 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/config"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/blob"
+	"github.com/passwall/passwall-server/internal/storage/event"
+	"github.com/passwall/passwall-server/internal/storage/migration"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateAll(t *testing.T) {
+	db, err := storage.DBConn(&config.DatabaseConfiguration{Driver: "sqlite", Path: ":memory:"})
+	require.Nil(t, err)
+	defer db.Close()
+	s := storage.New(db, blob.NewMemory(), event.NewLocal())
+
+	MigrateSystemTables(s)
+	for _, schema := range []string{"user1", "user2"} {
+		_, err := s.Users().Save(&model.User{Email: schema + "@passwall.io", Schema: schema})
+		require.Nil(t, err)
+	}
+
+	// Another instance is migrating
+	require.Nil(t, s.Migrations().Lock("other-instance", time.Hour))
+	assert.NotNil(t, MigrateAll(s))
+	assert.NotNil(t, WithMigrationLock(s, func(refresh func() error) error {
+		t.Error("manual migrations shouldn't run while another instance migrates")
+		return nil
+	}))
+	assert.False(t, db.HasTable("user1_logins"))
+	require.Nil(t, s.Migrations().Unlock("other-instance"))
+
+	require.Nil(t, MigrateAll(s))
+	assert.True(t, db.HasTable("user1_logins"))
+	assert.True(t, db.HasTable("user2_servers"))
+
+	// The lock is released after migrating
+	require.Nil(t, s.Migrations().Lock("other-instance", time.Hour))
+	require.Nil(t, s.Migrations().Unlock("other-instance"))
+
+	// An expired lock taken over by another instance stops the migration
+	err = WithMigrationLock(s, func(refresh func() error) error {
+		require.Nil(t, refresh())
+		require.Nil(t, s.Migrations().Lock("other-instance", 0))
+		return migrateAll(s, refresh)
+	})
+	assert.Equal(t, migration.ErrLockLost, err)
+	require.Nil(t, s.Migrations().Unlock("other-instance"))
+}
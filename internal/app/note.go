@@ -7,6 +7,10 @@ import (
 
 // CreateNote creates a new note and saves it to the store
 func CreateNote(s storage.Store, dto *model.NoteDTO, schema string) (*model.Note, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToNote(dto)
 	encModel := EncryptModel(rawModel)
 
@@ -14,22 +18,83 @@ func CreateNote(s storage.Store, dto *model.NoteDTO, schema string) (*model.Note
 	if err != nil {
 		return nil, err
 	}
+	if err := recordRevision(s, model.NoteItem, createdNote.ID, createdNote, schema); err != nil {
+		return nil, err
+	}
+
+	createdNote.TagIDs, err = tagItem(s, model.NoteItem, createdNote.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
 
 	return createdNote, nil
 }
 
 // UpdateNote updates the note with the dto and applies the changes in the store
 func UpdateNote(s storage.Store, note *model.Note, dto *model.NoteDTO, schema string) (*model.Note, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToNote(dto)
 	encModel := EncryptModel(rawModel).(*model.Note)
 
 	note.Title = encModel.Title
 	note.Note = encModel.Note
+	note.CustomFields = encModel.CustomFields
+	note.Favorite = encModel.Favorite
+	note.FolderID = encModel.FolderID
+	note.SearchIndex = encModel.SearchIndex
 
-	updatedNote, err := s.Notes().Save(note, schema)
+	updatedNote, err := s.Notes().Update(note, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.NoteItem, note.ID, err, schema)
+	}
+	if err := recordRevision(s, model.NoteItem, updatedNote.ID, updatedNote, schema); err != nil {
+		return nil, err
+	}
+
+	updatedNote.TagIDs, err = tagItem(s, model.NoteItem, updatedNote.ID, dto.TagIDs, schema)
 	if err != nil {
 		return nil, err
 	}
 
 	return updatedNote, nil
 }
+
+// BulkUpdateNotes updates the notes with the dtos in a single transaction,
+// all of them or none
+func BulkUpdateNotes(s storage.Store, dtos []model.NoteDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		note, err := tx.Notes().FindByID(dtos[i].ID, schema)
+		if err != nil {
+			return dtos[i].ID, err
+		}
+		_, err = UpdateNote(tx, note, &dtos[i], schema)
+		return note.ID, err
+	})
+}
+
+// BulkCreateNotes creates the notes of the dtos in a single transaction,
+// all of them or none
+func BulkCreateNotes(s storage.Store, dtos []model.NoteDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		createdNote, err := CreateNote(tx, &dtos[i], schema)
+		if err != nil {
+			return 0, err
+		}
+		return createdNote.ID, nil
+	})
+}
+
+// BulkDeleteNotes moves the notes with the ids to the trash in a single
+// transaction, all of them or none
+func BulkDeleteNotes(s storage.Store, ids []uint, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(ids), func(tx storage.Store, i int) (uint, error) {
+		note, err := tx.Notes().FindByID(ids[i], schema)
+		if err != nil {
+			return ids[i], err
+		}
+		return note.ID, DeleteItem(tx, model.NoteItem, note.ID, schema)
+	})
+}
@@ -0,0 +1,211 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+)
+
+// ErrRevisionNotFound represents message for a revision which doesn't belong to the item
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// recordRevision saves a revision of the item as it is stored, then removes
+// the oldest revisions of the item exceeding server.revisionLimit. Every save
+// of an item records a revision, so the devices of the user are told here.
+func recordRevision(s storage.Store, itemType string, itemID uint, item interface{}, schema string) error {
+	data, err := json.Marshal(storedFields(item))
+	if err != nil {
+		return err
+	}
+
+	revision := EncryptModel(&model.Revision{
+		ItemType: itemType,
+		ItemID:   itemID,
+		Data:     string(data),
+	}).(*model.Revision)
+
+	if _, err := s.Revisions().Save(revision, schema); err != nil {
+		return err
+	}
+
+	if limit := viper.GetInt("server.revisionLimit"); limit > 0 {
+		if err := s.Revisions().Prune(itemType, itemID, limit, schema); err != nil {
+			return err
+		}
+	}
+
+	publish(s, model.ItemChangedEvent, itemType, itemID, schema)
+	return nil
+}
+
+// FindRevisions lists the revisions of an item, newest first
+func FindRevisions(s storage.Store, itemType string, itemID uint, schema string) ([]*model.RevisionDTO, error) {
+	t, err := findItemType(itemType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.find(s, itemID, schema); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.Revisions().FindAll(itemType, itemID, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionDTOs := make([]*model.RevisionDTO, len(revisions))
+	for i := range revisions {
+		revisionDTOs[i] = model.ToRevisionDTO(&revisions[i])
+	}
+	return revisionDTOs, nil
+}
+
+// FindRevision returns a revision of an item with the item as it was
+func FindRevision(s storage.Store, itemType string, itemID, revisionID uint, schema string) (*model.RevisionDTO, error) {
+	t, revision, item, err := findRevision(s, itemType, itemID, revisionID, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	decItem, err := DecryptModel(item)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionDTO := model.ToRevisionDTO(revision)
+	revisionDTO.Item = t.toDTO(decItem)
+	return revisionDTO, nil
+}
+
+// DiffRevision lists the fields of the item which changed since the revision
+func DiffRevision(s storage.Store, itemType string, itemID, revisionID uint, schema string) ([]model.FieldChange, error) {
+	t, _, item, err := findRevision(s, itemType, itemID, revisionID, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := t.find(s, itemID, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := dtoFields(t, item)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := dtoFields(t, current)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []model.FieldChange{}
+	for field := range cur {
+		if field != "id" && field != "revision" && !reflect.DeepEqual(old[field], cur[field]) {
+			changes = append(changes, model.FieldChange{Field: field, Revision: old[field], Current: cur[field]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// RestoreRevision overwrites the item with the revision, the restore is
+// recorded as a new revision so it can be undone as well
+func RestoreRevision(s storage.Store, itemType string, itemID, revisionID uint, schema string) (interface{}, error) {
+	t, _, item, err := findRevision(s, itemType, itemID, revisionID, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := t.find(s, itemID, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the identity of the current item, take the content of the revision
+	restored := reflect.ValueOf(item).Elem()
+	for _, name := range []string{"ID", "CreatedAt", "DeletedAt"} {
+		restored.FieldByName(name).Set(reflect.ValueOf(current).Elem().FieldByName(name))
+	}
+	// Revisions don't keep the search index
+	if err := reindex(item); err != nil {
+		return nil, err
+	}
+
+	saved, err := t.update(s, item, 0, schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordRevision(s, itemType, itemID, saved, schema); err != nil {
+		return nil, err
+	}
+
+	decItem, err := DecryptModel(saved)
+	if err != nil {
+		return nil, err
+	}
+	return t.toDTO(decItem), nil
+}
+
+// findRevision finds the revision of the item and unmarshals
+// the item it holds, the fields of the item stay encrypted
+func findRevision(s storage.Store, itemType string, itemID, revisionID uint, schema string) (itemType, *model.Revision, interface{}, error) {
+	t, err := findItemType(itemType)
+	if err != nil {
+		return t, nil, nil, err
+	}
+
+	revision, err := s.Revisions().FindByID(revisionID, schema)
+	if err != nil {
+		return t, nil, nil, err
+	}
+	if revision.ItemType != itemType || revision.ItemID != itemID {
+		return t, nil, nil, ErrRevisionNotFound
+	}
+
+	decRevision, err := DecryptModel(revision)
+	if err != nil {
+		return t, nil, nil, err
+	}
+
+	item := t.newModel()
+	if err := json.Unmarshal([]byte(decRevision.(*model.Revision).Data), item); err != nil {
+		return t, nil, nil, err
+	}
+	return t, revision, item, nil
+}
+
+// dtoFields decrypts the item and returns the fields of its DTO
+func dtoFields(t itemType, item interface{}) (map[string]interface{}, error) {
+	decItem, err := DecryptModel(item)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(t.toDTO(decItem))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// storedFields returns a copy of the item without the fields which are
+// not stored in its table, like the tags which are linked separately
+func storedFields(item interface{}) interface{} {
+	copied := reflect.New(reflect.TypeOf(item).Elem())
+	copied.Elem().Set(reflect.ValueOf(item).Elem())
+
+	for i := 0; i < copied.Elem().NumField(); i++ {
+		if copied.Elem().Type().Field(i).Tag.Get("gorm") == "-" {
+			copied.Elem().Field(i).Set(reflect.Zero(copied.Elem().Field(i).Type()))
+		}
+	}
+	return copied.Interface()
+}
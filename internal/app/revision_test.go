@@ -0,0 +1,68 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisions(t *testing.T) {
+	viper.Set("server.revisionLimit", 3)
+	defer viper.Set("server.revisionLimit", nil)
+
+	s := memory.New()
+	schema := "user1"
+
+	login, err := CreateLogin(s, &model.LoginDTO{Title: "PassWall", Username: "patron", Password: "first"}, schema)
+	require.Nil(t, err)
+	_, err = UpdateLogin(s, login, &model.LoginDTO{Title: "PassWall", Username: "patron", Password: "second"}, schema)
+	require.Nil(t, err)
+
+	revisions, err := FindRevisions(s, model.LoginItem, login.ID, schema)
+	require.Nil(t, err)
+	require.Len(t, revisions, 2)
+	first := revisions[1]
+
+	revision, err := FindRevision(s, model.LoginItem, login.ID, first.ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "first", revision.Item.(*model.LoginDTO).Password)
+
+	changes, err := DiffRevision(s, model.LoginItem, login.ID, first.ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []model.FieldChange{{Field: "password", Revision: "first", Current: "second"}}, changes)
+
+	restored, err := RestoreRevision(s, model.LoginItem, login.ID, first.ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "first", restored.(*model.LoginDTO).Password)
+	assert.Equal(t, login.ID, restored.(*model.LoginDTO).ID)
+
+	stored, err := s.Logins().FindByID(login.ID, schema)
+	require.Nil(t, err)
+	decrypted, err := DecryptModel(stored)
+	require.Nil(t, err)
+	assert.Equal(t, "first", decrypted.(*model.Login).Password)
+
+	// Revisions belong to their own item
+	_, err = FindRevision(s, model.NoteItem, login.ID, first.ID, schema)
+	assert.Equal(t, ErrRevisionNotFound, err)
+
+	// The limit keeps the newest revisions
+	_, err = UpdateLogin(s, stored, &model.LoginDTO{Title: "PassWall", Password: "third"}, schema)
+	require.Nil(t, err)
+	revisions, err = FindRevisions(s, model.LoginItem, login.ID, schema)
+	require.Nil(t, err)
+	assert.Len(t, revisions, 3)
+	_, err = FindRevision(s, model.LoginItem, login.ID, first.ID, schema)
+	assert.NotNil(t, err)
+
+	// Purging the item removes its revisions
+	require.Nil(t, s.Logins().Delete(login.ID, schema))
+	require.Nil(t, PurgeItem(s, model.LoginItem, login.ID, schema))
+	remaining, err := s.Revisions().FindAll(model.LoginItem, login.ID, schema)
+	require.Nil(t, err)
+	assert.Empty(t, remaining)
+}
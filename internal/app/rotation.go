@@ -0,0 +1,217 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// RotatePassphrase encrypts again with the current passphrase the values of
+// every user schema which are encrypted with a legacy one. Every schema is
+// rotated in a transaction of its own and values already encrypted with the
+// current passphrase are skipped, so an interrupted rotation resumes where it
+// stopped when it runs again. Failed schemas don't stop the others.
+func RotatePassphrase(s storage.Store) (int, error) {
+	users, err := s.Users().All()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	failed := []string{}
+	for i, user := range users {
+		if user.Schema == "" {
+			continue
+		}
+		rotated, err := RotateSchema(s, user.Schema)
+		if err != nil {
+			log.Printf("[%d/%d] %s: %v", i+1, len(users), user.Schema, err)
+			failed = append(failed, user.Schema)
+			continue
+		}
+		log.Printf("[%d/%d] %s: %d rows encrypted again", i+1, len(users), user.Schema, rotated)
+		count += rotated
+	}
+	if len(failed) > 0 {
+		return count, fmt.Errorf("%d of %d user schemas failed to rotate: %v", len(failed), len(users), failed)
+	}
+	return count, nil
+}
+
+// RotateSchema encrypts again with the current passphrase the items of a
+// user, in the trash too, with their revisions and attachments and the tags.
+// It returns the number of rows which were saved again.
+func RotateSchema(s storage.Store, schema string) (int, error) {
+	count := 0
+	err := s.Transaction(func(tx storage.Store) error {
+		count = 0
+		for _, name := range model.ItemTypes {
+			t, err := findItemType(name)
+			if err != nil {
+				return err
+			}
+			// every item is changed since the beginning of time
+			items, err := t.changed(tx, time.Time{}, schema)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				rotated, err := rotateItem(tx, name, t, item, schema)
+				if err != nil {
+					return fmt.Errorf("%s %d: %w", name, reflect.ValueOf(item).Elem().FieldByName("ID").Uint(), err)
+				}
+				count += rotated
+			}
+		}
+
+		tags, err := tx.Tags().All(schema)
+		if err != nil {
+			return err
+		}
+		for i := range tags {
+			rotated, err := rotateModel(&tags[i])
+			if err != nil {
+				return fmt.Errorf("tag %d: %w", tags[i].ID, err)
+			}
+			if !rotated {
+				continue
+			}
+			if _, err := tx.Tags().Save(&tags[i], schema); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// rotateItem encrypts again the item with its revisions and attachments
+func rotateItem(s storage.Store, itemType string, t itemType, item interface{}, schema string) (int, error) {
+	count := 0
+	rotated, err := rotateModel(item)
+	if err != nil {
+		return 0, err
+	}
+	if rotated {
+		// the item itself didn't change, its update time and revision stay so it isn't synced again
+		if _, err := t.saveEncrypted(s, item, schema); err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	id := uint(reflect.ValueOf(item).Elem().FieldByName("ID").Uint())
+	revisions, err := s.Revisions().FindAll(itemType, id, schema)
+	if err != nil {
+		return 0, err
+	}
+	for i := range revisions {
+		rotated, err := rotateRevision(t, &revisions[i])
+		if err != nil {
+			return 0, err
+		}
+		if !rotated {
+			continue
+		}
+		if _, err := s.Revisions().Save(&revisions[i], schema); err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	attachments, err := s.Attachments().FindAll(itemType, id, schema)
+	if err != nil {
+		return 0, err
+	}
+	for i := range attachments {
+		rotated, err := rotateModel(&attachments[i])
+		if err != nil {
+			return 0, err
+		}
+		if !rotated {
+			continue
+		}
+		if _, err := s.Attachments().Save(&attachments[i], schema); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// rotateModel encrypts the model again unless all of its values are
+// encrypted with the current passphrase, it reports whether it did.
+// The search index is built again too, with the search key.
+func rotateModel(rawModel interface{}) (bool, error) {
+	if encryptedWithCurrentKeys(rawModel) {
+		return false, nil
+	}
+	if _, err := DecryptModel(rawModel); err != nil {
+		return false, err
+	}
+	EncryptModel(rawModel)
+	return true, nil
+}
+
+// rotateRevision encrypts the revision again, the item it holds too
+func rotateRevision(t itemType, revision *model.Revision) (bool, error) {
+	current := encryptedWithCurrentKeys(revision)
+	if _, err := DecryptModel(revision); err != nil {
+		return false, err
+	}
+
+	item := t.newModel()
+	if err := json.Unmarshal([]byte(revision.Data), item); err != nil {
+		return false, err
+	}
+	if current && encryptedWithCurrentKeys(item) {
+		return false, nil
+	}
+
+	if _, err := rotateModel(item); err != nil {
+		return false, err
+	}
+	data, err := json.Marshal(storedFields(item))
+	if err != nil {
+		return false, err
+	}
+	revision.Data = string(data)
+	EncryptModel(revision)
+	return true, nil
+}
+
+// encryptedWithCurrentKeys reports whether every encrypted value of the
+// model is encrypted with the current passphrase
+func encryptedWithCurrentKeys(rawModel interface{}) bool {
+	value := reflect.ValueOf(rawModel).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).Tag.Get("encrypt") != "true" {
+			continue
+		}
+		switch field := value.Field(i).Interface().(type) {
+		case model.CustomFields:
+			for _, customField := range field {
+				if !encryptedWithCurrentKey(customField.Name) || !encryptedWithCurrentKey(customField.Value) {
+					return false
+				}
+			}
+		case model.ItemFields:
+			for _, itemField := range field {
+				if itemField.Secret && !encryptedWithCurrentKey(itemField.Value) {
+					return false
+				}
+			}
+		case string:
+			if !encryptedWithCurrentKey(field) {
+				return false
+			}
+		}
+	}
+	return true
+}
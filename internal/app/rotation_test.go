@@ -0,0 +1,137 @@
+package app
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatePassphrase(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+	_, err := s.Users().Save(&model.User{Email: "user@passwall.io", Schema: schema})
+	require.Nil(t, err)
+
+	current := viper.GetString("server.passphrase")
+	defer viper.Set("server.passphrase", current)
+	defer viper.Set("server.legacyPassphrases", nil)
+	defer viper.Set("server.searchKey", nil)
+	defer viper.Set("server.passphraseID", nil)
+	viper.Set("server.passphrase", "old passphrase")
+	viper.Set("server.passphraseID", "1")
+	viper.Set("server.searchKey", "search key")
+
+	tag, err := CreateTag(s, &model.TagDTO{Name: "Work"}, schema)
+	require.Nil(t, err)
+	login, err := CreateLogin(s, &model.LoginDTO{Title: "GitHub", Password: "first", TagIDs: []uint{tag.ID}}, schema)
+	require.Nil(t, err)
+	login, err = UpdateLogin(s, login, &model.LoginDTO{Title: "GitHub", Password: "second", TagIDs: []uint{tag.ID}}, schema)
+	require.Nil(t, err)
+	_, err = CreateAttachment(s, model.LoginItem, login.ID, "recovery codes", "text/plain", bytes.NewReader([]byte("codes")), schema)
+	require.Nil(t, err)
+	note, err := CreateNote(s, &model.NoteDTO{Title: "Trashed", Note: "text"}, schema)
+	require.Nil(t, err)
+	require.Nil(t, DeleteItem(s, model.NoteItem, note.ID, schema))
+
+	// values stored before key ids were added are plain base64
+	legacy, err := CreateLogin(s, &model.LoginDTO{Title: "Legacy", Password: "legacy"}, schema)
+	require.Nil(t, err)
+	value := reflect.ValueOf(legacy).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).Tag.Get("encrypt") == "true" && value.Field(i).Kind() == reflect.String {
+			_, ciphertext, _ := splitKeyID(value.Field(i).String())
+			value.Field(i).SetString(ciphertext)
+		}
+	}
+	_, err = s.Logins().Save(legacy, schema)
+	require.Nil(t, err)
+
+	// older versions tagged values with a hash of their passphrase
+	hashed, err := CreateLogin(s, &model.LoginDTO{Title: "Hashed", Password: "hashed"}, schema)
+	require.Nil(t, err)
+	_, ciphertext, _ := splitKeyID(hashed.Password)
+	hashed.Password = keyIDSeparator + "1a2b3c4d" + keyIDSeparator + ciphertext
+	_, err = s.Logins().Save(hashed, schema)
+	require.Nil(t, err)
+
+	viper.Set("server.passphrase", "new passphrase")
+	viper.Set("server.passphraseID", "2")
+	viper.Set("server.legacyPassphrases", []string{"old passphrase"})
+
+	stored, err := s.Logins().FindByID(login.ID, schema)
+	require.Nil(t, err)
+	assert.False(t, encryptedWithCurrentKey(stored.Password))
+	decrypted, err := DecryptModel(stored)
+	require.Nil(t, err)
+	assert.Equal(t, "second", decrypted.(*model.Login).Password, "legacy passphrases should still decrypt")
+
+	all := map[string]int{"limit": -1, "offset": -1}
+	hits, _, err := Search(s, searchArgs("github"), all, schema)
+	require.Nil(t, err)
+	assert.Len(t, hits, 1, "the search key shouldn't change with the passphrase")
+
+	count, err := RotatePassphrase(s)
+	require.Nil(t, err)
+	// three logins, their four revisions, the attachment, the note, its revision and the tag
+	assert.Equal(t, 11, count)
+
+	// nothing is left for the legacy passphrase, an interrupted rotation resumes from here
+	count, err = RotatePassphrase(s)
+	require.Nil(t, err)
+	assert.Equal(t, 0, count)
+	viper.Set("server.legacyPassphrases", nil)
+
+	for id, revision := range map[uint]uint{login.ID: 2, legacy.ID: 1, hashed.ID: 1} {
+		stored, err := s.Logins().FindByID(id, schema)
+		require.Nil(t, err)
+		assert.True(t, strings.HasPrefix(stored.Password, keyIDSeparator+"2"+keyIDSeparator))
+		assert.Equal(t, revision, stored.Revision, "rotation shouldn't change revisions")
+	}
+	rotated, err := s.Logins().FindByID(login.ID, schema)
+	require.Nil(t, err)
+	assert.True(t, stored.UpdatedAt.Equal(rotated.UpdatedAt), "rotated items shouldn't be synced again")
+	stored, err = s.Logins().FindByID(legacy.ID, schema)
+	require.Nil(t, err)
+	decrypted, err = DecryptModel(stored)
+	require.Nil(t, err)
+	assert.Equal(t, "legacy", decrypted.(*model.Login).Password)
+	stored, err = s.Logins().FindByID(hashed.ID, schema)
+	require.Nil(t, err)
+	decrypted, err = DecryptModel(stored)
+	require.Nil(t, err)
+	assert.Equal(t, "hashed", decrypted.(*model.Login).Password)
+
+	trashed, err := s.Notes().FindAllDeleted(schema)
+	require.Nil(t, err)
+	require.Len(t, trashed, 1)
+	assert.True(t, encryptedWithCurrentKeys(&trashed[0]), "items in the trash should be rotated too")
+
+	revisions, err := FindRevisions(s, model.LoginItem, login.ID, schema)
+	require.Nil(t, err)
+	restored, err := RestoreRevision(s, model.LoginItem, login.ID, revisions[len(revisions)-1].ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "first", restored.(*model.LoginDTO).Password, "revisions should decrypt with the new passphrase")
+
+	attachments, err := s.Attachments().FindAll(model.LoginItem, login.ID, schema)
+	require.Nil(t, err)
+	require.Len(t, attachments, 1)
+	_, err = DecryptModel(&attachments[0])
+	require.Nil(t, err)
+	assert.Equal(t, "recovery codes", attachments[0].Name)
+
+	tags, err := FindAllTags(s, schema)
+	require.Nil(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "Work", tags[0].Name)
+
+	hits, _, err = Search(s, searchArgs("github"), all, schema)
+	require.Nil(t, err)
+	assert.Len(t, hits, 1, "items should still be found after the rotation")
+}
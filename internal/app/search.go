@@ -0,0 +1,129 @@
+package app
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+// ErrEmptySearch represents message for a search without a query
+var ErrEmptySearch = errors.New("search can't be empty")
+
+// Scores of a search hit by where the search is found
+const (
+	scoreTitleExact  = 100
+	scoreTitlePrefix = 50
+	scoreTitle       = 25
+	scoreFieldExact  = 20
+	scoreField       = 10
+	scoreMatch       = 1
+)
+
+// Search finds the decrypted items of every type matching the search and
+// the filters in argsStr. Hits are ranked by relevance, recently updated
+// first when they score the same, and paginated with argsInt. The total
+// number of hits is returned with the page.
+func Search(s storage.Store, argsStr map[string]string, argsInt map[string]int, schema string) ([]model.SearchHit, int, error) {
+	search := normalize(argsStr["search"])
+	if search == "" {
+		return nil, 0, ErrEmptySearch
+	}
+
+	query := map[string]string{}
+	for key, value := range argsStr {
+		query[key] = value
+	}
+	// hits are ranked, so pages are found by offset only
+	query["order"] = "updated_at desc"
+	delete(query, "cursor")
+	all := map[string]int{"limit": -1, "offset": -1}
+
+	hits := []model.SearchHit{}
+	for _, name := range model.ItemTypes {
+		t := itemTypes[name]
+		items, err := t.findAll(s, query, all, schema)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, item := range items {
+			if _, err := DecryptModel(item); err != nil {
+				return nil, 0, err
+			}
+			value := reflect.ValueOf(item).Elem()
+			hits = append(hits, model.SearchHit{
+				Type:      name,
+				ID:        uint(value.FieldByName("ID").Uint()),
+				Title:     value.FieldByName(t.titleField).String(),
+				Score:     score(value, t.titleField, search),
+				UpdatedAt: value.FieldByName("UpdatedAt").Interface().(time.Time),
+				Item:      item,
+			})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].UpdatedAt.After(hits[j].UpdatedAt)
+	})
+	total := len(hits)
+	hits = paginate(hits, argsInt)
+
+	for _, hit := range hits {
+		if err := LoadTags(s, hit.Type, hit.Item, schema); err != nil {
+			return nil, 0, err
+		}
+	}
+	return hits, total, nil
+}
+
+// score rates how well a decrypted item matches the search. Matches in the
+// title count most, then matches in the fields with blind indexes. Items
+// found some other way, like by their url, get the lowest score.
+func score(value reflect.Value, titleField, search string) int {
+	title := normalize(value.FieldByName(titleField).String())
+	switch {
+	case title == search:
+		return scoreTitleExact
+	case strings.HasPrefix(title, search):
+		return scoreTitlePrefix
+	case strings.Contains(title, search):
+		return scoreTitle
+	}
+
+	best := scoreMatch
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).Tag.Get("search") == "" {
+			continue
+		}
+		text := normalize(value.Field(i).String())
+		switch {
+		case text == search:
+			return scoreFieldExact
+		case strings.Contains(text, search):
+			best = scoreField
+		}
+	}
+	return best
+}
+
+// paginate returns the hits in the page of the offset and limit,
+// -1 cancels either of them like in the repositories
+func paginate(hits []model.SearchHit, argsInt map[string]int) []model.SearchHit {
+	if offset := argsInt["offset"]; offset > 0 {
+		if offset > len(hits) {
+			offset = len(hits)
+		}
+		hits = hits[offset:]
+	}
+	if limit := argsInt["limit"]; limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+)
+
+// Encrypted fields can't be searched with LIKE, so searchable fields get
+// blind indexes: truncated HMACs of their normalized value which are stored
+// space separated in the SearchIndex field of the model.
+// Fields tagged search:"exact" are found by their whole value, fields
+// tagged search:"token" by the prefixes of their words as well.
+const (
+	searchIndexField = "SearchIndex"
+	minPrefixLength  = 3
+	maxPrefixLength  = 16
+	blindIndexBytes  = 8
+)
+
+// blindIndexKey derives the HMAC key of the blind indexes from the search key.
+// It isn't rotated with the passphrase, so the indexes stay valid while the
+// passphrase is rotated. Without a search key, the passphrase is used as it
+// was before the search key was added.
+func blindIndexKey() []byte {
+	key := viper.GetString("server.searchKey")
+	if key == "" {
+		key = viper.GetString("server.passphrase")
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("blind index"))
+	return mac.Sum(nil)
+}
+
+// blindIndex returns the truncated HMAC of the value
+func blindIndex(key []byte, kind, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(kind + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil)[:blindIndexBytes])
+}
+
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// words splits the value on spaces and on punctuation, so "10.0.0.1" can be
+// found by "10.0" and "patron@passwall.io" by "passwall"
+func words(value string) []string {
+	words := strings.Fields(value)
+	words = append(words, strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})...)
+	return words
+}
+
+// prefixes returns the prefixes of the word from minPrefixLength runes,
+// words shorter than that are kept whole
+func prefixes(word string) []string {
+	runes := []rune(word)
+	prefixes := []string{}
+	for i := minPrefixLength; i <= len(runes) && i <= maxPrefixLength; i++ {
+		prefixes = append(prefixes, string(runes[:i]))
+	}
+	if len(runes) < minPrefixLength {
+		prefixes = append(prefixes, word)
+	}
+	return prefixes
+}
+
+// searchIndex builds the blind indexes of the searchable fields of a plain model
+func searchIndex(rawModel interface{}) string {
+	value := reflect.ValueOf(rawModel).Elem()
+	key := blindIndexKey()
+	tokens := map[string]bool{}
+
+	for i := 0; i < value.NumField(); i++ {
+		kind := value.Type().Field(i).Tag.Get("search")
+		text := normalize(value.Field(i).String())
+		if kind == "" || text == "" {
+			continue
+		}
+
+		tokens[blindIndex(key, "exact", text)] = true
+		if kind != "token" {
+			continue
+		}
+		for _, word := range words(text) {
+			for _, prefix := range prefixes(word) {
+				tokens[blindIndex(key, "prefix", prefix)] = true
+			}
+		}
+	}
+
+	if len(tokens) == 0 {
+		return ""
+	}
+	index := make([]string, 0, len(tokens))
+	for token := range tokens {
+		index = append(index, token)
+	}
+	sort.Strings(index)
+	// surrounding spaces let every token be matched with LIKE '% token %'
+	return " " + strings.Join(index, " ") + " "
+}
+
+// setSearchIndex sets the SearchIndex field of a plain model, if it has one
+func setSearchIndex(rawModel interface{}) {
+	field := reflect.ValueOf(rawModel).Elem().FieldByName(searchIndexField)
+	if field.IsValid() {
+		field.SetString(searchIndex(rawModel))
+	}
+}
+
+// SearchIndexQuery returns the blind indexes to look for when searching:
+// the index of the whole search, and the prefix indexes of its words
+// which all have to be in the search index of an item
+func SearchIndexQuery(search string) (exact string, prefixTokens string) {
+	search = normalize(search)
+	if search == "" {
+		return "", ""
+	}
+
+	key := blindIndexKey()
+	tokens := []string{}
+	for _, word := range strings.Fields(search) {
+		if runes := []rune(word); len(runes) > maxPrefixLength {
+			word = string(runes[:maxPrefixLength])
+		}
+		tokens = append(tokens, blindIndex(key, "prefix", word))
+	}
+	return blindIndex(key, "exact", search), strings.Join(tokens, " ")
+}
+
+// reindex rebuilds the search index of a model whose fields are encrypted
+func reindex(encModel interface{}) error {
+	field := reflect.ValueOf(encModel).Elem().FieldByName(searchIndexField)
+	if !field.IsValid() {
+		return nil
+	}
+
+	copied := reflect.New(reflect.TypeOf(encModel).Elem())
+	copied.Elem().Set(reflect.ValueOf(encModel).Elem())
+	decModel, err := DecryptModel(copied.Interface())
+	if err != nil {
+		return err
+	}
+	field.SetString(searchIndex(decModel))
+	return nil
+}
+
+// RebuildSearchIndexes builds the search index of every item of every user
+// again, in the trash too. Items saved before the search index was added
+// become searchable. Only the indexes are written, the update time and
+// revision of the items stay so clients don't sync them again.
+func RebuildSearchIndexes(s storage.Store) (int, error) {
+	users, err := s.Users().All()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, user := range users {
+		if user.Schema == "" {
+			continue
+		}
+		for _, name := range model.ItemTypes {
+			t, err := findItemType(name)
+			if err != nil {
+				return count, err
+			}
+			// every item, deleted ones included, is changed since the beginning of time
+			items, err := t.changed(s, time.Time{}, user.Schema)
+			if err != nil {
+				return count, fmt.Errorf("%s: %w", user.Schema, err)
+			}
+			for _, item := range items {
+				if err := reindex(item); err != nil {
+					return count, fmt.Errorf("%s: %w", user.Schema, err)
+				}
+				if _, err := t.saveEncrypted(s, item, user.Schema); err != nil {
+					return count, fmt.Errorf("%s: %w", user.Schema, err)
+				}
+				count++
+			}
+		}
+	}
+	return count, nil
+}
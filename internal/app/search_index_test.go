@@ -0,0 +1,84 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchArgs(search string) map[string]string {
+	argsStr := map[string]string{"search": search, "order": "id asc"}
+	argsStr["search_exact"], argsStr["search_tokens"] = SearchIndexQuery(search)
+	return argsStr
+}
+
+func TestSearchIndex(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+	argsInt := map[string]int{"limit": -1, "offset": -1}
+
+	login, err := CreateLogin(s, &model.LoginDTO{Title: "Mail", Username: "patron@passwall.io"}, schema)
+	require.Nil(t, err)
+	assert.NotContains(t, login.SearchIndex, "passwall", "the index should not contain plain text")
+
+	for _, search := range []string{"passwall", "PATRON", "patron@passwall.io", "pass patr"} {
+		logins, err := s.Logins().FindAll(searchArgs(search), argsInt, schema)
+		require.Nil(t, err)
+		assert.Len(t, logins, 1, search)
+	}
+	logins, err := s.Logins().FindAll(searchArgs("other"), argsInt, schema)
+	require.Nil(t, err)
+	assert.Empty(t, logins)
+
+	// Exact fields are found by their whole value only
+	_, err = CreateCreditCard(s, &model.CreditCardDTO{CardName: "Card", Type: "Visa"}, schema)
+	require.Nil(t, err)
+	cards, err := s.CreditCards().FindAll(searchArgs("visa"), argsInt, schema)
+	require.Nil(t, err)
+	assert.Len(t, cards, 1)
+	cards, err = s.CreditCards().FindAll(searchArgs("vis"), argsInt, schema)
+	require.Nil(t, err)
+	assert.Empty(t, cards)
+
+	// Items saved without an index are found after reindexing
+	_, err = s.Users().Save(&model.User{Email: "patron@passwall.io", Schema: schema})
+	require.Nil(t, err)
+	login.SearchIndex = ""
+	_, err = s.Logins().Save(login, schema)
+	require.Nil(t, err)
+	logins, err = s.Logins().FindAll(searchArgs("passwall"), argsInt, schema)
+	require.Nil(t, err)
+	assert.Empty(t, logins)
+	stored, err := s.Logins().FindByID(login.ID, schema)
+	require.Nil(t, err)
+
+	// items in the trash are indexed too, they are searchable once restored
+	trashed, err := CreateLogin(s, &model.LoginDTO{Title: "Trashed", Username: "ghost"}, schema)
+	require.Nil(t, err)
+	require.Nil(t, DeleteItem(s, model.LoginItem, trashed.ID, schema))
+	deleted, err := s.Logins().FindAllDeleted(schema)
+	require.Nil(t, err)
+	require.Len(t, deleted, 1)
+	deleted[0].SearchIndex = ""
+	_, err = s.Logins().Save(&deleted[0], schema)
+	require.Nil(t, err)
+
+	count, err := RebuildSearchIndexes(s)
+	require.Nil(t, err)
+	assert.Equal(t, 3, count)
+	logins, err = s.Logins().FindAll(searchArgs("passwall"), argsInt, schema)
+	require.Nil(t, err)
+	require.Len(t, logins, 1)
+	assert.True(t, strings.HasPrefix(logins[0].SearchIndex, " "))
+	assert.True(t, stored.UpdatedAt.Equal(logins[0].UpdatedAt), "reindexed items shouldn't be synced again")
+	assert.Equal(t, stored.Revision, logins[0].Revision)
+
+	require.Nil(t, RestoreItem(s, model.LoginItem, trashed.ID, schema))
+	logins, err = s.Logins().FindAll(searchArgs("ghost"), argsInt, schema)
+	require.Nil(t, err)
+	assert.Len(t, logins, 1)
+}
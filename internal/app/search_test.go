@@ -0,0 +1,47 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+	argsInt := map[string]int{"limit": -1, "offset": -1}
+
+	server, err := CreateServer(s, &model.ServerDTO{Title: "Deploy", Username: "github-deploy"}, schema)
+	require.Nil(t, err)
+	note, err := CreateNote(s, &model.NoteDTO{Title: "Notes about GitHub", Note: "text"}, schema)
+	require.Nil(t, err)
+	login, err := CreateLogin(s, &model.LoginDTO{Title: "GitHub", Password: "secret"}, schema)
+	require.Nil(t, err)
+	_, err = CreateLogin(s, &model.LoginDTO{Title: "GitLab"}, schema)
+	require.Nil(t, err)
+
+	hits, total, err := Search(s, searchArgs("github"), argsInt, schema)
+	require.Nil(t, err)
+	require.Len(t, hits, 3)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, model.LoginItem, hits[0].Type)
+	assert.Equal(t, login.ID, hits[0].ID)
+	assert.Equal(t, "secret", hits[0].Item.(*model.Login).Password, "hits should be decrypted")
+	assert.Equal(t, model.NoteItem, hits[1].Type)
+	assert.Equal(t, note.ID, hits[1].ID)
+	assert.Equal(t, model.ServerItem, hits[2].Type)
+	assert.Equal(t, server.ID, hits[2].ID)
+
+	// Pagination applies to the ranked hits
+	hits, total, err = Search(s, searchArgs("github"), map[string]int{"limit": 1, "offset": 1}, schema)
+	require.Nil(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, model.NoteItem, hits[0].Type)
+
+	_, _, err = Search(s, searchArgs(" "), argsInt, schema)
+	assert.Equal(t, ErrEmptySearch, err)
+}
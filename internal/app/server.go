@@ -7,6 +7,10 @@ import (
 
 // CreateServer creates a server and saves it to the store
 func CreateServer(s storage.Store, dto *model.ServerDTO, schema string) (*model.Server, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToServer(dto)
 	encModel := EncryptModel(rawModel)
 
@@ -14,12 +18,24 @@ func CreateServer(s storage.Store, dto *model.ServerDTO, schema string) (*model.
 	if err != nil {
 		return nil, err
 	}
+	if err := recordRevision(s, model.ServerItem, createdServer.ID, createdServer, schema); err != nil {
+		return nil, err
+	}
+
+	createdServer.TagIDs, err = tagItem(s, model.ServerItem, createdServer.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
 
 	return createdServer, nil
 }
 
 // UpdateServer updates the server with the dto and applies the changes in the store
 func UpdateServer(s storage.Store, server *model.Server, dto *model.ServerDTO, schema string) (*model.Server, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+
 	rawModel := model.ToServer(dto)
 	encModel := EncryptModel(rawModel).(*model.Server)
 
@@ -33,10 +49,59 @@ func UpdateServer(s storage.Store, server *model.Server, dto *model.ServerDTO, s
 	server.AdminUsername = encModel.AdminUsername
 	server.AdminPassword = encModel.AdminPassword
 	server.Extra = encModel.Extra
+	server.CustomFields = encModel.CustomFields
+	server.Favorite = encModel.Favorite
+	server.FolderID = encModel.FolderID
+	server.SearchIndex = encModel.SearchIndex
 
-	updatedServer, err := s.Servers().Save(server, schema)
+	updatedServer, err := s.Servers().Update(server, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.ServerItem, server.ID, err, schema)
+	}
+	if err := recordRevision(s, model.ServerItem, updatedServer.ID, updatedServer, schema); err != nil {
+		return nil, err
+	}
+	updatedServer.TagIDs, err = tagItem(s, model.ServerItem, updatedServer.ID, dto.TagIDs, schema)
 	if err != nil {
 		return nil, err
 	}
+
 	return updatedServer, nil
 }
+
+// BulkUpdateServers updates the servers with the dtos in a single transaction,
+// all of them or none
+func BulkUpdateServers(s storage.Store, dtos []model.ServerDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		server, err := tx.Servers().FindByID(dtos[i].ID, schema)
+		if err != nil {
+			return dtos[i].ID, err
+		}
+		_, err = UpdateServer(tx, server, &dtos[i], schema)
+		return server.ID, err
+	})
+}
+
+// BulkCreateServers creates the servers of the dtos in a single transaction,
+// all of them or none
+func BulkCreateServers(s storage.Store, dtos []model.ServerDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		createdServer, err := CreateServer(tx, &dtos[i], schema)
+		if err != nil {
+			return 0, err
+		}
+		return createdServer.ID, nil
+	})
+}
+
+// BulkDeleteServers moves the servers with the ids to the trash in a single
+// transaction, all of them or none
+func BulkDeleteServers(s storage.Store, ids []uint, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(ids), func(tx storage.Store, i int) (uint, error) {
+		server, err := tx.Servers().FindByID(ids[i], schema)
+		if err != nil {
+			return ids[i], err
+		}
+		return server.ID, DeleteItem(tx, model.ServerItem, server.ID, schema)
+	})
+}
@@ -0,0 +1,213 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrInvalidSSHKey is returned when the keys of an SSH key can't be parsed,
+// or when its server doesn't exist
+var ErrInvalidSSHKey = errors.New("invalid ssh key")
+
+// checkSSHKey checks the keys and the server of an SSH key. The public key is
+// derived from the private key when it is missing and must match it when both
+// are given. The fingerprint is always computed from the public key.
+func checkSSHKey(s storage.Store, dto *model.SSHKeyDTO, schema string) error {
+	if dto.ServerID != nil {
+		if _, err := s.Servers().FindByID(*dto.ServerID, schema); err != nil {
+			return fmt.Errorf("%w: server %d not found", ErrInvalidSSHKey, *dto.ServerID)
+		}
+	}
+
+	dto.Fingerprint = ""
+	var privatePublicKey ssh.PublicKey
+	if strings.TrimSpace(dto.PrivateKey) != "" {
+		var err error
+		privatePublicKey, err = privateKeyPublicKey(dto.PrivateKey, dto.Passphrase)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSSHKey, err)
+		}
+	}
+	if strings.TrimSpace(dto.PublicKey) == "" && privatePublicKey != nil {
+		dto.PublicKey = authorizedKey(privatePublicKey, dto.Comment)
+	}
+	if strings.TrimSpace(dto.PublicKey) == "" {
+		return nil
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(dto.PublicKey))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSSHKey, err)
+	}
+	if privatePublicKey != nil && !bytes.Equal(publicKey.Marshal(), privatePublicKey.Marshal()) {
+		return fmt.Errorf("%w: the public key doesn't match the private key", ErrInvalidSSHKey)
+	}
+	dto.Fingerprint = ssh.FingerprintSHA256(publicKey)
+	return nil
+}
+
+// privateKeyPublicKey returns the public key of a private key. Without its
+// passphrase, the public key stored next to an encrypted OpenSSH private key
+// is returned, other encrypted private keys return nil.
+func privateKeyPublicKey(privateKey, passphrase string) (ssh.PublicKey, error) {
+	var key interface{}
+	var err error
+	if passphrase != "" {
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase([]byte(privateKey), []byte(passphrase))
+	} else {
+		key, err = ssh.ParseRawPrivateKey([]byte(privateKey))
+	}
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return missing.PublicKey, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return signer.PublicKey(), nil
+}
+
+// authorizedKey returns the public key in the authorized_keys format
+func authorizedKey(publicKey ssh.PublicKey, comment string) string {
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey)))
+	if comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+// GenerateSSHKey generates a new key pair and saves it as an SSH key, the
+// private key is encrypted with the passphrase of the request if it has one
+func GenerateSSHKey(s storage.Store, dto *model.SSHKeyGenerateDTO, schema string) (*model.SSHKey, error) {
+	pair, err := generateSSHKeyPair(strings.ToLower(dto.Type), dto.Bits, dto.Passphrase, dto.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateSSHKey(s, &model.SSHKeyDTO{
+		Title:      dto.Title,
+		PrivateKey: pair.privateKey,
+		Passphrase: dto.Passphrase,
+		PublicKey:  authorizedKey(pair.publicKey, dto.Comment),
+		Comment:    dto.Comment,
+		ServerID:   dto.ServerID,
+		FolderID:   dto.FolderID,
+		TagIDs:     dto.TagIDs,
+	}, schema)
+}
+
+// CreateSSHKey creates a new SSH key and saves it to the store
+func CreateSSHKey(s storage.Store, dto *model.SSHKeyDTO, schema string) (*model.SSHKey, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+	if err := checkSSHKey(s, dto, schema); err != nil {
+		return nil, err
+	}
+
+	rawModel := model.ToSSHKey(dto)
+	encModel := EncryptModel(rawModel)
+
+	createdSSHKey, err := s.SSHKeys().Save(encModel.(*model.SSHKey), schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordRevision(s, model.SSHKeyItem, createdSSHKey.ID, createdSSHKey, schema); err != nil {
+		return nil, err
+	}
+
+	createdSSHKey.TagIDs, err = tagItem(s, model.SSHKeyItem, createdSSHKey.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return createdSSHKey, nil
+}
+
+// UpdateSSHKey updates the SSH key with the dto and applies the changes in the store
+func UpdateSSHKey(s storage.Store, sshKey *model.SSHKey, dto *model.SSHKeyDTO, schema string) (*model.SSHKey, error) {
+	if err := validateCustomFields(dto.CustomFields); err != nil {
+		return nil, err
+	}
+	if err := checkSSHKey(s, dto, schema); err != nil {
+		return nil, err
+	}
+
+	rawModel := model.ToSSHKey(dto)
+	encModel := EncryptModel(rawModel).(*model.SSHKey)
+
+	sshKey.Title = encModel.Title
+	sshKey.PrivateKey = encModel.PrivateKey
+	sshKey.Passphrase = encModel.Passphrase
+	sshKey.PublicKey = encModel.PublicKey
+	sshKey.Fingerprint = encModel.Fingerprint
+	sshKey.Comment = encModel.Comment
+	sshKey.ServerID = encModel.ServerID
+	sshKey.Extra = encModel.Extra
+	sshKey.CustomFields = encModel.CustomFields
+	sshKey.Favorite = encModel.Favorite
+	sshKey.FolderID = encModel.FolderID
+	sshKey.SearchIndex = encModel.SearchIndex
+
+	updatedSSHKey, err := s.SSHKeys().Update(sshKey, dto.Revision, schema)
+	if err != nil {
+		return nil, revisionConflict(s, model.SSHKeyItem, sshKey.ID, err, schema)
+	}
+	if err := recordRevision(s, model.SSHKeyItem, updatedSSHKey.ID, updatedSSHKey, schema); err != nil {
+		return nil, err
+	}
+
+	updatedSSHKey.TagIDs, err = tagItem(s, model.SSHKeyItem, updatedSSHKey.ID, dto.TagIDs, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedSSHKey, nil
+}
+
+// BulkUpdateSSHKeys updates the SSH keys with the dtos in a single transaction,
+// all of them or none
+func BulkUpdateSSHKeys(s storage.Store, dtos []model.SSHKeyDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		sshKey, err := tx.SSHKeys().FindByID(dtos[i].ID, schema)
+		if err != nil {
+			return dtos[i].ID, err
+		}
+		_, err = UpdateSSHKey(tx, sshKey, &dtos[i], schema)
+		return sshKey.ID, err
+	})
+}
+
+// BulkCreateSSHKeys creates the SSH keys of the dtos in a single transaction,
+// all of them or none
+func BulkCreateSSHKeys(s storage.Store, dtos []model.SSHKeyDTO, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(dtos), func(tx storage.Store, i int) (uint, error) {
+		createdSSHKey, err := CreateSSHKey(tx, &dtos[i], schema)
+		if err != nil {
+			return 0, err
+		}
+		return createdSSHKey.ID, nil
+	})
+}
+
+// BulkDeleteSSHKeys moves the SSH keys with the ids to the trash in a single
+// transaction, all of them or none
+func BulkDeleteSSHKeys(s storage.Store, ids []uint, schema string) ([]model.BulkResult, error) {
+	return bulk(s, len(ids), func(tx storage.Store, i int) (uint, error) {
+		sshKey, err := tx.SSHKeys().FindByID(ids[i], schema)
+		if err != nil {
+			return ids[i], err
+		}
+		return sshKey.ID, DeleteItem(tx, model.SSHKeyItem, sshKey.ID, schema)
+	})
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestGenerateSSHKey(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	server, err := CreateServer(s, &model.ServerDTO{Title: "Production"}, schema)
+	require.Nil(t, err)
+
+	tests := []struct {
+		dto     model.SSHKeyGenerateDTO
+		keyType string
+	}{
+		{model.SSHKeyGenerateDTO{Title: "Deploy", Comment: "deploy@passwall.io", ServerID: &server.ID}, ssh.KeyAlgoED25519},
+		{model.SSHKeyGenerateDTO{Title: "Protected", Type: "ed25519", Passphrase: "dummypassphrase"}, ssh.KeyAlgoED25519},
+		{model.SSHKeyGenerateDTO{Title: "Legacy", Type: "rsa", Bits: 2048, Passphrase: "dummypassphrase"}, ssh.KeyAlgoRSA},
+	}
+	for _, tt := range tests {
+		sshKey, err := GenerateSSHKey(s, &tt.dto, schema)
+		require.Nil(t, err, tt.dto.Title)
+		assert.False(t, strings.Contains(sshKey.PrivateKey, "PRIVATE KEY"), "the private key should be encrypted")
+
+		decrypted, err := DecryptModel(sshKey)
+		require.Nil(t, err)
+		dto := model.ToSSHKeyDTO(decrypted.(*model.SSHKey))
+
+		publicKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(dto.PublicKey))
+		require.Nil(t, err)
+		assert.Equal(t, tt.keyType, publicKey.Type())
+		assert.Equal(t, tt.dto.Comment, comment)
+		assert.Equal(t, ssh.FingerprintSHA256(publicKey), dto.Fingerprint)
+
+		// the private key can be read back by ssh, with the passphrase if it has one
+		var key interface{}
+		if tt.dto.Passphrase == "" {
+			key, err = ssh.ParseRawPrivateKey([]byte(dto.PrivateKey))
+		} else {
+			_, err = ssh.ParseRawPrivateKey([]byte(dto.PrivateKey))
+			assert.IsType(t, &ssh.PassphraseMissingError{}, err)
+			key, err = ssh.ParseRawPrivateKeyWithPassphrase([]byte(dto.PrivateKey), []byte(tt.dto.Passphrase))
+			assertSSHKeyKDF(t, dto.PrivateKey)
+		}
+		require.Nil(t, err, tt.dto.Title)
+		switch k := key.(type) {
+		case *ed25519.PrivateKey:
+			assert.Equal(t, publicKey.(ssh.CryptoPublicKey).CryptoPublicKey(), k.Public())
+		case *rsa.PrivateKey:
+			assert.Equal(t, 2048, k.N.BitLen())
+			assert.Equal(t, publicKey.(ssh.CryptoPublicKey).CryptoPublicKey(), k.Public())
+		default:
+			t.Fatalf("unexpected key %T", key)
+		}
+	}
+
+	for _, dto := range []model.SSHKeyGenerateDTO{
+		{Title: "Unknown type", Type: "dsa"},
+		{Title: "Weak", Type: "rsa", Bits: 1024},
+		{Title: "Unknown server", ServerID: new(uint)},
+	} {
+		_, err := GenerateSSHKey(s, &dto, schema)
+		assert.True(t, errors.Is(err, ErrInvalidSSHKey), dto.Title)
+	}
+}
+
+// assertSSHKeyKDF checks that a private key is protected like ssh-keygen does,
+// see https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.key
+func assertSSHKeyKDF(t *testing.T, privateKey string) {
+	block, _ := pem.Decode([]byte(privateKey))
+	require.NotNil(t, block)
+	magic := "openssh-key-v1\x00"
+	require.True(t, strings.HasPrefix(string(block.Bytes), magic))
+
+	var header struct {
+		CipherName string
+		KdfName    string
+		KdfOptions string
+		Rest       []byte `ssh:"rest"`
+	}
+	require.Nil(t, ssh.Unmarshal(block.Bytes[len(magic):], &header))
+	var options struct {
+		Salt   []byte
+		Rounds uint32
+	}
+	require.Nil(t, ssh.Unmarshal([]byte(header.KdfOptions), &options))
+	assert.Equal(t, "aes256-ctr", header.CipherName)
+	assert.Equal(t, "bcrypt", header.KdfName)
+	assert.GreaterOrEqual(t, options.Rounds, uint32(16))
+	assert.Len(t, options.Salt, 16)
+}
+
+func TestSSHKey(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+	argsInt := map[string]int{"limit": -1, "offset": -1}
+
+	pair, err := generateSSHKeyPair("ed25519", 0, "", "")
+	require.Nil(t, err)
+
+	// the public key and the fingerprint come from the private key
+	dto := &model.SSHKeyDTO{Title: "Imported", PrivateKey: pair.privateKey, Comment: "backup@passwall.io"}
+	sshKey, err := CreateSSHKey(s, dto, schema)
+	require.Nil(t, err)
+	assert.Equal(t, authorizedKey(pair.publicKey, "backup@passwall.io"), sshKey.PublicKey)
+	assert.Equal(t, ssh.FingerprintSHA256(pair.publicKey), sshKey.Fingerprint)
+
+	sshKeys, err := s.SSHKeys().FindAll(searchArgs("backup"), argsInt, schema)
+	require.Nil(t, err)
+	assert.Len(t, sshKeys, 1)
+	sshKeys, err = s.SSHKeys().FindAll(searchArgs(sshKey.Fingerprint), argsInt, schema)
+	require.Nil(t, err)
+	assert.Len(t, sshKeys, 1)
+
+	_, err = UpdateSSHKey(s, sshKey, &model.SSHKeyDTO{Title: "Imported", PublicKey: "ssh-ed25519 not-a-key"}, schema)
+	assert.True(t, errors.Is(err, ErrInvalidSSHKey))
+	_, err = UpdateSSHKey(s, sshKey, &model.SSHKeyDTO{Title: "Imported", PrivateKey: "not a key"}, schema)
+	assert.True(t, errors.Is(err, ErrInvalidSSHKey))
+
+	// the public key must be the one of the private key
+	other, err := generateSSHKeyPair("ed25519", 0, "", "")
+	require.Nil(t, err)
+	_, err = UpdateSSHKey(s, sshKey, &model.SSHKeyDTO{Title: "Mixed", PrivateKey: pair.privateKey, PublicKey: authorizedKey(other.publicKey, "")}, schema)
+	assert.True(t, errors.Is(err, ErrInvalidSSHKey))
+	sshKey, err = UpdateSSHKey(s, sshKey, &model.SSHKeyDTO{Title: "Both", PrivateKey: pair.privateKey, PublicKey: authorizedKey(pair.publicKey, "")}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, ssh.FingerprintSHA256(pair.publicKey), sshKey.Fingerprint)
+
+	sshKey, err = UpdateSSHKey(s, sshKey, &model.SSHKeyDTO{Title: "Public only", PublicKey: authorizedKey(pair.publicKey, "")}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, ssh.FingerprintSHA256(pair.publicKey), sshKey.Fingerprint)
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// defaultRSABits is the size of the rsa keys generated like ssh-keygen does
+const defaultRSABits = 4096
+
+// sshKeyPair is a generated key pair, the private key in the OpenSSH format
+type sshKeyPair struct {
+	privateKey string
+	publicKey  ssh.PublicKey
+}
+
+// generateSSHKeyPair generates an ed25519 or rsa key pair. The private key
+// is encrypted with the passphrase unless it is empty.
+func generateSSHKeyPair(keyType string, bits int, passphrase, comment string) (*sshKeyPair, error) {
+	var key interface{}
+	switch keyType {
+	case "", "ed25519":
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key = private
+	case "rsa":
+		if bits == 0 {
+			bits = defaultRSABits
+		}
+		if bits != 2048 && bits != 3072 && bits != 4096 {
+			return nil, fmt.Errorf("%w: rsa keys should have 2048, 3072 or 4096 bits", ErrInvalidSSHKey)
+		}
+		private, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, err
+		}
+		key = private
+	default:
+		return nil, fmt.Errorf("%w: type %q should be ed25519 or rsa", ErrInvalidSSHKey, keyType)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, err
+	}
+	// the OpenSSH format of ssh-keygen, encrypted with aes256-ctr and bcrypt_pbkdf
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(key, comment)
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, comment, []byte(passphrase))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sshKeyPair{privateKey: string(pem.EncodeToMemory(block)), publicKey: signer.PublicKey()}, nil
+}
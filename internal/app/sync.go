@@ -0,0 +1,174 @@
+package app
+
+import (
+	"encoding/base64"
+	"errors"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+)
+
+// ErrInvalidSyncToken is returned when a sync token wasn't made by Sync
+var ErrInvalidSyncToken = errors.New("invalid sync token")
+
+// syncOverlap is subtracted from the time of a sync token, so the items saved
+// by transactions which were still running during the last sync are not
+// missed. Clients apply the changes by id, receiving some twice is harmless.
+const syncOverlap = 5 * time.Second
+
+// encodeSyncToken returns the token of a sync started at t
+func encodeSyncToken(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.UTC().Format(time.RFC3339Nano)))
+}
+
+// decodeSyncToken returns the time the sync of the token started at, in the
+// zone times are stored in. The empty token asks for everything.
+func decodeSyncToken(token string) (time.Time, error) {
+	if token == "" {
+		return time.Time{}, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, ErrInvalidSyncToken
+	}
+	t, err := time.Parse(time.RFC3339Nano, string(decoded))
+	if err != nil {
+		return time.Time{}, ErrInvalidSyncToken
+	}
+	return dialect.Time(t.Add(-syncOverlap)), nil
+}
+
+// tombstoneRetention returns how long the tombstones of purged items are
+// kept, 0 keeps them forever
+func tombstoneRetention() time.Duration {
+	retention := viper.GetString("server.tombstoneRetention")
+	if retention == "" || retention == "0" {
+		return 0
+	}
+	return resolveTokenExpireDuration(retention)
+}
+
+// Sync returns the items of every type created, updated or deleted since the
+// sync of the token, with the token of this sync. Soft deleted and purged
+// items are both reported as deleted. A token older than the tombstones which
+// are kept could miss purged items, it gets a full resync instead.
+func Sync(s storage.Store, token string, schema string) (*model.Sync, error) {
+	since, err := decodeSyncToken(token)
+	if err != nil {
+		return nil, err
+	}
+	full := since.IsZero()
+	if retention := tombstoneRetention(); !full && retention > 0 && since.Before(time.Now().Add(-retention)) {
+		since, full = time.Time{}, true
+	}
+
+	sync := &model.Sync{
+		Token:   encodeSyncToken(time.Now()),
+		Full:    full,
+		Created: []model.SyncItem{},
+		Updated: []model.SyncItem{},
+		Deleted: []model.Tombstone{},
+	}
+	for _, name := range model.ItemTypes {
+		t := itemTypes[name]
+		items, err := t.changed(s, since, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		saved := []interface{}{}
+		for _, item := range items {
+			value := reflect.ValueOf(item).Elem()
+			id := uint(value.FieldByName("ID").Uint())
+			if deletedAt := value.FieldByName("DeletedAt").Interface().(*time.Time); deletedAt != nil {
+				sync.Deleted = append(sync.Deleted, model.Tombstone{ItemType: name, ItemID: id, CreatedAt: *deletedAt})
+				continue
+			}
+			if _, err := DecryptModel(item); err != nil {
+				return nil, err
+			}
+			saved = append(saved, item)
+		}
+		if err := LoadTags(s, name, saved, schema); err != nil {
+			return nil, err
+		}
+
+		for _, item := range saved {
+			value := reflect.ValueOf(item).Elem()
+			syncItem := model.SyncItem{
+				Type:      name,
+				ID:        uint(value.FieldByName("ID").Uint()),
+				UpdatedAt: value.FieldByName("UpdatedAt").Interface().(time.Time),
+				Item:      t.toDTO(item),
+			}
+			if value.FieldByName("CreatedAt").Interface().(time.Time).After(since) {
+				sync.Created = append(sync.Created, syncItem)
+			} else {
+				sync.Updated = append(sync.Updated, syncItem)
+			}
+		}
+	}
+
+	purged, err := s.Tombstones().FindAllSince(since, schema)
+	if err != nil {
+		return nil, err
+	}
+	sync.Deleted = append(sync.Deleted, purged...)
+
+	return sync, nil
+}
+
+// PruneTombstones removes the tombstones of the items of any user which were
+// purged longer than the retention period ago
+func PruneTombstones(s storage.Store, retention time.Duration) error {
+	users, err := s.Users().All()
+	if err != nil {
+		return err
+	}
+
+	before := time.Now().Add(-retention)
+	for _, user := range users {
+		if user.Schema == "" {
+			continue
+		}
+		if err := s.Tombstones().DeleteBefore(before, user.Schema); err != nil {
+			log.Printf("%s: could not prune tombstones: %v", user.Schema, err)
+		}
+	}
+	return nil
+}
+
+// StartTombstonePrune prunes the expired tombstones periodically.
+// A retention of 0 keeps the tombstones forever.
+func StartTombstonePrune(s storage.Store) {
+	retention := tombstoneRetention()
+	if retention == 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if err := PruneTombstones(s, retention); err != nil {
+				log.Printf("could not prune tombstones: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// recordTombstones records the purge of the items for the clients to sync
+func recordTombstones(s storage.Store, itemType string, ids []uint, schema string) error {
+	for _, id := range ids {
+		if _, err := s.Tombstones().Save(&model.Tombstone{ItemType: itemType, ItemID: id}, schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
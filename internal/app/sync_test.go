@@ -0,0 +1,138 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/config"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/blob"
+	"github.com/passwall/passwall-server/internal/storage/event"
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	_, err := CreateLogin(s, &model.LoginDTO{Title: "Kept", Password: "dummypassword"}, schema)
+	require.Nil(t, err)
+	updated, err := CreateNote(s, &model.NoteDTO{Title: "Updated"}, schema)
+	require.Nil(t, err)
+	trashed, err := CreateServer(s, &model.ServerDTO{Title: "Trashed"}, schema)
+	require.Nil(t, err)
+	purged, err := CreateLogin(s, &model.LoginDTO{Title: "Purged"}, schema)
+	require.Nil(t, err)
+	require.Nil(t, s.Logins().Delete(purged.ID, schema))
+
+	// the first sync sends everything, decrypted
+	sync, err := Sync(s, "", schema)
+	require.Nil(t, err)
+	assert.Len(t, sync.Created, 3)
+	assert.Empty(t, sync.Updated)
+	assert.True(t, sync.Full)
+	assert.Equal(t, []model.Tombstone{{ItemType: model.LoginItem, ItemID: purged.ID, CreatedAt: sync.Deleted[0].CreatedAt}}, sync.Deleted)
+	assert.Equal(t, "dummypassword", sync.Created[0].Item.(*model.LoginDTO).Password)
+	assert.Equal(t, []uint{}, sync.Created[0].Item.(*model.LoginDTO).TagIDs)
+
+	// a token of now, regardless of the overlap
+	token := encodeSyncToken(time.Now().Add(syncOverlap))
+
+	sync, err = Sync(s, token, schema)
+	require.Nil(t, err)
+	assert.Empty(t, sync.Created)
+	assert.Empty(t, sync.Updated)
+	assert.Empty(t, sync.Deleted)
+
+	created, err := CreateEmail(s, &model.EmailDTO{Title: "Created"}, schema)
+	require.Nil(t, err)
+	_, err = UpdateNote(s, updated, &model.NoteDTO{Title: "Updated again"}, schema)
+	require.Nil(t, err)
+	require.Nil(t, s.Servers().Delete(trashed.ID, schema))
+	require.Nil(t, PurgeItem(s, model.LoginItem, purged.ID, schema))
+
+	sync, err = Sync(s, token, schema)
+	require.Nil(t, err)
+	require.Len(t, sync.Created, 1)
+	assert.Equal(t, model.EmailItem, sync.Created[0].Type)
+	assert.Equal(t, created.ID, sync.Created[0].ID)
+	require.Len(t, sync.Updated, 1)
+	assert.Equal(t, "Updated again", sync.Updated[0].Item.(*model.NoteDTO).Title)
+	deleted := []string{}
+	for _, tombstone := range sync.Deleted {
+		deleted = append(deleted, tombstone.ItemType)
+	}
+	assert.ElementsMatch(t, []string{model.ServerItem, model.LoginItem}, deleted, "trashed and purged items should be deleted")
+
+	_, err = Sync(s, "not a token", schema)
+	assert.Equal(t, ErrInvalidSyncToken, err)
+}
+
+func TestSyncTombstoneRetention(t *testing.T) {
+	viper.Set("server.tombstoneRetention", "1h")
+	defer viper.Set("server.tombstoneRetention", nil)
+
+	s := memory.New()
+	schema := "user1"
+	_, err := s.Users().Save(&model.User{Email: "patron@passwall.io", Schema: schema})
+	require.Nil(t, err)
+
+	kept, err := CreateNote(s, &model.NoteDTO{Title: "Kept"}, schema)
+	require.Nil(t, err)
+	_, err = s.Tombstones().Save(&model.Tombstone{ItemType: model.LoginItem, ItemID: 1, CreatedAt: time.Now().Add(-2 * time.Hour)}, schema)
+	require.Nil(t, err)
+
+	// tokens within the retention get the changes
+	sync, err := Sync(s, encodeSyncToken(time.Now().Add(-time.Minute)), schema)
+	require.Nil(t, err)
+	assert.False(t, sync.Full)
+
+	// older tokens could miss pruned tombstones, they get every item
+	sync, err = Sync(s, encodeSyncToken(time.Now().Add(-2*time.Hour)), schema)
+	require.Nil(t, err)
+	assert.True(t, sync.Full)
+	require.Len(t, sync.Created, 1)
+	assert.Equal(t, kept.ID, sync.Created[0].ID)
+
+	require.Nil(t, PruneTombstones(s, time.Hour))
+	tombstones, err := s.Tombstones().FindAllSince(time.Time{}, schema)
+	require.Nil(t, err)
+	assert.Empty(t, tombstones)
+}
+
+func TestSyncSQLite(t *testing.T) {
+	// SQLite compares times as text, the zone offset is part of it
+	local := time.Local
+	defer func() { time.Local = local }()
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+
+	db, err := storage.DBConn(&config.DatabaseConfiguration{Driver: "sqlite", Path: ":memory:"})
+	require.Nil(t, err)
+	defer db.Close()
+	s := storage.New(db, blob.NewMemory(), event.NewLocal())
+	MigrateSystemTables(s)
+	schema := "user1"
+	_, err = s.Users().Save(&model.User{Email: "patron@passwall.io", Schema: schema})
+	require.Nil(t, err)
+	require.Nil(t, MigrateAll(s))
+
+	_, err = CreateLogin(s, &model.LoginDTO{Title: "First"}, schema)
+	require.Nil(t, err)
+	sync, err := Sync(s, "", schema)
+	require.Nil(t, err)
+	require.Len(t, sync.Created, 1)
+
+	second, err := CreateLogin(s, &model.LoginDTO{Title: "Second"}, schema)
+	require.Nil(t, err)
+	sync, err = Sync(s, sync.Token, schema)
+	require.Nil(t, err)
+	ids := []uint{}
+	for _, item := range sync.Created {
+		ids = append(ids, item.ID)
+	}
+	assert.Contains(t, ids, second.ID, "changes after the token shouldn't be skipped")
+}
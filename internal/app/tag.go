@@ -0,0 +1,156 @@
+package app
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+var (
+	// ErrTagExists represents message for a tag name which is already used
+	ErrTagExists = errors.New("a tag with this name already exists")
+	// ErrTagMergeItself represents message for merging a tag into itself
+	ErrTagMergeItself = errors.New("a tag can't be merged into itself")
+)
+
+// FindAllTags returns every tag with its decrypted name
+func FindAllTags(s storage.Store, schema string) ([]*model.TagDTO, error) {
+	tags, err := s.Tags().All(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	tagDTOs := make([]*model.TagDTO, len(tags))
+	for i := range tags {
+		decTag, err := DecryptModel(&tags[i])
+		if err != nil {
+			return nil, err
+		}
+		tagDTOs[i] = model.ToTagDTO(decTag.(*model.Tag))
+	}
+	return tagDTOs, nil
+}
+
+// CreateTag creates a tag with an unused name
+func CreateTag(s storage.Store, dto *model.TagDTO, schema string) (*model.Tag, error) {
+	if err := checkTagName(s, dto.Name, 0, schema); err != nil {
+		return nil, err
+	}
+
+	encTag := EncryptModel(model.ToTag(dto))
+	return s.Tags().Save(encTag.(*model.Tag), schema)
+}
+
+// UpdateTag renames a tag
+func UpdateTag(s storage.Store, tag *model.Tag, dto *model.TagDTO, schema string) (*model.Tag, error) {
+	if err := checkTagName(s, dto.Name, tag.ID, schema); err != nil {
+		return nil, err
+	}
+
+	encTag := EncryptModel(model.ToTag(dto)).(*model.Tag)
+	tag.Name = encTag.Name
+	return s.Tags().Save(tag, schema)
+}
+
+// MergeTag moves the items of a tag to the target tag and deletes the tag
+func MergeTag(s storage.Store, tag *model.Tag, targetID uint, schema string) error {
+	if tag.ID == targetID {
+		return ErrTagMergeItself
+	}
+	if _, err := s.Tags().FindByID(targetID, schema); err != nil {
+		return err
+	}
+	return s.Tags().Merge(tag.ID, targetID, schema)
+}
+
+// checkTagName fails when another tag than except has the name.
+// Names are encrypted, so they are compared after decryption.
+func checkTagName(s storage.Store, name string, except uint, schema string) error {
+	tags, err := FindAllTags(s, schema)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if tag.ID != except && tag.Name == name {
+			return ErrTagExists
+		}
+	}
+	return nil
+}
+
+// LoadTags sets the TagIDs of items, which is a pointer to an item, a slice of items
+// or a slice of pointers to items
+func LoadTags(s storage.Store, itemType string, items interface{}, schema string) error {
+	value := reflect.ValueOf(items)
+	rows := []reflect.Value{}
+	if value.Kind() == reflect.Slice {
+		for i := 0; i < value.Len(); i++ {
+			row := value.Index(i)
+			// slices of any item type hold pointers to the items
+			if row.Kind() == reflect.Interface {
+				row = row.Elem().Elem()
+			}
+			rows = append(rows, row)
+		}
+	} else {
+		rows = append(rows, value.Elem())
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = uint(row.FieldByName("ID").Uint())
+	}
+
+	itemTags, err := s.Tags().FindItemTags(itemType, ids, schema)
+	if err != nil {
+		return err
+	}
+	tagIDs := map[uint][]uint{}
+	for _, itemTag := range itemTags {
+		tagIDs[itemTag.ItemID] = append(tagIDs[itemTag.ItemID], itemTag.TagID)
+	}
+
+	for i, row := range rows {
+		itemTagIDs := tagIDs[ids[i]]
+		if itemTagIDs == nil {
+			itemTagIDs = []uint{}
+		}
+		row.FieldByName("TagIDs").Set(reflect.ValueOf(itemTagIDs))
+	}
+	return nil
+}
+
+// tagItem replaces the tags of an item unless tagIDs is nil
+// and returns the ids of the tags the item has
+func tagItem(s storage.Store, itemType string, id uint, tagIDs []uint, schema string) ([]uint, error) {
+	if tagIDs != nil {
+		unique := []uint{}
+		seen := map[uint]bool{}
+		for _, tagID := range tagIDs {
+			if seen[tagID] {
+				continue
+			}
+			if _, err := s.Tags().FindByID(tagID, schema); err != nil {
+				return nil, err
+			}
+			seen[tagID] = true
+			unique = append(unique, tagID)
+		}
+
+		if err := s.Tags().SetItemTags(itemType, id, unique, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	itemTags, err := s.Tags().FindItemTags(itemType, []uint{id}, schema)
+	if err != nil {
+		return nil, err
+	}
+	ids := []uint{}
+	for _, itemTag := range itemTags {
+		ids = append(ids, itemTag.TagID)
+	}
+	return ids, nil
+}
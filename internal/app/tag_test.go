@@ -0,0 +1,59 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	prod, err := CreateTag(s, &model.TagDTO{Name: "prod"}, schema)
+	require.Nil(t, err)
+	assert.NotEqual(t, "prod", prod.Name, "tag names should be stored encrypted")
+	_, err = CreateTag(s, &model.TagDTO{Name: "prod"}, schema)
+	assert.Equal(t, ErrTagExists, err)
+
+	live, err := CreateTag(s, &model.TagDTO{Name: "live"}, schema)
+	require.Nil(t, err)
+	_, err = UpdateTag(s, live, &model.TagDTO{Name: "prod"}, schema)
+	assert.Equal(t, ErrTagExists, err)
+	live, err = UpdateTag(s, live, &model.TagDTO{Name: "customer-x"}, schema)
+	require.Nil(t, err)
+
+	tags, err := FindAllTags(s, schema)
+	require.Nil(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, "customer-x", tags[1].Name)
+
+	login, err := CreateLogin(s, &model.LoginDTO{Title: "PassWall", TagIDs: []uint{prod.ID, live.ID, prod.ID}}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []uint{prod.ID, live.ID}, login.TagIDs)
+
+	_, err = CreateLogin(s, &model.LoginDTO{Title: "Missing tag", TagIDs: []uint{99}}, schema)
+	assert.NotNil(t, err)
+
+	// Nil tag ids keep the tags of the item
+	login, err = UpdateLogin(s, login, &model.LoginDTO{Title: "Renamed"}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []uint{prod.ID, live.ID}, login.TagIDs)
+
+	assert.Equal(t, ErrTagMergeItself, MergeTag(s, live, live.ID, schema))
+	require.Nil(t, MergeTag(s, live, prod.ID, schema))
+
+	logins, err := s.Logins().All(schema)
+	require.Nil(t, err)
+	require.Nil(t, LoadTags(s, model.LoginItem, logins, schema))
+	assert.Equal(t, []uint{prod.ID}, logins[0].TagIDs)
+
+	require.Nil(t, s.Logins().Delete(login.ID, schema))
+	require.Nil(t, PurgeItem(s, model.LoginItem, login.ID, schema))
+	itemTags, err := s.Tags().FindItemTags(model.LoginItem, []uint{login.ID}, schema)
+	require.Nil(t, err)
+	assert.Empty(t, itemTags, "purged items should lose their tags")
+}
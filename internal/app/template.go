@@ -0,0 +1,100 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+)
+
+var (
+	// ErrInvalidTemplate is returned when a template's type or fields are not valid
+	ErrInvalidTemplate = errors.New("invalid template")
+	// ErrTemplateExists represents message for a template type which is already taken
+	ErrTemplateExists = errors.New("a template with this type already exists")
+	// ErrTemplateInUse represents message for deleting a template which still has items
+	ErrTemplateInUse = errors.New("the template has items, they should be purged first")
+)
+
+// templateType matches the types of templates, they are used in urls
+var templateType = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CreateTemplate creates a new template and saves it to the store
+func CreateTemplate(s storage.Store, dto *model.TemplateDTO, schema string) (*model.Template, error) {
+	if !templateType.MatchString(dto.Type) {
+		return nil, fmt.Errorf("%w: type %q should be lowercase letters, digits and dashes", ErrInvalidTemplate, dto.Type)
+	}
+	if err := validateTemplateFields(dto.Fields); err != nil {
+		return nil, err
+	}
+
+	_, err := s.Templates().FindByType(dto.Type, schema)
+	if err == nil {
+		return nil, ErrTemplateExists
+	}
+	if !gorm.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+
+	return s.Templates().Save(model.ToTemplate(dto), schema)
+}
+
+// UpdateTemplate renames a template or changes its fields. The type can't be
+// changed, the items of the template are found by it. Items are encrypted
+// according to the new fields the next time they are saved.
+func UpdateTemplate(s storage.Store, template *model.Template, dto *model.TemplateDTO, schema string) (*model.Template, error) {
+	if err := validateTemplateFields(dto.Fields); err != nil {
+		return nil, err
+	}
+
+	template.Name = dto.Name
+	template.Fields = dto.Fields
+	return s.Templates().Save(template, schema)
+}
+
+// DeleteTemplate deletes a template which has no items, trashed ones included
+func DeleteTemplate(s storage.Store, template *model.Template, schema string) error {
+	count, err := s.CustomItems().Count(map[string]string{"type": template.Type}, schema)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.CustomItems().FindAllDeleted(schema)
+	if err != nil {
+		return err
+	}
+	for _, item := range deleted {
+		if item.Type == template.Type {
+			count++
+		}
+	}
+	if count > 0 {
+		return ErrTemplateInUse
+	}
+
+	return s.Templates().Delete(template.ID, schema)
+}
+
+// validateTemplateFields checks that the fields have unique names and known types
+func validateTemplateFields(fields []model.TemplateField) error {
+	names := map[string]bool{}
+	for _, field := range fields {
+		name := strings.TrimSpace(field.Name)
+		if name == "" {
+			return fmt.Errorf("%w: fields should have a name", ErrInvalidTemplate)
+		}
+		if names[name] {
+			return fmt.Errorf("%w: field %q is defined twice", ErrInvalidTemplate, name)
+		}
+		names[name] = true
+
+		if !isCustomFieldType(field.Type) {
+			return fmt.Errorf("%w: field %q has unknown type %q, it should be one of %s",
+				ErrInvalidTemplate, name, field.Type, strings.Join(model.CustomFieldTypes, ", "))
+		}
+	}
+	return nil
+}
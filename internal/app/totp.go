@@ -0,0 +1,150 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/passwall/passwall-server/model"
+)
+
+var (
+	// ErrInvalidTOTP is returned when the TOTP of a login is neither an otpauth URI nor a base32 secret
+	ErrInvalidTOTP = errors.New("invalid totp")
+	// ErrNoTOTP is returned when a code is asked for a login without a TOTP
+	ErrNoTOTP = errors.New("the login has no totp")
+)
+
+// Defaults of the otpauth URIs, base32 secrets always use them
+const (
+	totpDigits = 6
+	totpPeriod = 30
+)
+
+var totpAlgorithms = map[string]func() hash.Hash{
+	"SHA1":   sha1.New,
+	"SHA256": sha256.New,
+	"SHA512": sha512.New,
+}
+
+// totp is a parsed TOTP secret with its parameters
+type totp struct {
+	secret    []byte
+	algorithm func() hash.Hash
+	digits    int
+	period    int
+}
+
+// parseTOTP parses an otpauth://totp URI or a base32 secret
+func parseTOTP(value string) (*totp, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(strings.ToLower(value), "otpauth:") {
+		secret, err := decodeTOTPSecret(value)
+		if err != nil {
+			return nil, err
+		}
+		return &totp{secret: secret, algorithm: sha1.New, digits: totpDigits, period: totpPeriod}, nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTOTP, err)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return nil, fmt.Errorf("%w: only otpauth://totp URIs are supported", ErrInvalidTOTP)
+	}
+
+	query := u.Query()
+	secret, err := decodeTOTPSecret(query.Get("secret"))
+	if err != nil {
+		return nil, err
+	}
+	t := &totp{secret: secret, algorithm: sha1.New, digits: totpDigits, period: totpPeriod}
+
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		if t.algorithm = totpAlgorithms[strings.ToUpper(algorithm)]; t.algorithm == nil {
+			return nil, fmt.Errorf("%w: algorithm %q should be SHA1, SHA256 or SHA512", ErrInvalidTOTP, algorithm)
+		}
+	}
+	if digits := query.Get("digits"); digits != "" {
+		if t.digits, err = strconv.Atoi(digits); err != nil || (t.digits != 6 && t.digits != 8) {
+			return nil, fmt.Errorf("%w: digits %q should be 6 or 8", ErrInvalidTOTP, digits)
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		if t.period, err = strconv.Atoi(period); err != nil || t.period <= 0 {
+			return nil, fmt.Errorf("%w: period %q should be a positive number of seconds", ErrInvalidTOTP, period)
+		}
+	}
+	return t, nil
+}
+
+// decodeTOTPSecret decodes a base32 secret, case, spaces and padding are ignored
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.Join(strings.Fields(secret), ""))
+	secret = strings.TrimRight(secret, "=")
+	if secret == "" {
+		return nil, fmt.Errorf("%w: the secret is empty", ErrInvalidTOTP)
+	}
+
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: the secret should be base32", ErrInvalidTOTP)
+	}
+	return decoded, nil
+}
+
+// code returns the code of the period t is in (RFC 6238)
+func (t *totp) code(at time.Time) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(at.Unix()/int64(t.period)))
+
+	mac := hmac.New(t.algorithm, t.secret)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < t.digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", t.digits, value%modulo)
+}
+
+// validateTOTP checks that the TOTP of a login can generate codes, logins may have none
+func validateTOTP(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	_, err := parseTOTP(value)
+	return err
+}
+
+// TOTPCode returns the code of a decrypted TOTP at the time with the seconds it is valid for
+func TOTPCode(value string, at time.Time) (*model.TOTPCode, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, ErrNoTOTP
+	}
+
+	t, err := parseTOTP(value)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TOTPCode{
+		Code:      t.code(at),
+		Period:    t.period,
+		Remaining: t.period - int(at.Unix()%int64(t.period)),
+	}, nil
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"encoding/base32"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The test vectors of RFC 6238
+func TestTOTPCode(t *testing.T) {
+	uri := func(secret, algorithm string) string {
+		encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(secret))
+		return "otpauth://totp/Passwall:test?secret=" + encoded + "&algorithm=" + algorithm + "&digits=8"
+	}
+	sha1URI := uri("12345678901234567890", "SHA1")
+	sha256URI := uri("12345678901234567890123456789012", "SHA256")
+	sha512URI := uri("1234567890123456789012345678901234567890123456789012345678901234", "sha512")
+
+	tests := []struct {
+		totp string
+		unix int64
+		code string
+	}{
+		{sha1URI, 59, "94287082"},
+		{sha256URI, 59, "46119246"},
+		{sha512URI, 59, "90693936"},
+		{sha1URI, 1111111109, "07081804"},
+		{sha256URI, 1111111109, "68084774"},
+		{sha512URI, 1111111109, "25091201"},
+		{sha1URI, 20000000000, "65353130"},
+		{sha256URI, 20000000000, "77737706"},
+		{sha512URI, 20000000000, "47863826"},
+	}
+	for _, tt := range tests {
+		code, err := TOTPCode(tt.totp, time.Unix(tt.unix, 0))
+		require.Nil(t, err)
+		assert.Equal(t, tt.code, code.Code, "%s at %d", tt.totp, tt.unix)
+		assert.Equal(t, 30, code.Period)
+	}
+
+	// Base32 secrets use SHA1, 6 digits and 30 seconds
+	code, err := TOTPCode("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", time.Unix(59, 0))
+	require.Nil(t, err)
+	assert.Equal(t, &model.TOTPCode{Code: "287082", Period: 30, Remaining: 1}, code)
+
+	code, err = TOTPCode("otpauth://totp/test?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&period=60", time.Unix(59, 0))
+	require.Nil(t, err)
+	assert.Equal(t, 60, code.Period)
+	assert.Equal(t, 1, code.Remaining)
+
+	_, err = TOTPCode("", time.Now())
+	assert.Equal(t, ErrNoTOTP, err)
+}
+
+func TestTOTPValidation(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+
+	for _, totp := range []string{
+		"not base32!",
+		"otpauth://hotp/test?secret=JBSWY3DPEHPK3PXP&counter=1",
+		"otpauth://totp/test",
+		"otpauth://totp/test?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+		"otpauth://totp/test?secret=JBSWY3DPEHPK3PXP&digits=7",
+		"otpauth://totp/test?secret=JBSWY3DPEHPK3PXP&period=0",
+	} {
+		_, err := CreateLogin(s, &model.LoginDTO{Title: "invalid", TOTP: totp}, schema)
+		assert.True(t, errors.Is(err, ErrInvalidTOTP), "%q should be invalid", totp)
+	}
+
+	login, err := CreateLogin(s, &model.LoginDTO{Title: "Service account", TOTP: "JBSWY3DPEHPK3PXP"}, schema)
+	require.Nil(t, err)
+	assert.NotEqual(t, "JBSWY3DPEHPK3PXP", login.TOTP, "the secret should be encrypted")
+
+	_, err = UpdateLogin(s, login, &model.LoginDTO{Title: "Service account", TOTP: "otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&digits=9"}, schema)
+	assert.True(t, errors.Is(err, ErrInvalidTOTP))
+	_, err = UpdateLogin(s, login, &model.LoginDTO{Title: "Service account"}, schema)
+	assert.Nil(t, err, "logins may have no totp")
+}
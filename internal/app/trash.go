@@ -0,0 +1,338 @@
+package app
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/model"
+	"github.com/spf13/viper"
+)
+
+// ErrUnknownItemType represents message for an unsupported item type
+var ErrUnknownItemType = errors.New("unknown item type")
+
+// Trash lists the soft deleted items of every type, recently deleted first
+func Trash(s storage.Store, schema string) ([]model.TrashItem, error) {
+	items := []model.TrashItem{}
+	add := func(itemType string, id uint, title string, deletedAt *time.Time) {
+		items = append(items, model.TrashItem{ID: id, Type: itemType, Title: title, DeletedAt: *deletedAt})
+	}
+
+	logins, err := s.Logins().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range logins {
+		add(model.LoginItem, item.ID, item.Title, item.DeletedAt)
+	}
+
+	cards, err := s.CreditCards().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range cards {
+		add(model.CreditCardItem, item.ID, item.CardName, item.DeletedAt)
+	}
+
+	accounts, err := s.BankAccounts().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range accounts {
+		add(model.BankAccountItem, item.ID, item.BankName, item.DeletedAt)
+	}
+
+	notes, err := s.Notes().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range notes {
+		add(model.NoteItem, item.ID, item.Title, item.DeletedAt)
+	}
+
+	emails, err := s.Emails().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range emails {
+		add(model.EmailItem, item.ID, item.Title, item.DeletedAt)
+	}
+
+	servers, err := s.Servers().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range servers {
+		add(model.ServerItem, item.ID, item.Title, item.DeletedAt)
+	}
+
+	identities, err := s.Identities().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range identities {
+		add(model.IdentityItem, item.ID, item.Title, item.DeletedAt)
+	}
+
+	licenseKeys, err := s.LicenseKeys().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range licenseKeys {
+		add(model.LicenseKeyItem, item.ID, item.Product, item.DeletedAt)
+	}
+
+	customItems, err := s.CustomItems().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range customItems {
+		add(model.CustomItemItem, item.ID, item.Title, item.DeletedAt)
+	}
+
+	sshKeys, err := s.SSHKeys().FindAllDeleted(schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range sshKeys {
+		add(model.SSHKeyItem, item.ID, item.Title, item.DeletedAt)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// DeleteItem moves an item to the trash
+func DeleteItem(s storage.Store, itemType string, id uint, schema string) error {
+	if err := deleteItem(s, itemType, id, schema); err != nil {
+		return err
+	}
+	publish(s, model.ItemDeletedEvent, itemType, id, schema)
+	return nil
+}
+
+func deleteItem(s storage.Store, itemType string, id uint, schema string) error {
+	switch itemType {
+	case model.LoginItem:
+		return s.Logins().Delete(id, schema)
+	case model.CreditCardItem:
+		return s.CreditCards().Delete(id, schema)
+	case model.BankAccountItem:
+		return s.BankAccounts().Delete(id, schema)
+	case model.NoteItem:
+		return s.Notes().Delete(id, schema)
+	case model.EmailItem:
+		return s.Emails().Delete(id, schema)
+	case model.ServerItem:
+		return s.Servers().Delete(id, schema)
+	case model.IdentityItem:
+		return s.Identities().Delete(id, schema)
+	case model.LicenseKeyItem:
+		return s.LicenseKeys().Delete(id, schema)
+	case model.CustomItemItem:
+		return s.CustomItems().Delete(id, schema)
+	case model.SSHKeyItem:
+		return s.SSHKeys().Delete(id, schema)
+	}
+	return ErrUnknownItemType
+}
+
+// RestoreItem brings back a soft deleted item
+func RestoreItem(s storage.Store, itemType string, id uint, schema string) error {
+	if err := restoreItem(s, itemType, id, schema); err != nil {
+		return err
+	}
+	publish(s, model.ItemChangedEvent, itemType, id, schema)
+	return nil
+}
+
+func restoreItem(s storage.Store, itemType string, id uint, schema string) error {
+	switch itemType {
+	case model.LoginItem:
+		return s.Logins().Restore(id, schema)
+	case model.CreditCardItem:
+		return s.CreditCards().Restore(id, schema)
+	case model.BankAccountItem:
+		return s.BankAccounts().Restore(id, schema)
+	case model.NoteItem:
+		return s.Notes().Restore(id, schema)
+	case model.EmailItem:
+		return s.Emails().Restore(id, schema)
+	case model.ServerItem:
+		return s.Servers().Restore(id, schema)
+	case model.IdentityItem:
+		return s.Identities().Restore(id, schema)
+	case model.LicenseKeyItem:
+		return s.LicenseKeys().Restore(id, schema)
+	case model.CustomItemItem:
+		return s.CustomItems().Restore(id, schema)
+	case model.SSHKeyItem:
+		return s.SSHKeys().Restore(id, schema)
+	}
+	return ErrUnknownItemType
+}
+
+// PurgeItem permanently removes a soft deleted item with its revisions, tag links
+// and attachments, leaving a tombstone for the clients to sync. The rows are
+// removed in a transaction, the attachment contents once it is committed.
+func PurgeItem(s storage.Store, itemType string, id uint, schema string) error {
+	var blobKeys []string
+	err := s.Transaction(func(tx storage.Store) error {
+		if err := purgeItem(tx, itemType, id, schema); err != nil {
+			return err
+		}
+		var err error
+		blobKeys, err = purgeItemRows(tx, itemType, []uint{id}, schema)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	deleteBlobs(s, blobKeys)
+	return nil
+}
+
+func purgeItem(s storage.Store, itemType string, id uint, schema string) error {
+	switch itemType {
+	case model.LoginItem:
+		return s.Logins().Purge(id, schema)
+	case model.CreditCardItem:
+		return s.CreditCards().Purge(id, schema)
+	case model.BankAccountItem:
+		return s.BankAccounts().Purge(id, schema)
+	case model.NoteItem:
+		return s.Notes().Purge(id, schema)
+	case model.EmailItem:
+		return s.Emails().Purge(id, schema)
+	case model.ServerItem:
+		return s.Servers().Purge(id, schema)
+	case model.IdentityItem:
+		return s.Identities().Purge(id, schema)
+	case model.LicenseKeyItem:
+		return s.LicenseKeys().Purge(id, schema)
+	case model.CustomItemItem:
+		return s.CustomItems().Purge(id, schema)
+	case model.SSHKeyItem:
+		return s.SSHKeys().Purge(id, schema)
+	}
+	return ErrUnknownItemType
+}
+
+// PurgeDeletedBefore permanently removes the items of every type deleted before t
+// with their revisions, tag links and attachments, leaving tombstones for the
+// clients to sync. The rows are removed in a transaction, the attachment
+// contents once it is committed.
+func PurgeDeletedBefore(s storage.Store, t time.Time, schema string) error {
+	var blobKeys []string
+	err := s.Transaction(func(tx storage.Store) error {
+		trash, err := Trash(tx, schema)
+		if err != nil {
+			return err
+		}
+		purged := map[string][]uint{}
+		for _, item := range trash {
+			if item.DeletedAt.Before(t) {
+				purged[item.Type] = append(purged[item.Type], item.ID)
+			}
+		}
+
+		purges := []func(time.Time, string) error{
+			tx.Logins().PurgeDeletedBefore,
+			tx.CreditCards().PurgeDeletedBefore,
+			tx.BankAccounts().PurgeDeletedBefore,
+			tx.Notes().PurgeDeletedBefore,
+			tx.Emails().PurgeDeletedBefore,
+			tx.Servers().PurgeDeletedBefore,
+			tx.Identities().PurgeDeletedBefore,
+			tx.LicenseKeys().PurgeDeletedBefore,
+			tx.CustomItems().PurgeDeletedBefore,
+			tx.SSHKeys().PurgeDeletedBefore,
+		}
+		for _, purge := range purges {
+			if err := purge(t, schema); err != nil {
+				return err
+			}
+		}
+
+		for itemType, ids := range purged {
+			keys, err := purgeItemRows(tx, itemType, ids, schema)
+			if err != nil {
+				return err
+			}
+			blobKeys = append(blobKeys, keys...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	deleteBlobs(s, blobKeys)
+	return nil
+}
+
+// purgeItemRows records the tombstones of purged items and removes their
+// revisions, tag links and attachment rows. It returns the keys of the
+// attachment contents, which are deleted after the transaction.
+func purgeItemRows(s storage.Store, itemType string, ids []uint, schema string) ([]string, error) {
+	if err := recordTombstones(s, itemType, ids, schema); err != nil {
+		return nil, err
+	}
+	if err := s.Revisions().DeleteAll(itemType, ids, schema); err != nil {
+		return nil, err
+	}
+	if err := s.Tags().DeleteItemTags(itemType, ids, schema); err != nil {
+		return nil, err
+	}
+	return deleteAttachments(s, itemType, ids, schema)
+}
+
+// EmptyTrash permanently removes every soft deleted item
+func EmptyTrash(s storage.Store, schema string) error {
+	return PurgeDeletedBefore(s, time.Now(), schema)
+}
+
+// PurgeExpiredTrash permanently removes the items which stayed
+// in the trash of any user longer than the retention period
+func PurgeExpiredTrash(s storage.Store, retention time.Duration) error {
+	users, err := s.Users().All()
+	if err != nil {
+		return err
+	}
+
+	before := time.Now().Add(-retention)
+	for _, user := range users {
+		if user.Schema == "" {
+			continue
+		}
+		if err := PurgeDeletedBefore(s, before, user.Schema); err != nil {
+			log.Printf("%s: could not purge trash: %v", user.Schema, err)
+		}
+	}
+	return nil
+}
+
+// StartTrashPurge purges the expired trash items periodically.
+// A retention of 0 keeps the items until they are purged by the user.
+func StartTrashPurge(s storage.Store) {
+	retention := viper.GetString("server.trashRetention")
+	if retention == "" || retention == "0" {
+		return
+	}
+	period := resolveTokenExpireDuration(retention)
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if err := PurgeExpiredTrash(s, period); err != nil {
+				log.Printf("could not purge trash: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
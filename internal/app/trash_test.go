@@ -0,0 +1,53 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash(t *testing.T) {
+	s := memory.New()
+	schema := "user1"
+	_, err := s.Users().Save(&model.User{Email: "patron@passwall.io", Schema: schema})
+	require.Nil(t, err)
+
+	login, _ := s.Logins().Save(&model.Login{Title: "login"}, schema)
+	note, _ := s.Notes().Save(&model.Note{Title: "note"}, schema)
+	card, _ := s.CreditCards().Save(&model.CreditCard{CardName: "card"}, schema)
+
+	require.Nil(t, s.Logins().Delete(login.ID, schema))
+	time.Sleep(10 * time.Millisecond)
+	require.Nil(t, s.Notes().Delete(note.ID, schema))
+
+	items, err := Trash(s, schema)
+	require.Nil(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, model.NoteItem, items[0].Type, "recently deleted items should come first")
+	assert.Equal(t, "login", items[1].Title)
+
+	assert.Equal(t, ErrUnknownItemType, RestoreItem(s, "car", note.ID, schema))
+	require.Nil(t, RestoreItem(s, model.NoteItem, note.ID, schema))
+	require.Nil(t, PurgeItem(s, model.LoginItem, login.ID, schema))
+	assert.NotNil(t, PurgeItem(s, model.CreditCardItem, card.ID, schema), "only deleted items can be purged")
+
+	items, err = Trash(s, schema)
+	require.Nil(t, err)
+	assert.Empty(t, items)
+
+	// Retention purges only the items deleted before it
+	require.Nil(t, s.CreditCards().Delete(card.ID, schema))
+	require.Nil(t, PurgeExpiredTrash(s, time.Hour))
+	items, err = Trash(s, schema)
+	require.Nil(t, err)
+	assert.Len(t, items, 1)
+
+	require.Nil(t, PurgeExpiredTrash(s, 0))
+	items, err = Trash(s, schema)
+	require.Nil(t, err)
+	assert.Empty(t, items)
+}
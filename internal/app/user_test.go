@@ -7,6 +7,8 @@ import (
 
 	"github.com/passwall/passwall-server/internal/config"
 	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/blob"
+	"github.com/passwall/passwall-server/internal/storage/event"
 	"github.com/passwall/passwall-server/model"
 	uuid "github.com/satori/go.uuid"
 )
@@ -199,6 +201,6 @@ func initDB() (*storage.Database, error) {
 		return nil, err
 	}
 
-	db := storage.New(mockDB)
+	db := storage.New(mockDB, blob.NewMemory(), event.NewLocal())
 	return db, nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"os"
 	"path/filepath"
 
@@ -22,29 +23,38 @@ var (
 
 // Configuration ...
 type Configuration struct {
-	Server   ServerConfiguration
-	Database DatabaseConfiguration
-	Email    EmailConfiguration
-	Backup   BackupConfiguration
+	Server     ServerConfiguration
+	Database   DatabaseConfiguration
+	Email      EmailConfiguration
+	Backup     BackupConfiguration
+	Attachment AttachmentConfiguration
 }
 
 // ServerConfiguration is the required parameters to set up a server
 type ServerConfiguration struct {
-	Env                        string `default:"dev"` // dev, prod
-	Port                       string `default:"3625"`
-	Domain                     string `default:"https://vault.passwall.io"`
-	Dir                        string `default:"/app/config"`
-	Passphrase                 string `default:"passphrase-for-encrypting-passwords-do-not-forget"`
-	Secret                     string `default:"secret-key-for-JWT-TOKEN"`
-	Timeout                    int    `default:"24"`
-	GeneratedPasswordLength    int    `default:"16"`
-	AccessTokenExpireDuration  string `default:"30m"`
-	RefreshTokenExpireDuration string `default:"15d"`
-	APIKey                     string `default:"my-secret-api-key"`
+	Env                        string   `default:"dev"` // dev, prod
+	Port                       string   `default:"3625"`
+	Domain                     string   `default:"https://vault.passwall.io"`
+	Dir                        string   `default:"/app/config"`
+	Passphrase                 string   `default:"passphrase-for-encrypting-passwords-do-not-forget"`
+	PassphraseID               string   // written into the encrypted values, changed with the passphrase, without "$"
+	LegacyPassphrases          []string // decrypt only, kept while the passphrase is rotated
+	SearchKey                  string   // key of the search indexes, it isn't rotated with the passphrase
+	Secret                     string   `default:"secret-key-for-JWT-TOKEN"`
+	Timeout                    int      `default:"24"`
+	GeneratedPasswordLength    int      `default:"16"`
+	AccessTokenExpireDuration  string   `default:"30m"`
+	RefreshTokenExpireDuration string   `default:"15d"`
+	APIKey                     string   `default:"my-secret-api-key"`
+	TrashRetention             string   `default:"30d"` // 0 keeps deleted items until purged
+	TombstoneRetention         string   `default:"90d"` // older sync tokens get a full resync, 0 keeps tombstones
+	RevisionLimit              int      `default:"20"`  // revisions kept per item, 0 keeps all
 }
 
 // DatabaseConfiguration is the required parameters to set up a DB instance
 type DatabaseConfiguration struct {
+	Driver   string `default:"postgres"` // postgres, sqlite
+	Path     string `default:"./store/passwall.db"`
 	Name     string `default:"passwall"`
 	Username string `default:"user"`
 	Password string `default:"password"`
@@ -70,6 +80,13 @@ type BackupConfiguration struct {
 	Period   string `default:"24h"`
 }
 
+// AttachmentConfiguration is the required parameters to store attachments
+type AttachmentConfiguration struct {
+	Folder  string `default:"./store/attachments/"`
+	MaxSize int    `default:"10"`  // MB per file
+	Quota   int    `default:"100"` // MB per user, 0 is unlimited
+}
+
 // SetupConfigDefaults ...
 func SetupConfigDefaults() (*Configuration, error) {
 
@@ -108,6 +125,15 @@ func readConfiguration() error {
 		} else {
 			return err
 		}
+		// new configurations key the search indexes with the passphrase they
+		// start with, the search key then stays when the passphrase is rotated
+		if viper.GetString("server.searchKey") == "" {
+			viper.Set("server.searchKey", viper.GetString("server.passphrase"))
+		}
+		// the id of the passphrase is random, it tells nothing about it
+		if viper.GetString("server.passphraseID") == "" {
+			viper.Set("server.passphraseID", generateKeyID())
+		}
 		// let's write defaults
 		if err := viper.WriteConfig(); err != nil {
 			return err
@@ -128,6 +154,9 @@ func bindEnvs() {
 	viper.BindEnv("server.port", "PORT")
 	viper.BindEnv("server.domain", "DOMAIN")
 	viper.BindEnv("server.passphrase", "PW_SERVER_PASSPHRASE")
+	viper.BindEnv("server.passphraseID", "PW_SERVER_PASSPHRASE_ID")
+	viper.BindEnv("server.legacyPassphrases", "PW_SERVER_LEGACY_PASSPHRASES")
+	viper.BindEnv("server.searchKey", "PW_SERVER_SEARCH_KEY")
 	viper.BindEnv("server.secret", "PW_SERVER_SECRET")
 	viper.BindEnv("server.timeout", "PW_SERVER_TIMEOUT")
 
@@ -135,9 +164,14 @@ func bindEnvs() {
 	viper.BindEnv("server.accessTokenExpireDuration", "PW_SERVER_ACCESS_TOKEN_EXPIRE_DURATION")
 	viper.BindEnv("server.refreshTokenExpireDuration", "PW_SERVER_REFRESH_TOKEN_EXPIRE_DURATION")
 
+	viper.BindEnv("server.trashRetention", "PW_SERVER_TRASH_RETENTION")
+	viper.BindEnv("server.tombstoneRetention", "PW_SERVER_TOMBSTONE_RETENTION")
+	viper.BindEnv("server.revisionLimit", "PW_SERVER_REVISION_LIMIT")
 	viper.BindEnv("server.apiKey", "PW_SERVER_API_KEY")
 	viper.BindEnv("server.recaptcha", "PW_SERVER_RECAPTCHA")
 
+	viper.BindEnv("database.driver", "PW_DB_DRIVER")
+	viper.BindEnv("database.path", "PW_DB_PATH")
 	viper.BindEnv("database.name", "PW_DB_NAME")
 	viper.BindEnv("database.username", "PW_DB_USERNAME")
 	viper.BindEnv("database.password", "PW_DB_PASSWORD")
@@ -156,6 +190,10 @@ func bindEnvs() {
 	viper.BindEnv("backup.folder", "PW_BACKUP_FOLDER")
 	viper.BindEnv("backup.rotation", "PW_BACKUP_ROTATION")
 	viper.BindEnv("backup.period", "PW_BACKUP_PERIOD")
+
+	viper.BindEnv("attachment.folder", "PW_ATTACHMENT_FOLDER")
+	viper.BindEnv("attachment.maxSize", "PW_ATTACHMENT_MAX_SIZE")
+	viper.BindEnv("attachment.quota", "PW_ATTACHMENT_QUOTA")
 }
 
 func setDefaults() {
@@ -165,15 +203,21 @@ func setDefaults() {
 	viper.SetDefault("server.port", "3625")
 	viper.SetDefault("server.domain", "https://vault.passwall.io")
 	viper.SetDefault("server.passphrase", generateKey())
+	viper.SetDefault("server.legacyPassphrases", []string{})
 	viper.SetDefault("server.secret", generateKey())
 	viper.SetDefault("server.timeout", 24)
 	viper.SetDefault("server.generatedPasswordLength", 16)
 	viper.SetDefault("server.accessTokenExpireDuration", "30m")
 	viper.SetDefault("server.refreshTokenExpireDuration", "15d")
+	viper.SetDefault("server.trashRetention", "30d")
+	viper.SetDefault("server.tombstoneRetention", "90d")
+	viper.SetDefault("server.revisionLimit", 20)
 	viper.SetDefault("server.apiKey", generateKey())
 	viper.SetDefault("server.recaptcha", "GoogleRecaptchaSecret")
 
 	// Database defaults
+	viper.SetDefault("database.driver", "postgres")
+	viper.SetDefault("database.path", filepath.Join(storeDirectory, "passwall.db"))
 	viper.SetDefault("database.name", "passwall")
 	viper.SetDefault("database.username", "postgres")
 	viper.SetDefault("database.password", "password")
@@ -194,6 +238,11 @@ func setDefaults() {
 	viper.SetDefault("backup.folder", storeDirectory)
 	viper.SetDefault("backup.rotation", 7)
 	viper.SetDefault("backup.period", "24h")
+
+	// Attachment defaults
+	viper.SetDefault("attachment.folder", filepath.Join(storeDirectory, "attachments"))
+	viper.SetDefault("attachment.maxSize", 10)
+	viper.SetDefault("attachment.quota", 100)
 }
 
 func generateKey() string {
@@ -205,3 +254,11 @@ func generateKey() string {
 	keyEnc := base64.StdEncoding.EncodeToString(key)
 	return keyEnc
 }
+
+func generateKeyID() string {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "1"
+	}
+	return hex.EncodeToString(id)
+}
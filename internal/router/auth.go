@@ -36,52 +36,95 @@ func Auth(s storage.Store) negroni.HandlerFunc {
 		}
 
 		claims, _ := token.Claims.(jwt.MapClaims)
-		uuid, _ := claims["uuid"].(string)
 
-		// Check token from tokens db table
-		tokenRow, tokenExist := s.Tokens().Any(uuid)
-
-		// Get User UUID from claims
-		ctxUserUUID, ok := claims["user_uuid"].(string)
-		if !ok {
+		// Event tokens only open event streams
+		if _, ok := claims["purpose"]; ok {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		// Get user details from db by User UUID
-		user, err := s.Users().FindByUUID(ctxUserUUID)
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
+		authorize(s, w, r, next, claims)
+	})
+}
+
+// EventAuth verifies the authentication of the event streams. Browsers
+// can't send headers with EventSource, so a short-lived event token is
+// accepted in the token query parameter too.
+func EventAuth(s storage.Store) negroni.HandlerFunc {
+	auth := Auth(s)
+
+	return negroni.HandlerFunc(func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		tokenstr := r.URL.Query().Get("token")
+		if tokenstr == "" {
+			auth(w, r, next)
 			return
 		}
 
-		// Token invalidation for old token usage
-		if !tokenExist {
-			s.Tokens().Delete(int(user.ID))
+		// An expired event token leaves the session of its access token alone
+		token, err := app.TokenValid(tokenstr)
+		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		// Admin or Member
-		ctxAuthorized, ok := claims["authorized"].(bool)
-		if !ok {
+		claims, _ := token.Claims.(jwt.MapClaims)
+		if purpose, _ := claims["purpose"].(string); purpose != app.EventTokenPurpose {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		ctxSchema := user.Schema
-		ctxTransmissionKey := tokenRow.TransmissionKey
-
-		ctx := r.Context()
-		ctxWithUUID := context.WithValue(ctx, "uuid", ctxUserUUID)
-		ctxWithAuthorized := context.WithValue(ctxWithUUID, "authorized", ctxAuthorized)
-		ctxWithSchema := context.WithValue(ctxWithAuthorized, "schema", ctxSchema)
-		ctxWithTransmissionKey := context.WithValue(ctxWithSchema, "transmissionKey", ctxTransmissionKey)
-
-		// These context variables can be accesable with
-		// ctxAuthorized := r.Context().Value("authorized").(bool)
-		// ctxID := r.Context().Value("id").(float64)
-
-		next(w, r.WithContext(ctxWithTransmissionKey))
+		authorize(s, w, r, next, claims)
 	})
 }
+
+// authorize puts the user and the session of the token claims in the context
+func authorize(s storage.Store, w http.ResponseWriter, r *http.Request, next http.HandlerFunc, claims jwt.MapClaims) {
+	uuid, _ := claims["uuid"].(string)
+
+	// Check token from tokens db table
+	tokenRow, tokenExist := s.Tokens().Any(uuid)
+
+	// Get User UUID from claims
+	ctxUserUUID, ok := claims["user_uuid"].(string)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// Get user details from db by User UUID
+	user, err := s.Users().FindByUUID(ctxUserUUID)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// Token invalidation for old token usage
+	if !tokenExist {
+		s.Tokens().Delete(int(user.ID))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// Admin or Member
+	ctxAuthorized, ok := claims["authorized"].(bool)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	ctxSchema := user.Schema
+	ctxTransmissionKey := tokenRow.TransmissionKey
+
+	ctx := r.Context()
+	ctxWithUUID := context.WithValue(ctx, "uuid", ctxUserUUID)
+	ctxWithAuthorized := context.WithValue(ctxWithUUID, "authorized", ctxAuthorized)
+	ctxWithSchema := context.WithValue(ctxWithAuthorized, "schema", ctxSchema)
+	ctxWithTransmissionKey := context.WithValue(ctxWithSchema, "transmissionKey", ctxTransmissionKey)
+	ctxWithTokenUUID := context.WithValue(ctxWithTransmissionKey, "tokenUUID", uuid)
+
+	// These context variables can be accesable with
+	// ctxAuthorized := r.Context().Value("authorized").(bool)
+	// ctxID := r.Context().Value("id").(float64)
+
+	next(w, r.WithContext(ctxWithTokenUUID))
+}
@@ -0,0 +1,90 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/passwall/passwall-server/internal/app"
+	"github.com/passwall/passwall-server/internal/storage/memory"
+	"github.com/passwall/passwall-server/model"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/negroni"
+)
+
+func TestEventAuth(t *testing.T) {
+	viper.Set("server.secret", "secret")
+	viper.Set("server.accessTokenExpireDuration", "30m")
+	viper.Set("server.refreshTokenExpireDuration", "1h")
+	viper.Set("server.generatedPasswordLength", 16)
+
+	s := memory.New()
+	require.Nil(t, s.Users().Migrate())
+	require.Nil(t, s.Tokens().Migrate())
+	user, err := s.Users().Save(&model.User{UUID: uuid.NewV4(), Email: "test@passwall.io", Schema: "user1"})
+	require.Nil(t, err)
+
+	td, err := app.CreateToken(user)
+	require.Nil(t, err)
+	s.Tokens().Save(int(user.ID), td.AtUUID, td.AccessToken, td.AtExpiresTime, td.TransmissionKey)
+	eventToken, err := app.CreateEventToken(user.UUID.String(), td.AtUUID.String(), false)
+	require.Nil(t, err)
+
+	serve := func(h negroni.HandlerFunc, query, bearer string) (int, string) {
+		req := httptest.NewRequest(http.MethodGet, "/api/events"+query, nil)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		var key string
+		h(w, req, func(w http.ResponseWriter, r *http.Request) {
+			key = r.Context().Value("transmissionKey").(string)
+		})
+		return w.Code, key
+	}
+
+	code, key := serve(EventAuth(s), "?token="+eventToken, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, td.TransmissionKey, key)
+
+	code, key = serve(EventAuth(s), "", td.AccessToken)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, td.TransmissionKey, key)
+
+	// event tokens don't open the other routes, access tokens stay out of URLs
+	code, _ = serve(Auth(s), "", eventToken)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = serve(EventAuth(s), "?token="+td.AccessToken, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// an event token of an ended session opens nothing
+	s.Tokens().DeleteByUUID(td.AtUUID.String())
+	code, _ = serve(EventAuth(s), "?token="+eventToken, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// expired event tokens leave the session alone
+	td, err = app.CreateToken(user)
+	require.Nil(t, err)
+	s.Tokens().Save(int(user.ID), td.AtUUID, td.AccessToken, td.AtExpiresTime, td.TransmissionKey)
+	expired, err := jwtToken(jwt.MapClaims{
+		"authorized": false,
+		"user_uuid":  user.UUID.String(),
+		"uuid":       td.AtUUID.String(),
+		"purpose":    app.EventTokenPurpose,
+		"exp":        time.Now().Add(-time.Minute).Unix(),
+	})
+	require.Nil(t, err)
+	code, _ = serve(EventAuth(s), "?token="+expired, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	_, ok := s.Tokens().Any(td.AtUUID.String())
+	assert.True(t, ok)
+}
+
+func jwtToken(claims jwt.MapClaims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(viper.GetString("server.secret")))
+}
@@ -42,7 +42,10 @@ func (r *Router) initRoutes() {
 	apiRouter.HandleFunc("/logins/{id:[0-9]+}", api.FindLoginsByID(r.store)).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/logins/{id:[0-9]+}", api.UpdateLogin(r.store)).Methods(http.MethodPut)
 	apiRouter.HandleFunc("/logins/{id:[0-9]+}", api.DeleteLogin(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/logins/{id:[0-9]+}/totp", api.FindLoginTOTP(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/logins/bulk-create", api.BulkCreateLogins(r.store)).Methods(http.MethodPost)
 	apiRouter.HandleFunc("/logins/bulk-update", api.BulkUpdateLogins(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/logins/bulk-delete", api.BulkDeleteLogins(r.store)).Methods(http.MethodPost)
 
 	// Bank Account endpoints
 	apiRouter.HandleFunc("/bank-accounts", api.FindAllBankAccounts(r.store)).Methods(http.MethodGet)
@@ -50,7 +53,9 @@ func (r *Router) initRoutes() {
 	apiRouter.HandleFunc("/bank-accounts/{id:[0-9]+}", api.FindBankAccountByID(r.store)).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/bank-accounts/{id:[0-9]+}", api.UpdateBankAccount(r.store)).Methods(http.MethodPut)
 	apiRouter.HandleFunc("/bank-accounts/{id:[0-9]+}", api.DeleteBankAccount(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/bank-accounts/bulk-create", api.BulkCreateBankAccounts(r.store)).Methods(http.MethodPost)
 	apiRouter.HandleFunc("/bank-accounts/bulk-update", api.BulkUpdateBankAccounts(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/bank-accounts/bulk-delete", api.BulkDeleteBankAccounts(r.store)).Methods(http.MethodPost)
 
 	// Credit Card endpoints
 	apiRouter.HandleFunc("/credit-cards", api.FindAllCreditCards(r.store)).Methods(http.MethodGet)
@@ -58,7 +63,9 @@ func (r *Router) initRoutes() {
 	apiRouter.HandleFunc("/credit-cards/{id:[0-9]+}", api.FindCreditCardByID(r.store)).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/credit-cards/{id:[0-9]+}", api.UpdateCreditCard(r.store)).Methods(http.MethodPut)
 	apiRouter.HandleFunc("/credit-cards/{id:[0-9]+}", api.DeleteCreditCard(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/credit-cards/bulk-create", api.BulkCreateCreditCards(r.store)).Methods(http.MethodPost)
 	apiRouter.HandleFunc("/credit-cards/bulk-update", api.BulkUpdateCreditCards(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/credit-cards/bulk-delete", api.BulkDeleteCreditCards(r.store)).Methods(http.MethodPost)
 
 	// Note endpoints
 	apiRouter.HandleFunc("/notes", api.FindAllNotes(r.store)).Methods(http.MethodGet)
@@ -66,7 +73,9 @@ func (r *Router) initRoutes() {
 	apiRouter.HandleFunc("/notes/{id:[0-9]+}", api.FindNoteByID(r.store)).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/notes/{id:[0-9]+}", api.UpdateNote(r.store)).Methods(http.MethodPut)
 	apiRouter.HandleFunc("/notes/{id:[0-9]+}", api.DeleteNote(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/notes/bulk-create", api.BulkCreateNotes(r.store)).Methods(http.MethodPost)
 	apiRouter.HandleFunc("/notes/bulk-update", api.BulkUpdateNotes(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/notes/bulk-delete", api.BulkDeleteNotes(r.store)).Methods(http.MethodPost)
 
 	// Email endpoints
 	apiRouter.HandleFunc("/emails", api.FindAllEmails(r.store)).Methods(http.MethodGet)
@@ -74,7 +83,40 @@ func (r *Router) initRoutes() {
 	apiRouter.HandleFunc("/emails/{id:[0-9]+}", api.FindEmailByID(r.store)).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/emails/{id:[0-9]+}", api.UpdateEmail(r.store)).Methods(http.MethodPut)
 	apiRouter.HandleFunc("/emails/{id:[0-9]+}", api.DeleteEmail(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/emails/bulk-create", api.BulkCreateEmails(r.store)).Methods(http.MethodPost)
 	apiRouter.HandleFunc("/emails/bulk-update", api.BulkUpdateEmails(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/emails/bulk-delete", api.BulkDeleteEmails(r.store)).Methods(http.MethodPost)
+
+	// Identity endpoints
+	apiRouter.HandleFunc("/identities", api.FindAllIdentities(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/identities", api.CreateIdentity(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/identities/{id:[0-9]+}", api.FindIdentityByID(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/identities/{id:[0-9]+}", api.UpdateIdentity(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/identities/{id:[0-9]+}", api.DeleteIdentity(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/identities/bulk-create", api.BulkCreateIdentities(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/identities/bulk-update", api.BulkUpdateIdentities(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/identities/bulk-delete", api.BulkDeleteIdentities(r.store)).Methods(http.MethodPost)
+
+	// License key endpoints
+	apiRouter.HandleFunc("/license-keys", api.FindAllLicenseKeys(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/license-keys", api.CreateLicenseKey(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/license-keys/{id:[0-9]+}", api.FindLicenseKeyByID(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/license-keys/{id:[0-9]+}", api.UpdateLicenseKey(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/license-keys/{id:[0-9]+}", api.DeleteLicenseKey(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/license-keys/bulk-create", api.BulkCreateLicenseKeys(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/license-keys/bulk-update", api.BulkUpdateLicenseKeys(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/license-keys/bulk-delete", api.BulkDeleteLicenseKeys(r.store)).Methods(http.MethodPost)
+
+	// SSH key endpoints
+	apiRouter.HandleFunc("/ssh-keys", api.FindAllSSHKeys(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/ssh-keys", api.CreateSSHKey(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/ssh-keys/generate", api.GenerateSSHKey(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/ssh-keys/{id:[0-9]+}", api.FindSSHKeyByID(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/ssh-keys/{id:[0-9]+}", api.UpdateSSHKey(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/ssh-keys/{id:[0-9]+}", api.DeleteSSHKey(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/ssh-keys/bulk-create", api.BulkCreateSSHKeys(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/ssh-keys/bulk-update", api.BulkUpdateSSHKeys(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/ssh-keys/bulk-delete", api.BulkDeleteSSHKeys(r.store)).Methods(http.MethodPost)
 
 	// Server endpoints
 	apiRouter.HandleFunc("/servers", api.FindAllServers(r.store)).Methods(http.MethodGet)
@@ -82,7 +124,67 @@ func (r *Router) initRoutes() {
 	apiRouter.HandleFunc("/servers/{id:[0-9]+}", api.FindServerByID(r.store)).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/servers/{id:[0-9]+}", api.UpdateServer(r.store)).Methods(http.MethodPut)
 	apiRouter.HandleFunc("/servers/{id:[0-9]+}", api.DeleteServer(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/servers/bulk-create", api.BulkCreateServers(r.store)).Methods(http.MethodPost)
 	apiRouter.HandleFunc("/servers/bulk-update", api.BulkUpdateServers(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/servers/bulk-delete", api.BulkDeleteServers(r.store)).Methods(http.MethodPost)
+
+	// Template endpoints
+	apiRouter.HandleFunc("/templates", api.FindAllTemplates(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/templates", api.CreateTemplate(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/templates/{id:[0-9]+}", api.FindTemplateByID(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/templates/{id:[0-9]+}", api.UpdateTemplate(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/templates/{id:[0-9]+}", api.DeleteTemplate(r.store)).Methods(http.MethodDelete)
+
+	// Custom item endpoints, the type is the type of a template
+	apiRouter.HandleFunc("/items/{type:[a-z0-9-]+}", api.FindAllCustomItems(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/items/{type:[a-z0-9-]+}", api.CreateCustomItem(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/items/{type:[a-z0-9-]+}/{id:[0-9]+}", api.FindCustomItemByID(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/items/{type:[a-z0-9-]+}/{id:[0-9]+}", api.UpdateCustomItem(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/items/{type:[a-z0-9-]+}/{id:[0-9]+}", api.DeleteCustomItem(r.store)).Methods(http.MethodDelete)
+
+	// Attachment endpoints
+	apiRouter.HandleFunc("/attachments/{type}/{id:[0-9]+}", api.FindAttachments(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/attachments/{type}/{id:[0-9]+}", api.UploadAttachment(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/attachments/{id:[0-9]+}", api.DownloadAttachment(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/attachments/{id:[0-9]+}", api.DeleteAttachment(r.store)).Methods(http.MethodDelete)
+
+	// Folder endpoints
+	apiRouter.HandleFunc("/folders", api.FindAllFolders(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/folders", api.CreateFolder(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/folders/move-items", api.MoveItems(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/folders/{id:[0-9]+}", api.FindFolderByID(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/folders/{id:[0-9]+}", api.UpdateFolder(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/folders/{id:[0-9]+}", api.DeleteFolder(r.store)).Methods(http.MethodDelete)
+
+	// Tag endpoints
+	apiRouter.HandleFunc("/tags", api.FindAllTags(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/tags", api.CreateTag(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/tags/{id:[0-9]+}", api.UpdateTag(r.store)).Methods(http.MethodPut)
+	apiRouter.HandleFunc("/tags/{id:[0-9]+}", api.DeleteTag(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/tags/{id:[0-9]+}/merge", api.MergeTag(r.store)).Methods(http.MethodPost)
+
+	// Favorite endpoints
+	apiRouter.HandleFunc("/favorites", api.FindAllFavorites(r.store)).Methods(http.MethodGet)
+
+	// Search endpoints
+	apiRouter.HandleFunc("/search", api.Search(r.store)).Methods(http.MethodGet)
+
+	// Sync endpoints
+	apiRouter.HandleFunc("/sync", api.Sync(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/events", api.StreamEvents(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/events/token", api.CreateEventToken).Methods(http.MethodPost)
+
+	// Trash endpoints
+	apiRouter.HandleFunc("/trash", api.FindAllTrash(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/trash", api.EmptyTrash(r.store)).Methods(http.MethodDelete)
+	apiRouter.HandleFunc("/trash/{type}/{id:[0-9]+}/restore", api.RestoreTrashItem(r.store)).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/trash/{type}/{id:[0-9]+}", api.PurgeTrashItem(r.store)).Methods(http.MethodDelete)
+
+	// Revision endpoints
+	apiRouter.HandleFunc("/revisions/{type}/{id:[0-9]+}", api.FindRevisions(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/revisions/{type}/{id:[0-9]+}/{revision:[0-9]+}", api.FindRevision(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/revisions/{type}/{id:[0-9]+}/{revision:[0-9]+}/diff", api.DiffRevision(r.store)).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/revisions/{type}/{id:[0-9]+}/{revision:[0-9]+}/restore", api.RestoreRevision(r.store)).Methods(http.MethodPost)
 
 	// User endpoints
 	apiRouter.HandleFunc("/users", api.FindAllUsers(r.store)).Methods(http.MethodGet)
@@ -135,6 +237,12 @@ func (r *Router) initRoutes() {
 		negroni.Wrap(webRouter),
 	))
 
+	// EventSource can't send headers, the stream takes an event token too
+	r.router.Path("/api/events").Methods(http.MethodGet).Handler(n.With(
+		EventAuth(r.store),
+		negroni.Wrap(apiRouter),
+	))
+
 	r.router.PathPrefix("/api").Handler(n.With(
 		Auth(r.store),
 		negroni.Wrap(apiRouter),
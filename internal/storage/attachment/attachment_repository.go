@@ -0,0 +1,74 @@
+package attachment
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Repository ...
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "attachments")
+}
+
+// FindAll ...
+func (p *Repository) FindAll(itemType string, itemID uint, schema string) ([]model.Attachment, error) {
+	attachments := []model.Attachment{}
+	err := p.db.Table(p.table(schema)).
+		Where("item_type = ? AND item_id = ?", itemType, itemID).
+		Order("id").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// FindAllByItems ...
+func (p *Repository) FindAllByItems(itemType string, itemIDs []uint, schema string) ([]model.Attachment, error) {
+	attachments := []model.Attachment{}
+	if len(itemIDs) == 0 {
+		return attachments, nil
+	}
+	err := p.db.Table(p.table(schema)).
+		Where("item_type = ? AND item_id IN (?)", itemType, itemIDs).
+		Order("id").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// FindByID ...
+func (p *Repository) FindByID(id uint, schema string) (*model.Attachment, error) {
+	attachment := new(model.Attachment)
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&attachment).Error
+	return attachment, err
+}
+
+// TotalSize ...
+func (p *Repository) TotalSize(schema string) (int64, error) {
+	var total struct{ Size int64 }
+	err := p.db.Table(p.table(schema)).Select("COALESCE(SUM(size), 0) AS size").Scan(&total).Error
+	return total.Size, err
+}
+
+// Save ...
+func (p *Repository) Save(attachment *model.Attachment, schema string) (*model.Attachment, error) {
+	err := p.db.Table(p.table(schema)).Save(&attachment).Error
+	return attachment, err
+}
+
+// Delete ...
+func (p *Repository) Delete(id uint, schema string) error {
+	return p.db.Table(p.table(schema)).Where("id = ?", id).Delete(&model.Attachment{}).Error
+}
+
+// Migrate ...
+func (p *Repository) Migrate(schema string) error {
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Attachment{}).Error
+}
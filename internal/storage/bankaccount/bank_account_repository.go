@@ -1,7 +1,14 @@
 package bankaccount
 
 import (
+	"strings"
+	"time"
+
 	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
 	"github.com/passwall/passwall-server/model"
 )
 
@@ -15,60 +22,137 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "bank_accounts")
+}
+
 // All ...
 func (p *Repository) All(schema string) ([]model.BankAccount, error) {
 	bankAccounts := []model.BankAccount{}
-	err := p.db.Table(schema + ".bank_accounts").Find(&bankAccounts).Error
+	err := p.db.Table(p.table(schema)).Find(&bankAccounts).Error
 	return bankAccounts, err
 }
 
 // FindAll ...
 func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.BankAccount, error) {
 	bankAccounts := []model.BankAccount{}
+	query := pagination.Apply(p.filter(argsStr, schema), argsStr, argsInt)
+	err := query.Find(&bankAccounts).Error
+	return bankAccounts, err
+}
 
-	query := p.db
-	query = query.Table(schema + ".bank_accounts")
-	query = query.Limit(argsInt["limit"])
-	if argsInt["limit"] > 0 {
-		// offset can't be declared without a valid limit
-		query = query.Offset(argsInt["offset"])
-	}
+// Count returns the number of entities matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string, schema string) (int, error) {
+	count := 0
+	err := p.filter(argsStr, schema).Model(&model.BankAccount{}).Count(&count).Error
+	return count, err
+}
 
-	query = query.Order(argsStr["order"])
+// filter returns the query of the entities matching the search and the filters
+func (p *Repository) filter(argsStr map[string]string, schema string) *gorm.DB {
+	query := p.db.Table(p.table(schema))
 
 	if argsStr["search"] != "" {
-		query = query.Where("bank_name LIKE ?", "%"+argsStr["search"]+"%")
+		condition, values := search.Condition(argsStr, "bank_name", "bank_code")
+		query = query.Where(condition, values...)
+	}
 
-		fields := []string{"bank_code", "account_name", "account_number", "iban", "currency"}
-		for i := range fields {
-			query = query.Or(fields[i]+" LIKE ?", "%"+argsStr["search"]+"%")
-		}
+	if argsStr["favorite"] != "" {
+		query = query.Where("favorite = ?", argsStr["favorite"] == "true")
 	}
 
-	err := query.Find(&bankAccounts).Error
-	return bankAccounts, err
+	if argsStr["folder"] == "0" {
+		query = query.Where("folder_id IS NULL")
+	} else if argsStr["folder"] != "" {
+		query = query.Where("folder_id = ?", argsStr["folder"])
+	}
+
+	if argsStr["tags"] != "" {
+		tagged := p.db.Table(dialect.Table(p.db, schema, "item_tags")).Select("item_id").
+			Where("item_type = ? AND tag_id IN (?)", model.BankAccountItem, strings.Split(argsStr["tags"], ",")).
+			SubQuery()
+		query = query.Where("id IN ?", tagged)
+	}
+
+	return query
 }
 
 // FindByID ...
 func (p *Repository) FindByID(id uint, schema string) (*model.BankAccount, error) {
 	bankAccount := new(model.BankAccount)
-	err := p.db.Table(schema+".bank_accounts").Where(`id = ?`, id).First(&bankAccount).Error
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&bankAccount).Error
 	return bankAccount, err
 }
 
 // Save ...
 func (p *Repository) Save(bankAccount *model.BankAccount, schema string) (*model.BankAccount, error) {
-	err := p.db.Table(schema + ".bank_accounts").Save(&bankAccount).Error
+	err := p.db.Unscoped().Table(p.table(schema)).Save(&bankAccount).Error
+	return bankAccount, err
+}
+
+// Update ...
+func (p *Repository) Update(bankAccount *model.BankAccount, revision uint, schema string) (*model.BankAccount, error) {
+	err := update.Revision(p.db, p.table(schema), bankAccount, revision)
+	return bankAccount, err
+}
+
+// SaveEncrypted ...
+func (p *Repository) SaveEncrypted(bankAccount *model.BankAccount, schema string) (*model.BankAccount, error) {
+	err := update.Encrypted(p.db, p.table(schema), bankAccount)
 	return bankAccount, err
 }
 
 // Delete ...
 func (p *Repository) Delete(id uint, schema string) error {
-	err := p.db.Table(schema + ".bank_accounts").Delete(&model.BankAccount{ID: id}).Error
+	err := p.db.Table(p.table(schema)).Delete(&model.BankAccount{ID: id}).Error
 	return err
 }
 
+// FindAllDeleted ...
+func (p *Repository) FindAllDeleted(schema string) ([]model.BankAccount, error) {
+	bankAccounts := []model.BankAccount{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&bankAccounts).Error
+	return bankAccounts, err
+}
+
+// FindAllChanged ...
+func (p *Repository) FindAllChanged(since time.Time, schema string) ([]model.BankAccount, error) {
+	bankAccounts := []model.BankAccount{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("updated_at > ? OR deleted_at > ?", since, since).Order("id").Find(&bankAccounts).Error
+	return bankAccounts, err
+}
+
+// Restore ...
+func (p *Repository) Restore(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge ...
+func (p *Repository) Purge(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.BankAccount{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedBefore ...
+func (p *Repository) PurgeDeletedBefore(t time.Time, schema string) error {
+	return p.db.Unscoped().Table(p.table(schema)).Where("deleted_at < ?", t).Delete(&model.BankAccount{}).Error
+}
+
 // Migrate ...
 func (p *Repository) Migrate(schema string) error {
-	return p.db.Table(schema + ".bank_accounts").AutoMigrate(&model.BankAccount{}).Error
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.BankAccount{}).Error
 }
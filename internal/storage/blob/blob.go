@@ -0,0 +1,41 @@
+// Package blob stores the contents of files, like attachments, outside of
+// the database. Blobs are written once and read as streams, so files don't
+// need to fit in memory.
+package blob
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned when there is no blob with the key
+var ErrNotFound = errors.New("blob not found")
+
+// ErrInvalidKey is returned when a key is empty or has empty or dot segments
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store is the common interface for the blob stores
+type Store interface {
+	// Put writes the content of r as the blob with the key and returns its size.
+	// Nothing is stored when reading r fails.
+	Put(key string, r io.Reader) (int64, error)
+	// Get opens the blob with the key, the caller closes it
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes the blob with the key, deleting a missing blob is not an error
+	Delete(key string) error
+}
+
+// checkKey checks that a key is made of slash separated segments which
+// can't escape the store, like "user1/5f0c7b0e"
+func checkKey(key string) error {
+	if key == "" {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsRune(segment, '\\') {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
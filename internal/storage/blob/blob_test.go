@@ -0,0 +1,42 @@
+package blob
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, store := range map[string]Store{"Local": NewLocal(dir), "Memory": NewMemory()} {
+		t.Run(name, func(t *testing.T) {
+			size, err := store.Put("user1/file", strings.NewReader("content"))
+			require.Nil(t, err)
+			assert.Equal(t, int64(7), size)
+
+			r, err := store.Get("user1/file")
+			require.Nil(t, err)
+			data, err := ioutil.ReadAll(r)
+			r.Close()
+			require.Nil(t, err)
+			assert.Equal(t, "content", string(data))
+
+			require.Nil(t, store.Delete("user1/file"))
+			_, err = store.Get("user1/file")
+			assert.Equal(t, ErrNotFound, err)
+			assert.Nil(t, store.Delete("user1/file"), "deleting a missing blob is not an error")
+
+			for _, key := range []string{"", "../file", "user1//file", "user1/..", `user1\file`} {
+				_, err := store.Put(key, strings.NewReader("content"))
+				assert.Equal(t, ErrInvalidKey, err, "%q should be invalid", key)
+			}
+		})
+	}
+}
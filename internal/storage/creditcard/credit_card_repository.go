@@ -1,7 +1,14 @@
 package creditcard
 
 import (
+	"strings"
+	"time"
+
 	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
 	"github.com/passwall/passwall-server/model"
 )
 
@@ -15,60 +22,137 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "credit_cards")
+}
+
 // All ...
 func (p *Repository) All(schema string) ([]model.CreditCard, error) {
 	creditCards := []model.CreditCard{}
-	err := p.db.Table(schema + ".credit_cards").Find(&creditCards).Error
+	err := p.db.Table(p.table(schema)).Find(&creditCards).Error
 	return creditCards, err
 }
 
 // FindAll ...
 func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.CreditCard, error) {
 	creditCards := []model.CreditCard{}
+	query := pagination.Apply(p.filter(argsStr, schema), argsStr, argsInt)
+	err := query.Find(&creditCards).Error
+	return creditCards, err
+}
 
-	query := p.db
-	query = query.Table(schema + ".credit_cards")
-	query = query.Limit(argsInt["limit"])
-	if argsInt["limit"] > 0 {
-		// offset can't be declared without a valid limit
-		query = query.Offset(argsInt["offset"])
-	}
+// Count returns the number of entities matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string, schema string) (int, error) {
+	count := 0
+	err := p.filter(argsStr, schema).Model(&model.CreditCard{}).Count(&count).Error
+	return count, err
+}
 
-	query = query.Order(argsStr["order"])
+// filter returns the query of the entities matching the search and the filters
+func (p *Repository) filter(argsStr map[string]string, schema string) *gorm.DB {
+	query := p.db.Table(p.table(schema))
 
 	if argsStr["search"] != "" {
-		query = query.Where("card_name LIKE ?", "%"+argsStr["search"]+"%")
+		condition, values := search.Condition(argsStr, "card_name")
+		query = query.Where(condition, values...)
+	}
 
-		fields := []string{"cardholder_name", "type", "number", "verification_number", "expiry_date"}
-		for i := range fields {
-			query = query.Or(fields[i]+" LIKE ?", "%"+argsStr["search"]+"%")
-		}
+	if argsStr["favorite"] != "" {
+		query = query.Where("favorite = ?", argsStr["favorite"] == "true")
 	}
 
-	err := query.Find(&creditCards).Error
-	return creditCards, err
+	if argsStr["folder"] == "0" {
+		query = query.Where("folder_id IS NULL")
+	} else if argsStr["folder"] != "" {
+		query = query.Where("folder_id = ?", argsStr["folder"])
+	}
+
+	if argsStr["tags"] != "" {
+		tagged := p.db.Table(dialect.Table(p.db, schema, "item_tags")).Select("item_id").
+			Where("item_type = ? AND tag_id IN (?)", model.CreditCardItem, strings.Split(argsStr["tags"], ",")).
+			SubQuery()
+		query = query.Where("id IN ?", tagged)
+	}
+
+	return query
 }
 
 // FindByID ...
 func (p *Repository) FindByID(id uint, schema string) (*model.CreditCard, error) {
 	creditCard := new(model.CreditCard)
-	err := p.db.Table(schema+".credit_cards").Where(`id = ?`, id).First(&creditCard).Error
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&creditCard).Error
 	return creditCard, err
 }
 
 // Save ...
 func (p *Repository) Save(creditCard *model.CreditCard, schema string) (*model.CreditCard, error) {
-	err := p.db.Table(schema + ".credit_cards").Save(&creditCard).Error
+	err := p.db.Unscoped().Table(p.table(schema)).Save(&creditCard).Error
+	return creditCard, err
+}
+
+// Update ...
+func (p *Repository) Update(creditCard *model.CreditCard, revision uint, schema string) (*model.CreditCard, error) {
+	err := update.Revision(p.db, p.table(schema), creditCard, revision)
+	return creditCard, err
+}
+
+// SaveEncrypted ...
+func (p *Repository) SaveEncrypted(creditCard *model.CreditCard, schema string) (*model.CreditCard, error) {
+	err := update.Encrypted(p.db, p.table(schema), creditCard)
 	return creditCard, err
 }
 
 // Delete ...
 func (p *Repository) Delete(id uint, schema string) error {
-	err := p.db.Table(schema + ".credit_cards").Delete(&model.CreditCard{ID: id}).Error
+	err := p.db.Table(p.table(schema)).Delete(&model.CreditCard{ID: id}).Error
 	return err
 }
 
+// FindAllDeleted ...
+func (p *Repository) FindAllDeleted(schema string) ([]model.CreditCard, error) {
+	creditCards := []model.CreditCard{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&creditCards).Error
+	return creditCards, err
+}
+
+// FindAllChanged ...
+func (p *Repository) FindAllChanged(since time.Time, schema string) ([]model.CreditCard, error) {
+	creditCards := []model.CreditCard{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("updated_at > ? OR deleted_at > ?", since, since).Order("id").Find(&creditCards).Error
+	return creditCards, err
+}
+
+// Restore ...
+func (p *Repository) Restore(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge ...
+func (p *Repository) Purge(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.CreditCard{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedBefore ...
+func (p *Repository) PurgeDeletedBefore(t time.Time, schema string) error {
+	return p.db.Unscoped().Table(p.table(schema)).Where("deleted_at < ?", t).Delete(&model.CreditCard{}).Error
+}
+
 // Migrate ...
 func (p *Repository) Migrate(schema string) error {
-	return p.db.Table(schema + ".credit_cards").AutoMigrate(&model.CreditCard{}).Error
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.CreditCard{}).Error
 }
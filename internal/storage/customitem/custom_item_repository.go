@@ -0,0 +1,162 @@
+package customitem
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Repository ...
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "custom_items")
+}
+
+// All ...
+func (p *Repository) All(schema string) ([]model.CustomItem, error) {
+	customItems := []model.CustomItem{}
+	err := p.db.Table(p.table(schema)).Find(&customItems).Error
+	return customItems, err
+}
+
+// FindAll ...
+func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.CustomItem, error) {
+	customItems := []model.CustomItem{}
+	query := pagination.Apply(p.filter(argsStr, schema), argsStr, argsInt)
+	err := query.Find(&customItems).Error
+	return customItems, err
+}
+
+// Count returns the number of entities matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string, schema string) (int, error) {
+	count := 0
+	err := p.filter(argsStr, schema).Model(&model.CustomItem{}).Count(&count).Error
+	return count, err
+}
+
+// filter returns the query of the entities matching the search and the filters
+func (p *Repository) filter(argsStr map[string]string, schema string) *gorm.DB {
+	query := p.db.Table(p.table(schema))
+
+	if argsStr["search"] != "" {
+		condition, values := search.Condition(argsStr, "title")
+		query = query.Where(condition, values...)
+	}
+
+	if argsStr["type"] != "" {
+		query = query.Where("type = ?", argsStr["type"])
+	}
+
+	if argsStr["favorite"] != "" {
+		query = query.Where("favorite = ?", argsStr["favorite"] == "true")
+	}
+
+	if argsStr["folder"] == "0" {
+		query = query.Where("folder_id IS NULL")
+	} else if argsStr["folder"] != "" {
+		query = query.Where("folder_id = ?", argsStr["folder"])
+	}
+
+	if argsStr["tags"] != "" {
+		tagged := p.db.Table(dialect.Table(p.db, schema, "item_tags")).Select("item_id").
+			Where("item_type = ? AND tag_id IN (?)", model.CustomItemItem, strings.Split(argsStr["tags"], ",")).
+			SubQuery()
+		query = query.Where("id IN ?", tagged)
+	}
+
+	return query
+}
+
+// FindByID ...
+func (p *Repository) FindByID(id uint, schema string) (*model.CustomItem, error) {
+	customItem := new(model.CustomItem)
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&customItem).Error
+	return customItem, err
+}
+
+// Save ...
+func (p *Repository) Save(customItem *model.CustomItem, schema string) (*model.CustomItem, error) {
+	err := p.db.Unscoped().Table(p.table(schema)).Save(&customItem).Error
+	return customItem, err
+}
+
+// Update ...
+func (p *Repository) Update(customItem *model.CustomItem, revision uint, schema string) (*model.CustomItem, error) {
+	err := update.Revision(p.db, p.table(schema), customItem, revision)
+	return customItem, err
+}
+
+// SaveEncrypted ...
+func (p *Repository) SaveEncrypted(customItem *model.CustomItem, schema string) (*model.CustomItem, error) {
+	err := update.Encrypted(p.db, p.table(schema), customItem)
+	return customItem, err
+}
+
+// Delete ...
+func (p *Repository) Delete(id uint, schema string) error {
+	err := p.db.Table(p.table(schema)).Delete(&model.CustomItem{ID: id}).Error
+	return err
+}
+
+// FindAllDeleted ...
+func (p *Repository) FindAllDeleted(schema string) ([]model.CustomItem, error) {
+	customItems := []model.CustomItem{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&customItems).Error
+	return customItems, err
+}
+
+// FindAllChanged ...
+func (p *Repository) FindAllChanged(since time.Time, schema string) ([]model.CustomItem, error) {
+	customItems := []model.CustomItem{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("updated_at > ? OR deleted_at > ?", since, since).Order("id").Find(&customItems).Error
+	return customItems, err
+}
+
+// Restore ...
+func (p *Repository) Restore(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge ...
+func (p *Repository) Purge(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.CustomItem{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedBefore ...
+func (p *Repository) PurgeDeletedBefore(t time.Time, schema string) error {
+	return p.db.Unscoped().Table(p.table(schema)).Where("deleted_at < ?", t).Delete(&model.CustomItem{}).Error
+}
+
+// Migrate ...
+func (p *Repository) Migrate(schema string) error {
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.CustomItem{}).Error
+}
@@ -2,18 +2,35 @@ package storage
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/postgres"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
 	"github.com/passwall/passwall-server/internal/config"
+	"github.com/passwall/passwall-server/internal/storage/attachment"
 	"github.com/passwall/passwall-server/internal/storage/bankaccount"
+	"github.com/passwall/passwall-server/internal/storage/blob"
 	"github.com/passwall/passwall-server/internal/storage/creditcard"
+	"github.com/passwall/passwall-server/internal/storage/customitem"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
 	"github.com/passwall/passwall-server/internal/storage/email"
+	"github.com/passwall/passwall-server/internal/storage/event"
+	"github.com/passwall/passwall-server/internal/storage/folder"
+	"github.com/passwall/passwall-server/internal/storage/identity"
+	"github.com/passwall/passwall-server/internal/storage/licensekey"
 	"github.com/passwall/passwall-server/internal/storage/login"
+	"github.com/passwall/passwall-server/internal/storage/migration"
 	"github.com/passwall/passwall-server/internal/storage/note"
+	"github.com/passwall/passwall-server/internal/storage/revision"
 	"github.com/passwall/passwall-server/internal/storage/server"
+	"github.com/passwall/passwall-server/internal/storage/sshkey"
 	"github.com/passwall/passwall-server/internal/storage/subscription"
+	"github.com/passwall/passwall-server/internal/storage/tag"
+	"github.com/passwall/passwall-server/internal/storage/template"
 	"github.com/passwall/passwall-server/internal/storage/token"
+	"github.com/passwall/passwall-server/internal/storage/tombstone"
 	"github.com/passwall/passwall-server/internal/storage/user"
 )
 
@@ -28,15 +45,32 @@ type Database struct {
 	tokens        TokenRepository
 	users         UserRepository
 	servers       ServerRepository
+	identities    IdentityRepository
+	licenseKeys   LicenseKeyRepository
+	customItems   CustomItemRepository
+	sshKeys       SSHKeyRepository
+	templates     TemplateRepository
+	attachments   AttachmentRepository
+	revisions     RevisionRepository
+	tombstones    TombstoneRepository
+	folders       FolderRepository
+	tags          TagRepository
 	subscriptions SubscriptionRepository
+	migrations    MigrationRepository
+	blobs         blob.Store
+	events        event.Broker
 }
 
 //DBConn databese connection
 func DBConn(cfg *config.DatabaseConfiguration) (*gorm.DB, error) {
+	if cfg.Driver == "sqlite" || cfg.Driver == dialect.SQLite {
+		return sqliteConn(cfg)
+	}
+
 	var db *gorm.DB
 	var err error
 
-	db, err = gorm.Open("postgres", "host="+cfg.Host+" port="+cfg.Port+" user="+cfg.Username+" dbname="+cfg.Name+"  sslmode=disable password="+cfg.Password)
+	db, err = gorm.Open(dialect.Postgres, "host="+cfg.Host+" port="+cfg.Port+" user="+cfg.Username+" dbname="+cfg.Name+"  sslmode=disable password="+cfg.Password)
 	if err != nil {
 		return nil, fmt.Errorf("could not open postgresql connection: %w", err)
 	}
@@ -46,8 +80,30 @@ func DBConn(cfg *config.DatabaseConfiguration) (*gorm.DB, error) {
 	return db, err
 }
 
-// New opens a database according to configuration.
-func New(db *gorm.DB) *Database {
+// sqliteConn opens the SQLite database file defined in configuration
+func sqliteConn(cfg *config.DatabaseConfiguration) (*gorm.DB, error) {
+	if cfg.Path != ":memory:" {
+		if err := os.MkdirAll(filepath.Dir(cfg.Path), 0755); err != nil {
+			return nil, fmt.Errorf("could not create sqlite folder: %w", err)
+		}
+	}
+
+	db, err := gorm.Open(dialect.SQLite, cfg.Path+"?_busy_timeout=5000&_foreign_keys=on")
+	if err != nil {
+		return nil, fmt.Errorf("could not open sqlite connection: %w", err)
+	}
+
+	// SQLite allows a single writer, sharing one connection avoids
+	// "database is locked" errors and keeps :memory: databases alive
+	db.DB().SetMaxOpenConns(1)
+	db.LogMode(cfg.LogMode)
+
+	return db, nil
+}
+
+// New opens a database according to configuration, the contents of
+// attachments are kept in blobs and the item events are published to events.
+func New(db *gorm.DB, blobs blob.Store, events event.Broker) *Database {
 	return &Database{
 		db:            db,
 		logins:        login.NewRepository(db),
@@ -58,7 +114,20 @@ func New(db *gorm.DB) *Database {
 		tokens:        token.NewRepository(db),
 		users:         user.NewRepository(db),
 		servers:       server.NewRepository(db),
+		identities:    identity.NewRepository(db),
+		licenseKeys:   licensekey.NewRepository(db),
+		customItems:   customitem.NewRepository(db),
+		sshKeys:       sshkey.NewRepository(db),
+		templates:     template.NewRepository(db),
+		attachments:   attachment.NewRepository(db),
+		revisions:     revision.NewRepository(db),
+		tombstones:    tombstone.NewRepository(db),
+		folders:       folder.NewRepository(db),
+		tags:          tag.NewRepository(db),
 		subscriptions: subscription.NewRepository(db),
+		migrations:    migration.NewRepository(db),
+		blobs:         blobs,
+		events:        events,
 	}
 }
 
@@ -102,11 +171,88 @@ func (db *Database) Servers() ServerRepository {
 	return db.servers
 }
 
+// Identities returns the IdentityRepository.
+func (db *Database) Identities() IdentityRepository {
+	return db.identities
+}
+
+// LicenseKeys returns the LicenseKeyRepository.
+func (db *Database) LicenseKeys() LicenseKeyRepository {
+	return db.licenseKeys
+}
+
+// CustomItems returns the CustomItemRepository.
+func (db *Database) CustomItems() CustomItemRepository {
+	return db.customItems
+}
+
+// SSHKeys returns the SSHKeyRepository.
+func (db *Database) SSHKeys() SSHKeyRepository {
+	return db.sshKeys
+}
+
+// Templates returns the TemplateRepository.
+func (db *Database) Templates() TemplateRepository {
+	return db.templates
+}
+
+// Attachments returns the AttachmentRepository.
+func (db *Database) Attachments() AttachmentRepository {
+	return db.attachments
+}
+
+// Revisions returns the RevisionRepository.
+func (db *Database) Revisions() RevisionRepository {
+	return db.revisions
+}
+
+// Tombstones returns the TombstoneRepository.
+func (db *Database) Tombstones() TombstoneRepository {
+	return db.tombstones
+}
+
+// Folders returns the FolderRepository.
+func (db *Database) Folders() FolderRepository {
+	return db.folders
+}
+
+// Tags returns the TagRepository.
+func (db *Database) Tags() TagRepository {
+	return db.tags
+}
+
 // Subscriptions returns the UserRepository.
 func (db *Database) Subscriptions() SubscriptionRepository {
 	return db.subscriptions
 }
 
+// Migrations returns the MigrationRepository.
+func (db *Database) Migrations() MigrationRepository {
+	return db.migrations
+}
+
+// Blobs returns the blob store.
+func (db *Database) Blobs() blob.Store {
+	return db.blobs
+}
+
+// Events returns the broker of the item events.
+func (db *Database) Events() event.Broker {
+	return db.events
+}
+
+// Transaction runs fn with a store whose repositories share a database transaction
+func (db *Database) Transaction(fn func(tx Store) error) error {
+	events := event.NewBuffer(db.events)
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		return fn(New(tx, db.blobs, events))
+	})
+	if err != nil {
+		return err
+	}
+	return events.Flush()
+}
+
 // Ping checks if database is up
 func (db *Database) Ping() error {
 	return db.db.DB().Ping()
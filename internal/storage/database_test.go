@@ -0,0 +1,139 @@
+package storage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/config"
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/blob"
+	"github.com/passwall/passwall-server/internal/storage/event"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/storagetest"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteContract(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Store {
+		db, err := storage.DBConn(&config.DatabaseConfiguration{
+			Driver: "sqlite",
+			Path:   filepath.Join(tempDir(t), "passwall.db"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return storage.New(db, blob.NewMemory(), event.NewLocal())
+	})
+}
+
+func TestPostgresContract(t *testing.T) {
+	// should be same with the one on github actions
+	db, err := storage.DBConn(&config.DatabaseConfiguration{
+		Name:     "passwall",
+		Username: "postgres",
+		Password: "postgres",
+		Host:     "localhost",
+		Port:     "5432",
+	})
+	if err != nil {
+		t.Skipf("postgres is not available: %v", err)
+	}
+	defer db.Close()
+
+	storagetest.Run(t, func(t *testing.T) storage.Store {
+		return storage.New(db, blob.NewMemory(), event.NewLocal())
+	})
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "passwall-sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestSQLiteStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "passwall-sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := storage.DBConn(&config.DatabaseConfiguration{
+		Driver: "sqlite",
+		Path:   filepath.Join(dir, "passwall.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	s := storage.New(db, blob.NewMemory(), event.NewLocal())
+	assert.Nil(t, s.Ping())
+	assert.Nil(t, s.Users().Migrate())
+
+	user, err := s.Users().Save(&model.User{Email: "patron@passwall.io", Schema: "user1"})
+	assert.Nil(t, err)
+	assert.Nil(t, s.Users().CreateSchema(user.Schema))
+	assert.Nil(t, s.Logins().Migrate(user.Schema))
+	assert.Nil(t, s.Servers().Migrate(user.Schema))
+	assert.True(t, db.HasTable("user1_logins"))
+
+	login, err := s.Logins().Save(&model.Login{Title: "PassWall", URL: "https://passwall.io"}, user.Schema)
+	assert.Nil(t, err)
+
+	found, err := s.Logins().FindByID(login.ID, user.Schema)
+	assert.Nil(t, err)
+	assert.Equal(t, "PassWall", found.Title)
+
+	// Deleting the user drops every table of its schema
+	assert.Nil(t, s.Users().Delete(user.ID, user.Schema))
+	assert.False(t, db.HasTable("user1_logins"))
+	assert.False(t, db.HasTable("user1_servers"))
+	assert.True(t, db.HasTable("users"))
+}
+
+func TestSQLiteCursorZone(t *testing.T) {
+	// SQLite compares times as text, the zone offset is part of it
+	local := time.Local
+	defer func() { time.Local = local }()
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+
+	db, err := storage.DBConn(&config.DatabaseConfiguration{
+		Driver: "sqlite",
+		Path:   filepath.Join(tempDir(t), "passwall.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	s := storage.New(db, blob.NewMemory(), event.NewLocal())
+	require.Nil(t, s.Logins().Migrate("user1"))
+	for _, title := range []string{"first", "second", "third"} {
+		_, err := s.Logins().Save(&model.Login{Title: title}, "user1")
+		require.Nil(t, err)
+	}
+
+	titles := []string{}
+	argsStr := map[string]string{"order": pagination.Order}
+	for page := 0; page < 5; page++ {
+		logins, err := s.Logins().FindAll(argsStr, map[string]int{"limit": 1, "offset": -1}, "user1")
+		require.Nil(t, err)
+		if len(logins) == 0 {
+			break
+		}
+		require.Len(t, logins, 1)
+		titles = append(titles, logins[0].Title)
+		argsStr["cursor"] = pagination.Encode(logins[0].UpdatedAt, logins[0].ID)
+	}
+	assert.Equal(t, []string{"third", "second", "first"}, titles, "every row should be on one page")
+}
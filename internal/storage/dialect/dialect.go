@@ -0,0 +1,41 @@
+package dialect
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// Postgres is the driver name of PostgreSQL databases
+	Postgres = "postgres"
+	// SQLite is the driver name of SQLite databases
+	SQLite = "sqlite3"
+)
+
+// IsSQLite reports whether db is connected to an SQLite database
+func IsSQLite(db *gorm.DB) bool {
+	return db.Dialect().GetName() == SQLite
+}
+
+// Table returns the name of a user table for the connected database.
+// PostgreSQL keeps every user in a schema of its own (user1.logins),
+// SQLite has no schemas so the schema becomes a prefix (user1_logins).
+func Table(db *gorm.DB, schema, name string) string {
+	if schema == "" {
+		return name
+	}
+
+	if IsSQLite(db) {
+		return schema + "_" + name
+	}
+
+	return schema + "." + name
+}
+
+// Time returns t in the zone the times are stored in, the one of gorm.NowFunc.
+// SQLite keeps times as text with the offset of their zone and compares them
+// as text, so the times of a query must be in the zone of the stored ones.
+func Time(t time.Time) time.Time {
+	return t.In(gorm.NowFunc().Location())
+}
@@ -1,7 +1,14 @@
 package email
 
 import (
+	"strings"
+	"time"
+
 	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
 	"github.com/passwall/passwall-server/model"
 )
 
@@ -15,55 +22,137 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "emails")
+}
+
 // All ...
 func (p *Repository) All(schema string) ([]model.Email, error) {
 	emails := []model.Email{}
-	err := p.db.Table(schema + ".emails").Find(&emails).Error
+	err := p.db.Table(p.table(schema)).Find(&emails).Error
 	return emails, err
 }
 
 // FindAll ...
 func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Email, error) {
 	emails := []model.Email{}
+	query := pagination.Apply(p.filter(argsStr, schema), argsStr, argsInt)
+	err := query.Find(&emails).Error
+	return emails, err
+}
 
-	query := p.db
-	query = query.Table(schema + ".emails")
-	query = query.Limit(argsInt["limit"])
-	if argsInt["limit"] > 0 {
-		// offset can't be declared without a valid limit
-		query = query.Offset(argsInt["offset"])
-	}
+// Count returns the number of entities matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string, schema string) (int, error) {
+	count := 0
+	err := p.filter(argsStr, schema).Model(&model.Email{}).Count(&count).Error
+	return count, err
+}
 
-	query = query.Order(argsStr["order"])
+// filter returns the query of the entities matching the search and the filters
+func (p *Repository) filter(argsStr map[string]string, schema string) *gorm.DB {
+	query := p.db.Table(p.table(schema))
 
 	if argsStr["search"] != "" {
-		query = query.Where("email LIKE ?", "%"+argsStr["search"]+"%")
+		condition, values := search.Condition(argsStr, "title")
+		query = query.Where(condition, values...)
 	}
 
-	err := query.Find(&emails).Error
-	return emails, err
+	if argsStr["favorite"] != "" {
+		query = query.Where("favorite = ?", argsStr["favorite"] == "true")
+	}
+
+	if argsStr["folder"] == "0" {
+		query = query.Where("folder_id IS NULL")
+	} else if argsStr["folder"] != "" {
+		query = query.Where("folder_id = ?", argsStr["folder"])
+	}
+
+	if argsStr["tags"] != "" {
+		tagged := p.db.Table(dialect.Table(p.db, schema, "item_tags")).Select("item_id").
+			Where("item_type = ? AND tag_id IN (?)", model.EmailItem, strings.Split(argsStr["tags"], ",")).
+			SubQuery()
+		query = query.Where("id IN ?", tagged)
+	}
+
+	return query
 }
 
 // FindByID ...
 func (p *Repository) FindByID(id uint, schema string) (*model.Email, error) {
 	email := new(model.Email)
-	err := p.db.Table(schema+".emails").Where(`id = ?`, id).First(&email).Error
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&email).Error
 	return email, err
 }
 
 // Save ...
 func (p *Repository) Save(email *model.Email, schema string) (*model.Email, error) {
-	err := p.db.Table(schema + ".emails").Save(&email).Error
+	err := p.db.Unscoped().Table(p.table(schema)).Save(&email).Error
+	return email, err
+}
+
+// Update ...
+func (p *Repository) Update(email *model.Email, revision uint, schema string) (*model.Email, error) {
+	err := update.Revision(p.db, p.table(schema), email, revision)
+	return email, err
+}
+
+// SaveEncrypted ...
+func (p *Repository) SaveEncrypted(email *model.Email, schema string) (*model.Email, error) {
+	err := update.Encrypted(p.db, p.table(schema), email)
 	return email, err
 }
 
 // Delete ...
 func (p *Repository) Delete(id uint, schema string) error {
-	err := p.db.Table(schema + ".emails").Delete(&model.Email{ID: id}).Error
+	err := p.db.Table(p.table(schema)).Delete(&model.Email{ID: id}).Error
 	return err
 }
 
+// FindAllDeleted ...
+func (p *Repository) FindAllDeleted(schema string) ([]model.Email, error) {
+	emails := []model.Email{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&emails).Error
+	return emails, err
+}
+
+// FindAllChanged ...
+func (p *Repository) FindAllChanged(since time.Time, schema string) ([]model.Email, error) {
+	emails := []model.Email{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("updated_at > ? OR deleted_at > ?", since, since).Order("id").Find(&emails).Error
+	return emails, err
+}
+
+// Restore ...
+func (p *Repository) Restore(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge ...
+func (p *Repository) Purge(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.Email{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedBefore ...
+func (p *Repository) PurgeDeletedBefore(t time.Time, schema string) error {
+	return p.db.Unscoped().Table(p.table(schema)).Where("deleted_at < ?", t).Delete(&model.Email{}).Error
+}
+
 // Migrate ...
 func (p *Repository) Migrate(schema string) error {
-	return p.db.Table(schema + ".emails").AutoMigrate(&model.Email{}).Error
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Email{}).Error
 }
@@ -0,0 +1,58 @@
+// Package event delivers the changes of the items of a user to the
+// devices of the user which are listening.
+package event
+
+import (
+	"github.com/passwall/passwall-server/model"
+)
+
+// Broker publishes the events of a user schema to its subscribers. Local
+// delivers them in process, deployments with more than one instance need a
+// broker shared by the instances, such as Postgres LISTEN/NOTIFY.
+type Broker interface {
+	// Publish sends the event to the subscribers of the schema
+	Publish(schema string, e model.Event) error
+	// Subscribe returns the events of the schema published from now on. The
+	// channel is closed by cancel, or when the subscriber falls behind.
+	Subscribe(schema string) (events <-chan model.Event, cancel func())
+}
+
+// Buffer keeps the events published in a transaction until it is committed,
+// like Postgres does with the notifications of a transaction
+type Buffer struct {
+	broker Broker
+	events []buffered
+}
+
+type buffered struct {
+	schema string
+	event  model.Event
+}
+
+// NewBuffer returns a buffer publishing to broker when it is flushed
+func NewBuffer(broker Broker) *Buffer {
+	return &Buffer{broker: broker}
+}
+
+// Publish keeps the event until Flush
+func (b *Buffer) Publish(schema string, e model.Event) error {
+	b.events = append(b.events, buffered{schema: schema, event: e})
+	return nil
+}
+
+// Subscribe subscribes to the broker, the buffered events are not delivered
+func (b *Buffer) Subscribe(schema string) (<-chan model.Event, func()) {
+	return b.broker.Subscribe(schema)
+}
+
+// Flush publishes the buffered events
+func (b *Buffer) Flush() error {
+	events := b.events
+	b.events = nil
+	for _, e := range events {
+		if err := b.broker.Publish(e.schema, e.event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
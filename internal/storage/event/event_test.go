@@ -0,0 +1,56 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	broker := NewLocal()
+
+	events, cancel := broker.Subscribe("user1")
+	others, cancelOthers := broker.Subscribe("user2")
+	defer cancelOthers()
+
+	e := model.Event{Type: model.ItemChangedEvent, ItemType: model.LoginItem, ItemID: 1}
+	require.Nil(t, broker.Publish("user1", e))
+	assert.Equal(t, e, <-events)
+	assert.Empty(t, others, "events of other schemas shouldn't be delivered")
+
+	cancel()
+	_, ok := <-events
+	assert.False(t, ok, "cancel should close the channel")
+	cancel()
+	require.Nil(t, broker.Publish("user1", e), "publishing without subscribers is fine")
+
+	// a subscriber which falls behind is dropped
+	slow, cancelSlow := broker.Subscribe("user1")
+	defer cancelSlow()
+	for i := 0; i <= subscriberBuffer; i++ {
+		require.Nil(t, broker.Publish("user1", e))
+	}
+	received := 0
+	for range slow {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+}
+
+func TestBuffer(t *testing.T) {
+	broker := NewLocal()
+	events, cancel := broker.Subscribe("user1")
+	defer cancel()
+
+	buffer := NewBuffer(broker)
+	e := model.Event{Type: model.ItemDeletedEvent, ItemType: model.NoteItem, ItemID: 2}
+	require.Nil(t, buffer.Publish("user1", e))
+	assert.Empty(t, events, "buffered events should wait for the flush")
+
+	require.Nil(t, buffer.Flush())
+	assert.Equal(t, e, <-events)
+	require.Nil(t, buffer.Flush())
+	assert.Empty(t, events, "events should be flushed once")
+}
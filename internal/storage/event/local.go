@@ -0,0 +1,69 @@
+package event
+
+import (
+	"sync"
+
+	"github.com/passwall/passwall-server/model"
+)
+
+// subscriberBuffer is the number of events a subscriber can fall behind
+// before it is dropped
+const subscriberBuffer = 64
+
+// Local delivers the events to the subscribers of the same process
+type Local struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan model.Event]struct{}
+}
+
+// NewLocal returns a broker delivering the events in process
+func NewLocal() *Local {
+	return &Local{subscribers: map[string]map[chan model.Event]struct{}{}}
+}
+
+// Publish sends the event to the subscribers of the schema. A subscriber
+// which can't keep up is dropped rather than blocking the publisher, its
+// channel is closed so it can subscribe again and sync what it missed.
+func (l *Local) Publish(schema string, e model.Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for events := range l.subscribers[schema] {
+		select {
+		case events <- e:
+		default:
+			l.remove(schema, events)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns the events of the schema published from now on
+func (l *Local) Subscribe(schema string) (<-chan model.Event, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := make(chan model.Event, subscriberBuffer)
+	if l.subscribers[schema] == nil {
+		l.subscribers[schema] = map[chan model.Event]struct{}{}
+	}
+	l.subscribers[schema][events] = struct{}{}
+
+	return events, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.remove(schema, events)
+	}
+}
+
+// remove drops a subscriber and closes its channel, once
+func (l *Local) remove(schema string, events chan model.Event) {
+	if _, ok := l.subscribers[schema][events]; !ok {
+		return
+	}
+	delete(l.subscribers[schema], events)
+	if len(l.subscribers[schema]) == 0 {
+		delete(l.subscribers, schema)
+	}
+	close(events)
+}
@@ -0,0 +1,52 @@
+package folder
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Repository ...
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "folders")
+}
+
+// All ...
+func (p *Repository) All(schema string) ([]model.Folder, error) {
+	folders := []model.Folder{}
+	err := p.db.Table(p.table(schema)).Order("id").Find(&folders).Error
+	return folders, err
+}
+
+// FindByID ...
+func (p *Repository) FindByID(id uint, schema string) (*model.Folder, error) {
+	folder := new(model.Folder)
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&folder).Error
+	return folder, err
+}
+
+// Save ...
+func (p *Repository) Save(folder *model.Folder, schema string) (*model.Folder, error) {
+	err := p.db.Table(p.table(schema)).Save(&folder).Error
+	return folder, err
+}
+
+// Delete ...
+func (p *Repository) Delete(id uint, schema string) error {
+	err := p.db.Table(p.table(schema)).Delete(&model.Folder{ID: id}).Error
+	return err
+}
+
+// Migrate ...
+func (p *Repository) Migrate(schema string) error {
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Folder{}).Error
+}
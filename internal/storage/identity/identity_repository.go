@@ -0,0 +1,158 @@
+package identity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Repository ...
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "identities")
+}
+
+// All ...
+func (p *Repository) All(schema string) ([]model.Identity, error) {
+	identities := []model.Identity{}
+	err := p.db.Table(p.table(schema)).Find(&identities).Error
+	return identities, err
+}
+
+// FindAll ...
+func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Identity, error) {
+	identities := []model.Identity{}
+	query := pagination.Apply(p.filter(argsStr, schema), argsStr, argsInt)
+	err := query.Find(&identities).Error
+	return identities, err
+}
+
+// Count returns the number of entities matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string, schema string) (int, error) {
+	count := 0
+	err := p.filter(argsStr, schema).Model(&model.Identity{}).Count(&count).Error
+	return count, err
+}
+
+// filter returns the query of the entities matching the search and the filters
+func (p *Repository) filter(argsStr map[string]string, schema string) *gorm.DB {
+	query := p.db.Table(p.table(schema))
+
+	if argsStr["search"] != "" {
+		condition, values := search.Condition(argsStr, "title")
+		query = query.Where(condition, values...)
+	}
+
+	if argsStr["favorite"] != "" {
+		query = query.Where("favorite = ?", argsStr["favorite"] == "true")
+	}
+
+	if argsStr["folder"] == "0" {
+		query = query.Where("folder_id IS NULL")
+	} else if argsStr["folder"] != "" {
+		query = query.Where("folder_id = ?", argsStr["folder"])
+	}
+
+	if argsStr["tags"] != "" {
+		tagged := p.db.Table(dialect.Table(p.db, schema, "item_tags")).Select("item_id").
+			Where("item_type = ? AND tag_id IN (?)", model.IdentityItem, strings.Split(argsStr["tags"], ",")).
+			SubQuery()
+		query = query.Where("id IN ?", tagged)
+	}
+
+	return query
+}
+
+// FindByID ...
+func (p *Repository) FindByID(id uint, schema string) (*model.Identity, error) {
+	identity := new(model.Identity)
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&identity).Error
+	return identity, err
+}
+
+// Save ...
+func (p *Repository) Save(identity *model.Identity, schema string) (*model.Identity, error) {
+	err := p.db.Unscoped().Table(p.table(schema)).Save(&identity).Error
+	return identity, err
+}
+
+// Update ...
+func (p *Repository) Update(identity *model.Identity, revision uint, schema string) (*model.Identity, error) {
+	err := update.Revision(p.db, p.table(schema), identity, revision)
+	return identity, err
+}
+
+// SaveEncrypted ...
+func (p *Repository) SaveEncrypted(identity *model.Identity, schema string) (*model.Identity, error) {
+	err := update.Encrypted(p.db, p.table(schema), identity)
+	return identity, err
+}
+
+// Delete ...
+func (p *Repository) Delete(id uint, schema string) error {
+	err := p.db.Table(p.table(schema)).Delete(&model.Identity{ID: id}).Error
+	return err
+}
+
+// FindAllDeleted ...
+func (p *Repository) FindAllDeleted(schema string) ([]model.Identity, error) {
+	identities := []model.Identity{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&identities).Error
+	return identities, err
+}
+
+// FindAllChanged ...
+func (p *Repository) FindAllChanged(since time.Time, schema string) ([]model.Identity, error) {
+	identities := []model.Identity{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("updated_at > ? OR deleted_at > ?", since, since).Order("id").Find(&identities).Error
+	return identities, err
+}
+
+// Restore ...
+func (p *Repository) Restore(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge ...
+func (p *Repository) Purge(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.Identity{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedBefore ...
+func (p *Repository) PurgeDeletedBefore(t time.Time, schema string) error {
+	return p.db.Unscoped().Table(p.table(schema)).Where("deleted_at < ?", t).Delete(&model.Identity{}).Error
+}
+
+// Migrate ...
+func (p *Repository) Migrate(schema string) error {
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Identity{}).Error
+}
@@ -1,7 +1,14 @@
 package login
 
 import (
+	"strings"
+	"time"
+
 	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
 	"github.com/passwall/passwall-server/model"
 )
 
@@ -15,55 +22,137 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "logins")
+}
+
 // All ...
 func (p *Repository) All(schema string) ([]model.Login, error) {
 	logins := []model.Login{}
-	err := p.db.Table(schema + ".logins").Find(&logins).Error
+	err := p.db.Table(p.table(schema)).Find(&logins).Error
 	return logins, err
 }
 
 // FindAll ...
 func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Login, error) {
 	logins := []model.Login{}
+	query := pagination.Apply(p.filter(argsStr, schema), argsStr, argsInt)
+	err := query.Find(&logins).Error
+	return logins, err
+}
 
-	query := p.db
-	query = query.Table(schema + ".logins")
-	query = query.Limit(argsInt["limit"])
-	if argsInt["limit"] > 0 {
-		// offset can't be declared without a valid limit
-		query = query.Offset(argsInt["offset"])
-	}
+// Count returns the number of entities matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string, schema string) (int, error) {
+	count := 0
+	err := p.filter(argsStr, schema).Model(&model.Login{}).Count(&count).Error
+	return count, err
+}
 
-	query = query.Order(argsStr["order"])
+// filter returns the query of the entities matching the search and the filters
+func (p *Repository) filter(argsStr map[string]string, schema string) *gorm.DB {
+	query := p.db.Table(p.table(schema))
 
 	if argsStr["search"] != "" {
-		query = query.Where("url LIKE ? OR username LIKE ?", "%"+argsStr["search"]+"%", "%"+argsStr["search"]+"%")
+		condition, values := search.Condition(argsStr, "title", "url")
+		query = query.Where(condition, values...)
 	}
 
-	err := query.Find(&logins).Error
-	return logins, err
+	if argsStr["favorite"] != "" {
+		query = query.Where("favorite = ?", argsStr["favorite"] == "true")
+	}
+
+	if argsStr["folder"] == "0" {
+		query = query.Where("folder_id IS NULL")
+	} else if argsStr["folder"] != "" {
+		query = query.Where("folder_id = ?", argsStr["folder"])
+	}
+
+	if argsStr["tags"] != "" {
+		tagged := p.db.Table(dialect.Table(p.db, schema, "item_tags")).Select("item_id").
+			Where("item_type = ? AND tag_id IN (?)", model.LoginItem, strings.Split(argsStr["tags"], ",")).
+			SubQuery()
+		query = query.Where("id IN ?", tagged)
+	}
+
+	return query
 }
 
 // FindByID ...
 func (p *Repository) FindByID(id uint, schema string) (*model.Login, error) {
 	login := new(model.Login)
-	err := p.db.Table(schema+".logins").Where(`id = ?`, id).First(&login).Error
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&login).Error
 	return login, err
 }
 
 // Save ...
 func (p *Repository) Save(login *model.Login, schema string) (*model.Login, error) {
-	err := p.db.Table(schema + ".logins").Save(&login).Error
+	err := p.db.Unscoped().Table(p.table(schema)).Save(&login).Error
+	return login, err
+}
+
+// Update ...
+func (p *Repository) Update(login *model.Login, revision uint, schema string) (*model.Login, error) {
+	err := update.Revision(p.db, p.table(schema), login, revision)
+	return login, err
+}
+
+// SaveEncrypted ...
+func (p *Repository) SaveEncrypted(login *model.Login, schema string) (*model.Login, error) {
+	err := update.Encrypted(p.db, p.table(schema), login)
 	return login, err
 }
 
 // Delete ...
 func (p *Repository) Delete(id uint, schema string) error {
-	err := p.db.Table(schema + ".logins").Delete(&model.Login{ID: id}).Error
+	err := p.db.Table(p.table(schema)).Delete(&model.Login{ID: id}).Error
 	return err
 }
 
+// FindAllDeleted ...
+func (p *Repository) FindAllDeleted(schema string) ([]model.Login, error) {
+	logins := []model.Login{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&logins).Error
+	return logins, err
+}
+
+// FindAllChanged ...
+func (p *Repository) FindAllChanged(since time.Time, schema string) ([]model.Login, error) {
+	logins := []model.Login{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("updated_at > ? OR deleted_at > ?", since, since).Order("id").Find(&logins).Error
+	return logins, err
+}
+
+// Restore ...
+func (p *Repository) Restore(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge ...
+func (p *Repository) Purge(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.Login{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedBefore ...
+func (p *Repository) PurgeDeletedBefore(t time.Time, schema string) error {
+	return p.db.Unscoped().Table(p.table(schema)).Where("deleted_at < ?", t).Delete(&model.Login{}).Error
+}
+
 // Migrate ...
 func (p *Repository) Migrate(schema string) error {
-	return p.db.Table(schema + ".logins").AutoMigrate(&model.Login{}).Error
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Login{}).Error
 }
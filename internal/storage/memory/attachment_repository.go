@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"github.com/passwall/passwall-server/model"
+)
+
+// AttachmentRepository keeps item attachments of every user schema in memory
+type AttachmentRepository struct {
+	s *Store
+	t *table
+}
+
+// FindAll ...
+func (p *AttachmentRepository) FindAll(itemType string, itemID uint, schema string) ([]model.Attachment, error) {
+	return p.FindAllByItems(itemType, []uint{itemID}, schema)
+}
+
+// FindAllByItems ...
+func (p *AttachmentRepository) FindAllByItems(itemType string, itemIDs []uint, schema string) ([]model.Attachment, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	ids := map[uint]bool{}
+	for _, id := range itemIDs {
+		ids[id] = true
+	}
+
+	attachments := []model.Attachment{}
+	for _, row := range p.t.all(schema) {
+		attachment := row.(*model.Attachment)
+		if attachment.ItemType == itemType && ids[attachment.ItemID] {
+			attachments = append(attachments, *attachment)
+		}
+	}
+	return attachments, nil
+}
+
+// FindByID ...
+func (p *AttachmentRepository) FindByID(id uint, schema string) (*model.Attachment, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	attachment := new(model.Attachment)
+	err := p.t.find(schema, id, attachment)
+	return attachment, err
+}
+
+// TotalSize ...
+func (p *AttachmentRepository) TotalSize(schema string) (int64, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	var total int64
+	for _, row := range p.t.all(schema) {
+		total += row.(*model.Attachment).Size
+	}
+	return total, nil
+}
+
+// Save ...
+func (p *AttachmentRepository) Save(attachment *model.Attachment, schema string) (*model.Attachment, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, attachment)
+	return attachment, nil
+}
+
+// Delete ...
+func (p *AttachmentRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// Migrate ...
+func (p *AttachmentRepository) Migrate(schema string) error {
+	return nil
+}
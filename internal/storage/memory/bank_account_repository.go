@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// BankAccountRepository keeps bankAccounts of every user schema in memory
+type BankAccountRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *BankAccountRepository) All(schema string) ([]model.BankAccount, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	bankAccounts := []model.BankAccount{}
+	for _, row := range p.t.all(schema) {
+		bankAccounts = append(bankAccounts, *row.(*model.BankAccount))
+	}
+	return bankAccounts, nil
+}
+
+// FindAll ...
+func (p *BankAccountRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.BankAccount, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	bankAccounts := []model.BankAccount{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.s.tags.tagged(schema, model.BankAccountItem, argsStr["tags"]), "bank_name", "bank_code") {
+		bankAccounts = append(bankAccounts, *row.(*model.BankAccount))
+	}
+	return bankAccounts, nil
+}
+
+// Count ...
+func (p *BankAccountRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.s.tags.tagged(schema, model.BankAccountItem, argsStr["tags"]), "bank_name", "bank_code"), nil
+}
+
+// FindByID ...
+func (p *BankAccountRepository) FindByID(id uint, schema string) (*model.BankAccount, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	bankAccount := new(model.BankAccount)
+	err := p.t.find(schema, id, bankAccount)
+	return bankAccount, err
+}
+
+// Save ...
+func (p *BankAccountRepository) Save(bankAccount *model.BankAccount, schema string) (*model.BankAccount, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, bankAccount)
+	return bankAccount, nil
+}
+
+// Update ...
+func (p *BankAccountRepository) Update(bankAccount *model.BankAccount, revision uint, schema string) (*model.BankAccount, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, bankAccount, revision)
+	return bankAccount, err
+}
+
+// SaveEncrypted ...
+func (p *BankAccountRepository) SaveEncrypted(bankAccount *model.BankAccount, schema string) (*model.BankAccount, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, bankAccount, update.EncryptedFields(bankAccount)...)
+	return bankAccount, err
+}
+
+// Delete ...
+func (p *BankAccountRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *BankAccountRepository) FindAllDeleted(schema string) ([]model.BankAccount, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	bankAccounts := []model.BankAccount{}
+	for _, row := range p.t.deleted(schema) {
+		bankAccounts = append(bankAccounts, *row.(*model.BankAccount))
+	}
+	return bankAccounts, nil
+}
+
+// FindAllChanged ...
+func (p *BankAccountRepository) FindAllChanged(since time.Time, schema string) ([]model.BankAccount, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	bankAccounts := []model.BankAccount{}
+	for _, row := range p.t.changed(schema, since) {
+		bankAccounts = append(bankAccounts, *row.(*model.BankAccount))
+	}
+	return bankAccounts, nil
+}
+
+// Restore ...
+func (p *BankAccountRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *BankAccountRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *BankAccountRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *BankAccountRepository) Migrate(schema string) error {
+	return nil
+}
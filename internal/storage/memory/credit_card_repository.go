@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// CreditCardRepository keeps creditCards of every user schema in memory
+type CreditCardRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *CreditCardRepository) All(schema string) ([]model.CreditCard, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	creditCards := []model.CreditCard{}
+	for _, row := range p.t.all(schema) {
+		creditCards = append(creditCards, *row.(*model.CreditCard))
+	}
+	return creditCards, nil
+}
+
+// FindAll ...
+func (p *CreditCardRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.CreditCard, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	creditCards := []model.CreditCard{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.s.tags.tagged(schema, model.CreditCardItem, argsStr["tags"]), "card_name") {
+		creditCards = append(creditCards, *row.(*model.CreditCard))
+	}
+	return creditCards, nil
+}
+
+// Count ...
+func (p *CreditCardRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.s.tags.tagged(schema, model.CreditCardItem, argsStr["tags"]), "card_name"), nil
+}
+
+// FindByID ...
+func (p *CreditCardRepository) FindByID(id uint, schema string) (*model.CreditCard, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	creditCard := new(model.CreditCard)
+	err := p.t.find(schema, id, creditCard)
+	return creditCard, err
+}
+
+// Save ...
+func (p *CreditCardRepository) Save(creditCard *model.CreditCard, schema string) (*model.CreditCard, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, creditCard)
+	return creditCard, nil
+}
+
+// Update ...
+func (p *CreditCardRepository) Update(creditCard *model.CreditCard, revision uint, schema string) (*model.CreditCard, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, creditCard, revision)
+	return creditCard, err
+}
+
+// SaveEncrypted ...
+func (p *CreditCardRepository) SaveEncrypted(creditCard *model.CreditCard, schema string) (*model.CreditCard, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, creditCard, update.EncryptedFields(creditCard)...)
+	return creditCard, err
+}
+
+// Delete ...
+func (p *CreditCardRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *CreditCardRepository) FindAllDeleted(schema string) ([]model.CreditCard, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	creditCards := []model.CreditCard{}
+	for _, row := range p.t.deleted(schema) {
+		creditCards = append(creditCards, *row.(*model.CreditCard))
+	}
+	return creditCards, nil
+}
+
+// FindAllChanged ...
+func (p *CreditCardRepository) FindAllChanged(since time.Time, schema string) ([]model.CreditCard, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	creditCards := []model.CreditCard{}
+	for _, row := range p.t.changed(schema, since) {
+		creditCards = append(creditCards, *row.(*model.CreditCard))
+	}
+	return creditCards, nil
+}
+
+// Restore ...
+func (p *CreditCardRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *CreditCardRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *CreditCardRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *CreditCardRepository) Migrate(schema string) error {
+	return nil
+}
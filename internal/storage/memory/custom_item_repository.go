@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// CustomItemRepository keeps custom items of every user schema in memory
+type CustomItemRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *CustomItemRepository) All(schema string) ([]model.CustomItem, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	customItems := []model.CustomItem{}
+	for _, row := range p.t.all(schema) {
+		customItems = append(customItems, *row.(*model.CustomItem))
+	}
+	return customItems, nil
+}
+
+// FindAll ...
+func (p *CustomItemRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.CustomItem, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	customItems := []model.CustomItem{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.where(schema, argsStr), "title") {
+		customItems = append(customItems, *row.(*model.CustomItem))
+	}
+	return customItems, nil
+}
+
+// Count ...
+func (p *CustomItemRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.where(schema, argsStr), "title"), nil
+}
+
+// where returns the filter of the tags and the template type in the arguments
+func (p *CustomItemRepository) where(schema string, argsStr map[string]string) func(row interface{}) bool {
+	tagged := p.s.tags.tagged(schema, model.CustomItemItem, argsStr["tags"])
+
+	return func(row interface{}) bool {
+		if tagged != nil && !tagged(row) {
+			return false
+		}
+		return argsStr["type"] == "" || row.(*model.CustomItem).Type == argsStr["type"]
+	}
+}
+
+// FindByID ...
+func (p *CustomItemRepository) FindByID(id uint, schema string) (*model.CustomItem, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	customItem := new(model.CustomItem)
+	err := p.t.find(schema, id, customItem)
+	return customItem, err
+}
+
+// Save ...
+func (p *CustomItemRepository) Save(customItem *model.CustomItem, schema string) (*model.CustomItem, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, customItem)
+	return customItem, nil
+}
+
+// Update ...
+func (p *CustomItemRepository) Update(customItem *model.CustomItem, revision uint, schema string) (*model.CustomItem, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, customItem, revision)
+	return customItem, err
+}
+
+// SaveEncrypted ...
+func (p *CustomItemRepository) SaveEncrypted(customItem *model.CustomItem, schema string) (*model.CustomItem, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, customItem, update.EncryptedFields(customItem)...)
+	return customItem, err
+}
+
+// Delete ...
+func (p *CustomItemRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *CustomItemRepository) FindAllDeleted(schema string) ([]model.CustomItem, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	customItems := []model.CustomItem{}
+	for _, row := range p.t.deleted(schema) {
+		customItems = append(customItems, *row.(*model.CustomItem))
+	}
+	return customItems, nil
+}
+
+// FindAllChanged ...
+func (p *CustomItemRepository) FindAllChanged(since time.Time, schema string) ([]model.CustomItem, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	customItems := []model.CustomItem{}
+	for _, row := range p.t.changed(schema, since) {
+		customItems = append(customItems, *row.(*model.CustomItem))
+	}
+	return customItems, nil
+}
+
+// Restore ...
+func (p *CustomItemRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *CustomItemRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *CustomItemRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *CustomItemRepository) Migrate(schema string) error {
+	return nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// EmailRepository keeps emails of every user schema in memory
+type EmailRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *EmailRepository) All(schema string) ([]model.Email, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	emails := []model.Email{}
+	for _, row := range p.t.all(schema) {
+		emails = append(emails, *row.(*model.Email))
+	}
+	return emails, nil
+}
+
+// FindAll ...
+func (p *EmailRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Email, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	emails := []model.Email{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.s.tags.tagged(schema, model.EmailItem, argsStr["tags"]), "title") {
+		emails = append(emails, *row.(*model.Email))
+	}
+	return emails, nil
+}
+
+// Count ...
+func (p *EmailRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.s.tags.tagged(schema, model.EmailItem, argsStr["tags"]), "title"), nil
+}
+
+// FindByID ...
+func (p *EmailRepository) FindByID(id uint, schema string) (*model.Email, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	email := new(model.Email)
+	err := p.t.find(schema, id, email)
+	return email, err
+}
+
+// Save ...
+func (p *EmailRepository) Save(email *model.Email, schema string) (*model.Email, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, email)
+	return email, nil
+}
+
+// Update ...
+func (p *EmailRepository) Update(email *model.Email, revision uint, schema string) (*model.Email, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, email, revision)
+	return email, err
+}
+
+// SaveEncrypted ...
+func (p *EmailRepository) SaveEncrypted(email *model.Email, schema string) (*model.Email, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, email, update.EncryptedFields(email)...)
+	return email, err
+}
+
+// Delete ...
+func (p *EmailRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *EmailRepository) FindAllDeleted(schema string) ([]model.Email, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	emails := []model.Email{}
+	for _, row := range p.t.deleted(schema) {
+		emails = append(emails, *row.(*model.Email))
+	}
+	return emails, nil
+}
+
+// FindAllChanged ...
+func (p *EmailRepository) FindAllChanged(since time.Time, schema string) ([]model.Email, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	emails := []model.Email{}
+	for _, row := range p.t.changed(schema, since) {
+		emails = append(emails, *row.(*model.Email))
+	}
+	return emails, nil
+}
+
+// Restore ...
+func (p *EmailRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *EmailRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *EmailRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *EmailRepository) Migrate(schema string) error {
+	return nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"github.com/passwall/passwall-server/model"
+)
+
+// FolderRepository keeps folders of every user schema in memory
+type FolderRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *FolderRepository) All(schema string) ([]model.Folder, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	folders := []model.Folder{}
+	for _, row := range p.t.all(schema) {
+		folders = append(folders, *row.(*model.Folder))
+	}
+	return folders, nil
+}
+
+// FindByID ...
+func (p *FolderRepository) FindByID(id uint, schema string) (*model.Folder, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	folder := new(model.Folder)
+	err := p.t.find(schema, id, folder)
+	return folder, err
+}
+
+// Save ...
+func (p *FolderRepository) Save(folder *model.Folder, schema string) (*model.Folder, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, folder)
+	return folder, nil
+}
+
+// Delete ...
+func (p *FolderRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// Migrate ...
+func (p *FolderRepository) Migrate(schema string) error {
+	return nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// IdentityRepository keeps identities of every user schema in memory
+type IdentityRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *IdentityRepository) All(schema string) ([]model.Identity, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	identities := []model.Identity{}
+	for _, row := range p.t.all(schema) {
+		identities = append(identities, *row.(*model.Identity))
+	}
+	return identities, nil
+}
+
+// FindAll ...
+func (p *IdentityRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Identity, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	identities := []model.Identity{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.s.tags.tagged(schema, model.IdentityItem, argsStr["tags"]), "title") {
+		identities = append(identities, *row.(*model.Identity))
+	}
+	return identities, nil
+}
+
+// Count ...
+func (p *IdentityRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.s.tags.tagged(schema, model.IdentityItem, argsStr["tags"]), "title"), nil
+}
+
+// FindByID ...
+func (p *IdentityRepository) FindByID(id uint, schema string) (*model.Identity, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	identity := new(model.Identity)
+	err := p.t.find(schema, id, identity)
+	return identity, err
+}
+
+// Save ...
+func (p *IdentityRepository) Save(identity *model.Identity, schema string) (*model.Identity, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, identity)
+	return identity, nil
+}
+
+// Update ...
+func (p *IdentityRepository) Update(identity *model.Identity, revision uint, schema string) (*model.Identity, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, identity, revision)
+	return identity, err
+}
+
+// SaveEncrypted ...
+func (p *IdentityRepository) SaveEncrypted(identity *model.Identity, schema string) (*model.Identity, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, identity, update.EncryptedFields(identity)...)
+	return identity, err
+}
+
+// Delete ...
+func (p *IdentityRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *IdentityRepository) FindAllDeleted(schema string) ([]model.Identity, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	identities := []model.Identity{}
+	for _, row := range p.t.deleted(schema) {
+		identities = append(identities, *row.(*model.Identity))
+	}
+	return identities, nil
+}
+
+// FindAllChanged ...
+func (p *IdentityRepository) FindAllChanged(since time.Time, schema string) ([]model.Identity, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	identities := []model.Identity{}
+	for _, row := range p.t.changed(schema, since) {
+		identities = append(identities, *row.(*model.Identity))
+	}
+	return identities, nil
+}
+
+// Restore ...
+func (p *IdentityRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *IdentityRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *IdentityRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *IdentityRepository) Migrate(schema string) error {
+	return nil
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// LicenseKeyRepository keeps license keys of every user schema in memory
+type LicenseKeyRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *LicenseKeyRepository) All(schema string) ([]model.LicenseKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	licenseKeys := []model.LicenseKey{}
+	for _, row := range p.t.all(schema) {
+		licenseKeys = append(licenseKeys, *row.(*model.LicenseKey))
+	}
+	return licenseKeys, nil
+}
+
+// FindAll ...
+func (p *LicenseKeyRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.LicenseKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	licenseKeys := []model.LicenseKey{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.where(schema, argsStr), "product") {
+		licenseKeys = append(licenseKeys, *row.(*model.LicenseKey))
+	}
+	return licenseKeys, nil
+}
+
+// Count ...
+func (p *LicenseKeyRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.where(schema, argsStr), "product"), nil
+}
+
+// where returns the filter of the tags and the expiry dates in the arguments
+func (p *LicenseKeyRepository) where(schema string, argsStr map[string]string) func(row interface{}) bool {
+	tagged := p.s.tags.tagged(schema, model.LicenseKeyItem, argsStr["tags"])
+	expiresAfter, afterErr := time.Parse(time.RFC3339, argsStr["expires_after"])
+	expiresBefore, beforeErr := time.Parse(time.RFC3339, argsStr["expires_before"])
+
+	return func(row interface{}) bool {
+		if tagged != nil && !tagged(row) {
+			return false
+		}
+		expiresAt := row.(*model.LicenseKey).ExpiresAt
+		if afterErr == nil && (expiresAt == nil || expiresAt.Before(expiresAfter)) {
+			return false
+		}
+		if beforeErr == nil && (expiresAt == nil || !expiresAt.Before(expiresBefore)) {
+			return false
+		}
+		return true
+	}
+}
+
+// FindByID ...
+func (p *LicenseKeyRepository) FindByID(id uint, schema string) (*model.LicenseKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	licenseKey := new(model.LicenseKey)
+	err := p.t.find(schema, id, licenseKey)
+	return licenseKey, err
+}
+
+// Save ...
+func (p *LicenseKeyRepository) Save(licenseKey *model.LicenseKey, schema string) (*model.LicenseKey, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, licenseKey)
+	return licenseKey, nil
+}
+
+// Update ...
+func (p *LicenseKeyRepository) Update(licenseKey *model.LicenseKey, revision uint, schema string) (*model.LicenseKey, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, licenseKey, revision)
+	return licenseKey, err
+}
+
+// SaveEncrypted ...
+func (p *LicenseKeyRepository) SaveEncrypted(licenseKey *model.LicenseKey, schema string) (*model.LicenseKey, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, licenseKey, update.EncryptedFields(licenseKey)...)
+	return licenseKey, err
+}
+
+// Delete ...
+func (p *LicenseKeyRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *LicenseKeyRepository) FindAllDeleted(schema string) ([]model.LicenseKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	licenseKeys := []model.LicenseKey{}
+	for _, row := range p.t.deleted(schema) {
+		licenseKeys = append(licenseKeys, *row.(*model.LicenseKey))
+	}
+	return licenseKeys, nil
+}
+
+// FindAllChanged ...
+func (p *LicenseKeyRepository) FindAllChanged(since time.Time, schema string) ([]model.LicenseKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	licenseKeys := []model.LicenseKey{}
+	for _, row := range p.t.changed(schema, since) {
+		licenseKeys = append(licenseKeys, *row.(*model.LicenseKey))
+	}
+	return licenseKeys, nil
+}
+
+// Restore ...
+func (p *LicenseKeyRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *LicenseKeyRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *LicenseKeyRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *LicenseKeyRepository) Migrate(schema string) error {
+	return nil
+}
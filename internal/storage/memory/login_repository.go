@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// LoginRepository keeps logins of every user schema in memory
+type LoginRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *LoginRepository) All(schema string) ([]model.Login, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	logins := []model.Login{}
+	for _, row := range p.t.all(schema) {
+		logins = append(logins, *row.(*model.Login))
+	}
+	return logins, nil
+}
+
+// FindAll ...
+func (p *LoginRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Login, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	logins := []model.Login{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.s.tags.tagged(schema, model.LoginItem, argsStr["tags"]), "title", "url") {
+		logins = append(logins, *row.(*model.Login))
+	}
+	return logins, nil
+}
+
+// Count ...
+func (p *LoginRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.s.tags.tagged(schema, model.LoginItem, argsStr["tags"]), "title", "url"), nil
+}
+
+// FindByID ...
+func (p *LoginRepository) FindByID(id uint, schema string) (*model.Login, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	login := new(model.Login)
+	err := p.t.find(schema, id, login)
+	return login, err
+}
+
+// Save ...
+func (p *LoginRepository) Save(login *model.Login, schema string) (*model.Login, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, login)
+	return login, nil
+}
+
+// Update ...
+func (p *LoginRepository) Update(login *model.Login, revision uint, schema string) (*model.Login, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, login, revision)
+	return login, err
+}
+
+// SaveEncrypted ...
+func (p *LoginRepository) SaveEncrypted(login *model.Login, schema string) (*model.Login, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, login, update.EncryptedFields(login)...)
+	return login, err
+}
+
+// Delete ...
+func (p *LoginRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *LoginRepository) FindAllDeleted(schema string) ([]model.Login, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	logins := []model.Login{}
+	for _, row := range p.t.deleted(schema) {
+		logins = append(logins, *row.(*model.Login))
+	}
+	return logins, nil
+}
+
+// FindAllChanged ...
+func (p *LoginRepository) FindAllChanged(since time.Time, schema string) ([]model.Login, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	logins := []model.Login{}
+	for _, row := range p.t.changed(schema, since) {
+		logins = append(logins, *row.(*model.Login))
+	}
+	return logins, nil
+}
+
+// Restore ...
+func (p *LoginRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *LoginRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *LoginRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *LoginRepository) Migrate(schema string) error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/migration"
+	"github.com/passwall/passwall-server/model"
+)
+
+// MigrationRepository has no migrations to run, in-memory tables have no
+// schema. It still holds the migration lock for the callers.
+type MigrationRepository struct {
+	mu   sync.Mutex
+	lock *model.MigrationLock
+}
+
+// Up ...
+func (p *MigrationRepository) Up(schema string) ([]model.Migration, error) {
+	return []model.Migration{}, nil
+}
+
+// Down ...
+func (p *MigrationRepository) Down(schema string, steps int) ([]model.Migration, error) {
+	return []model.Migration{}, nil
+}
+
+// Status ...
+func (p *MigrationRepository) Status(schema string) ([]model.MigrationStatus, error) {
+	return []model.MigrationStatus{}, nil
+}
+
+// Lock ...
+func (p *MigrationRepository) Lock(owner string, ttl time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lock != nil && p.lock.LockedAt.After(time.Now().Add(-ttl)) {
+		return fmt.Errorf("migrations are locked by %s since %s", p.lock.Owner, p.lock.LockedAt.Format(time.RFC3339))
+	}
+
+	p.lock = &model.MigrationLock{ID: 1, Owner: owner, LockedAt: time.Now()}
+	return nil
+}
+
+// Refresh ...
+func (p *MigrationRepository) Refresh(owner string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lock == nil || p.lock.Owner != owner {
+		return migration.ErrLockLost
+	}
+	p.lock.LockedAt = time.Now()
+	return nil
+}
+
+// Unlock ...
+func (p *MigrationRepository) Unlock(owner string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lock != nil && p.lock.Owner == owner {
+		p.lock = nil
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// NoteRepository keeps notes of every user schema in memory
+type NoteRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *NoteRepository) All(schema string) ([]model.Note, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	notes := []model.Note{}
+	for _, row := range p.t.all(schema) {
+		notes = append(notes, *row.(*model.Note))
+	}
+	return notes, nil
+}
+
+// FindAll ...
+func (p *NoteRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Note, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	notes := []model.Note{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.s.tags.tagged(schema, model.NoteItem, argsStr["tags"]), "title") {
+		notes = append(notes, *row.(*model.Note))
+	}
+	return notes, nil
+}
+
+// Count ...
+func (p *NoteRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.s.tags.tagged(schema, model.NoteItem, argsStr["tags"]), "title"), nil
+}
+
+// FindByID ...
+func (p *NoteRepository) FindByID(id uint, schema string) (*model.Note, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	note := new(model.Note)
+	err := p.t.find(schema, id, note)
+	return note, err
+}
+
+// Save ...
+func (p *NoteRepository) Save(note *model.Note, schema string) (*model.Note, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, note)
+	return note, nil
+}
+
+// Update ...
+func (p *NoteRepository) Update(note *model.Note, revision uint, schema string) (*model.Note, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, note, revision)
+	return note, err
+}
+
+// SaveEncrypted ...
+func (p *NoteRepository) SaveEncrypted(note *model.Note, schema string) (*model.Note, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, note, update.EncryptedFields(note)...)
+	return note, err
+}
+
+// Delete ...
+func (p *NoteRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *NoteRepository) FindAllDeleted(schema string) ([]model.Note, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	notes := []model.Note{}
+	for _, row := range p.t.deleted(schema) {
+		notes = append(notes, *row.(*model.Note))
+	}
+	return notes, nil
+}
+
+// FindAllChanged ...
+func (p *NoteRepository) FindAllChanged(since time.Time, schema string) ([]model.Note, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	notes := []model.Note{}
+	for _, row := range p.t.changed(schema, since) {
+		notes = append(notes, *row.(*model.Note))
+	}
+	return notes, nil
+}
+
+// Restore ...
+func (p *NoteRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *NoteRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *NoteRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *NoteRepository) Migrate(schema string) error {
+	return nil
+}
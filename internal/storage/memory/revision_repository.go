@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"github.com/passwall/passwall-server/model"
+)
+
+// RevisionRepository keeps item revisions of every user schema in memory
+type RevisionRepository struct {
+	s *Store
+	t *table
+}
+
+// FindAll ...
+func (p *RevisionRepository) FindAll(itemType string, itemID uint, schema string) ([]model.Revision, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.findAll(itemType, itemID, schema), nil
+}
+
+// findAll returns the revisions of an item, newest first
+func (p *RevisionRepository) findAll(itemType string, itemID uint, schema string) []model.Revision {
+	rows := p.t.all(schema)
+	revisions := []model.Revision{}
+	for i := len(rows) - 1; i >= 0; i-- {
+		revision := rows[i].(*model.Revision)
+		if revision.ItemType == itemType && revision.ItemID == itemID {
+			revisions = append(revisions, *revision)
+		}
+	}
+	return revisions
+}
+
+// FindByID ...
+func (p *RevisionRepository) FindByID(id uint, schema string) (*model.Revision, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	revision := new(model.Revision)
+	err := p.t.find(schema, id, revision)
+	return revision, err
+}
+
+// Save ...
+func (p *RevisionRepository) Save(revision *model.Revision, schema string) (*model.Revision, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, revision)
+	return revision, nil
+}
+
+// Prune ...
+func (p *RevisionRepository) Prune(itemType string, itemID uint, keep int, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	revisions := p.findAll(itemType, itemID, schema)
+	for i := keep; i < len(revisions); i++ {
+		p.t.delete(schema, revisions[i].ID)
+	}
+	return nil
+}
+
+// DeleteAll ...
+func (p *RevisionRepository) DeleteAll(itemType string, itemIDs []uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	for _, itemID := range itemIDs {
+		for _, revision := range p.findAll(itemType, itemID, schema) {
+			p.t.delete(schema, revision.ID)
+		}
+	}
+	return nil
+}
+
+// Migrate ...
+func (p *RevisionRepository) Migrate(schema string) error {
+	return nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// ServerRepository keeps servers of every user schema in memory
+type ServerRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *ServerRepository) All(schema string) ([]model.Server, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	servers := []model.Server{}
+	for _, row := range p.t.all(schema) {
+		servers = append(servers, *row.(*model.Server))
+	}
+	return servers, nil
+}
+
+// FindAll ...
+func (p *ServerRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Server, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	servers := []model.Server{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.s.tags.tagged(schema, model.ServerItem, argsStr["tags"]), "title", "url") {
+		servers = append(servers, *row.(*model.Server))
+	}
+	return servers, nil
+}
+
+// Count ...
+func (p *ServerRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.s.tags.tagged(schema, model.ServerItem, argsStr["tags"]), "title", "url"), nil
+}
+
+// FindByID ...
+func (p *ServerRepository) FindByID(id uint, schema string) (*model.Server, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	server := new(model.Server)
+	err := p.t.find(schema, id, server)
+	return server, err
+}
+
+// Save ...
+func (p *ServerRepository) Save(server *model.Server, schema string) (*model.Server, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, server)
+	return server, nil
+}
+
+// Update ...
+func (p *ServerRepository) Update(server *model.Server, revision uint, schema string) (*model.Server, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, server, revision)
+	return server, err
+}
+
+// SaveEncrypted ...
+func (p *ServerRepository) SaveEncrypted(server *model.Server, schema string) (*model.Server, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, server, update.EncryptedFields(server)...)
+	return server, err
+}
+
+// Delete ...
+func (p *ServerRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *ServerRepository) FindAllDeleted(schema string) ([]model.Server, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	servers := []model.Server{}
+	for _, row := range p.t.deleted(schema) {
+		servers = append(servers, *row.(*model.Server))
+	}
+	return servers, nil
+}
+
+// FindAllChanged ...
+func (p *ServerRepository) FindAllChanged(since time.Time, schema string) ([]model.Server, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	servers := []model.Server{}
+	for _, row := range p.t.changed(schema, since) {
+		servers = append(servers, *row.(*model.Server))
+	}
+	return servers, nil
+}
+
+// Restore ...
+func (p *ServerRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *ServerRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *ServerRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *ServerRepository) Migrate(schema string) error {
+	return nil
+}
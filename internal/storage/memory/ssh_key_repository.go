@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// SSHKeyRepository keeps SSH keys of every user schema in memory
+type SSHKeyRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *SSHKeyRepository) All(schema string) ([]model.SSHKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	sshKeys := []model.SSHKey{}
+	for _, row := range p.t.all(schema) {
+		sshKeys = append(sshKeys, *row.(*model.SSHKey))
+	}
+	return sshKeys, nil
+}
+
+// FindAll ...
+func (p *SSHKeyRepository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.SSHKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	sshKeys := []model.SSHKey{}
+	for _, row := range p.t.queryWhere(schema, argsStr, argsInt, p.s.tags.tagged(schema, model.SSHKeyItem, argsStr["tags"]), "title", "fingerprint") {
+		sshKeys = append(sshKeys, *row.(*model.SSHKey))
+	}
+	return sshKeys, nil
+}
+
+// Count ...
+func (p *SSHKeyRepository) Count(argsStr map[string]string, schema string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count(schema, argsStr, p.s.tags.tagged(schema, model.SSHKeyItem, argsStr["tags"]), "title", "fingerprint"), nil
+}
+
+// FindByID ...
+func (p *SSHKeyRepository) FindByID(id uint, schema string) (*model.SSHKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	sshKey := new(model.SSHKey)
+	err := p.t.find(schema, id, sshKey)
+	return sshKey, err
+}
+
+// Save ...
+func (p *SSHKeyRepository) Save(sshKey *model.SSHKey, schema string) (*model.SSHKey, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, sshKey)
+	return sshKey, nil
+}
+
+// Update ...
+func (p *SSHKeyRepository) Update(sshKey *model.SSHKey, revision uint, schema string) (*model.SSHKey, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveRevision(schema, sshKey, revision)
+	return sshKey, err
+}
+
+// SaveEncrypted ...
+func (p *SSHKeyRepository) SaveEncrypted(sshKey *model.SSHKey, schema string) (*model.SSHKey, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	err := p.t.saveFields(schema, sshKey, update.EncryptedFields(sshKey)...)
+	return sshKey, err
+}
+
+// Delete ...
+func (p *SSHKeyRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindAllDeleted ...
+func (p *SSHKeyRepository) FindAllDeleted(schema string) ([]model.SSHKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	sshKeys := []model.SSHKey{}
+	for _, row := range p.t.deleted(schema) {
+		sshKeys = append(sshKeys, *row.(*model.SSHKey))
+	}
+	return sshKeys, nil
+}
+
+// FindAllChanged ...
+func (p *SSHKeyRepository) FindAllChanged(since time.Time, schema string) ([]model.SSHKey, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	sshKeys := []model.SSHKey{}
+	for _, row := range p.t.changed(schema, since) {
+		sshKeys = append(sshKeys, *row.(*model.SSHKey))
+	}
+	return sshKeys, nil
+}
+
+// Restore ...
+func (p *SSHKeyRepository) Restore(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.restore(schema, id)
+}
+
+// Purge ...
+func (p *SSHKeyRepository) Purge(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	return p.t.purge(schema, id)
+}
+
+// PurgeDeletedBefore ...
+func (p *SSHKeyRepository) PurgeDeletedBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.purgeDeletedBefore(schema, t)
+	return nil
+}
+
+// Migrate ...
+func (p *SSHKeyRepository) Migrate(schema string) error {
+	return nil
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/blob"
+	"github.com/passwall/passwall-server/internal/storage/event"
+)
+
+// Store is an in-memory storage.Store. It needs no database server which
+// makes it a good fit for tests, data is lost when the process exits.
+type Store struct {
+	mu     sync.RWMutex
+	txMu   sync.Mutex
+	tables map[string]*table
+
+	logins        *LoginRepository
+	cards         *CreditCardRepository
+	accounts      *BankAccountRepository
+	notes         *NoteRepository
+	emails        *EmailRepository
+	tokens        *TokenRepository
+	users         *UserRepository
+	servers       *ServerRepository
+	identities    *IdentityRepository
+	licenseKeys   *LicenseKeyRepository
+	customItems   *CustomItemRepository
+	sshKeys       *SSHKeyRepository
+	templates     *TemplateRepository
+	attachments   *AttachmentRepository
+	revisions     *RevisionRepository
+	tombstones    *TombstoneRepository
+	folders       *FolderRepository
+	tags          *TagRepository
+	subscriptions *SubscriptionRepository
+	migrations    *MigrationRepository
+	blobs         *blob.Memory
+	events        event.Broker
+}
+
+// New creates an empty in-memory store
+func New() *Store {
+	s := &Store{tables: map[string]*table{}}
+
+	s.logins = &LoginRepository{s: s, t: s.table("logins")}
+	s.cards = &CreditCardRepository{s: s, t: s.table("credit_cards")}
+	s.accounts = &BankAccountRepository{s: s, t: s.table("bank_accounts")}
+	s.notes = &NoteRepository{s: s, t: s.table("notes")}
+	s.emails = &EmailRepository{s: s, t: s.table("emails")}
+	s.tokens = &TokenRepository{s: s, t: s.table("tokens")}
+	s.users = &UserRepository{s: s, t: s.table("users")}
+	s.servers = &ServerRepository{s: s, t: s.table("servers")}
+	s.identities = &IdentityRepository{s: s, t: s.table("identities")}
+	s.licenseKeys = &LicenseKeyRepository{s: s, t: s.table("license_keys")}
+	s.customItems = &CustomItemRepository{s: s, t: s.table("custom_items")}
+	s.sshKeys = &SSHKeyRepository{s: s, t: s.table("ssh_keys")}
+	s.templates = &TemplateRepository{s: s, t: s.table("templates")}
+	s.attachments = &AttachmentRepository{s: s, t: s.table("attachments")}
+	s.revisions = &RevisionRepository{s: s, t: s.table("revisions")}
+	s.tombstones = &TombstoneRepository{s: s, t: s.table("tombstones")}
+	s.folders = &FolderRepository{s: s, t: s.table("folders")}
+	s.tags = &TagRepository{s: s, t: s.table("tags"), items: s.table("item_tags")}
+	s.subscriptions = &SubscriptionRepository{s: s, t: s.table("subscriptions")}
+	s.migrations = &MigrationRepository{}
+	s.blobs = blob.NewMemory()
+	s.events = event.NewLocal()
+
+	return s
+}
+
+func (s *Store) table(name string) *table {
+	t := newTable()
+	s.tables[name] = t
+	return t
+}
+
+// dropSchema removes the data of a user schema from every table
+func (s *Store) dropSchema(schema string) {
+	for _, t := range s.tables {
+		t.drop(schema)
+	}
+}
+
+// Logins returns the LoginRepository.
+func (s *Store) Logins() storage.LoginRepository {
+	return s.logins
+}
+
+// CreditCards returns the CreditCardRepository.
+func (s *Store) CreditCards() storage.CreditCardRepository {
+	return s.cards
+}
+
+// BankAccounts returns the BankAccountRepository.
+func (s *Store) BankAccounts() storage.BankAccountRepository {
+	return s.accounts
+}
+
+// Notes returns the NoteRepository.
+func (s *Store) Notes() storage.NoteRepository {
+	return s.notes
+}
+
+// Emails returns the EmailRepository.
+func (s *Store) Emails() storage.EmailRepository {
+	return s.emails
+}
+
+// Tokens returns the TokenRepository.
+func (s *Store) Tokens() storage.TokenRepository {
+	return s.tokens
+}
+
+// Users returns the UserRepository.
+func (s *Store) Users() storage.UserRepository {
+	return s.users
+}
+
+// Servers returns the ServerRepository.
+func (s *Store) Servers() storage.ServerRepository {
+	return s.servers
+}
+
+// Identities returns the IdentityRepository.
+func (s *Store) Identities() storage.IdentityRepository {
+	return s.identities
+}
+
+// LicenseKeys returns the LicenseKeyRepository.
+func (s *Store) LicenseKeys() storage.LicenseKeyRepository {
+	return s.licenseKeys
+}
+
+// CustomItems returns the CustomItemRepository.
+func (s *Store) CustomItems() storage.CustomItemRepository {
+	return s.customItems
+}
+
+// SSHKeys returns the SSHKeyRepository.
+func (s *Store) SSHKeys() storage.SSHKeyRepository {
+	return s.sshKeys
+}
+
+// Templates returns the TemplateRepository.
+func (s *Store) Templates() storage.TemplateRepository {
+	return s.templates
+}
+
+// Attachments returns the AttachmentRepository.
+func (s *Store) Attachments() storage.AttachmentRepository {
+	return s.attachments
+}
+
+// Revisions returns the RevisionRepository.
+func (s *Store) Revisions() storage.RevisionRepository {
+	return s.revisions
+}
+
+// Tombstones returns the TombstoneRepository.
+func (s *Store) Tombstones() storage.TombstoneRepository {
+	return s.tombstones
+}
+
+// Folders returns the FolderRepository.
+func (s *Store) Folders() storage.FolderRepository {
+	return s.folders
+}
+
+// Tags returns the TagRepository.
+func (s *Store) Tags() storage.TagRepository {
+	return s.tags
+}
+
+// Subscriptions returns the SubscriptionRepository.
+func (s *Store) Subscriptions() storage.SubscriptionRepository {
+	return s.subscriptions
+}
+
+// Migrations returns the MigrationRepository.
+func (s *Store) Migrations() storage.MigrationRepository {
+	return s.migrations
+}
+
+// Blobs returns the in-memory blob store.
+func (s *Store) Blobs() blob.Store {
+	return s.blobs
+}
+
+// Events returns the broker of the item events.
+func (s *Store) Events() event.Broker {
+	return s.events
+}
+
+// Transaction runs fn with the store and rolls back every table when fn
+// returns an error. Transactions run one at a time, changes made by other
+// callers while a transaction runs are rolled back with it.
+func (s *Store) Transaction(fn func(tx storage.Store) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.RLock()
+	snapshot := map[string]*table{}
+	for name, t := range s.tables {
+		snapshot[name] = t.copy()
+	}
+	s.mu.RUnlock()
+
+	events := event.NewBuffer(s.events)
+	if err := fn(tx{s, events}); err != nil {
+		s.mu.Lock()
+		for name, t := range s.tables {
+			t.rollback(snapshot[name])
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return events.Flush()
+}
+
+// tx is the store in a transaction, transactions started on it join the running one
+type tx struct {
+	*Store
+	events *event.Buffer
+}
+
+// Events returns the events of the transaction, delivered once it is committed
+func (t tx) Events() event.Broker {
+	return t.events
+}
+
+// Transaction runs fn in the running transaction
+func (t tx) Transaction(fn func(tx storage.Store) error) error {
+	return fn(t)
+}
+
+// Ping always succeeds, there is no connection to check
+func (s *Store) Ping() error {
+	return nil
+}
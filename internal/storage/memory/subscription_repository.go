@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"github.com/passwall/passwall-server/model"
+)
+
+// SubscriptionRepository keeps subscriptions in memory
+type SubscriptionRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *SubscriptionRepository) All() ([]model.Subscription, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	subscriptions := []model.Subscription{}
+	for _, row := range p.t.all("") {
+		subscriptions = append(subscriptions, *row.(*model.Subscription))
+	}
+	return subscriptions, nil
+}
+
+// FindAll ...
+func (p *SubscriptionRepository) FindAll(argsStr map[string]string, argsInt map[string]int) ([]model.Subscription, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	subscriptions := []model.Subscription{}
+	for _, row := range p.t.query("", argsStr, argsInt, "email", "status") {
+		subscriptions = append(subscriptions, *row.(*model.Subscription))
+	}
+	return subscriptions, nil
+}
+
+// Count ...
+func (p *SubscriptionRepository) Count(argsStr map[string]string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count("", argsStr, nil, "email", "status"), nil
+}
+
+// FindByID ...
+func (p *SubscriptionRepository) FindByID(id uint) (*model.Subscription, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	subscription := new(model.Subscription)
+	err := p.t.find("", id, subscription)
+	return subscription, err
+}
+
+// FindByEmail ...
+func (p *SubscriptionRepository) FindByEmail(email string) (*model.Subscription, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	subscription := new(model.Subscription)
+	err := p.t.first("", subscription, func(row interface{}) bool {
+		return row.(*model.Subscription).Email == email
+	})
+	return subscription, err
+}
+
+// FindBySubscriptionID ...
+func (p *SubscriptionRepository) FindBySubscriptionID(id uint) (*model.Subscription, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	subscription := new(model.Subscription)
+	err := p.t.first("", subscription, func(row interface{}) bool {
+		return row.(*model.Subscription).SubscriptionID == int(id)
+	})
+	return subscription, err
+}
+
+// Save ...
+func (p *SubscriptionRepository) Save(subscription *model.Subscription) (*model.Subscription, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save("", subscription)
+	return subscription, nil
+}
+
+// Delete ...
+func (p *SubscriptionRepository) Delete(id uint) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete("", id)
+	return nil
+}
+
+// Migrate ...
+func (p *SubscriptionRepository) Migrate() error {
+	return nil
+}
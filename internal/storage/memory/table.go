@@ -0,0 +1,471 @@
+package memory
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
+)
+
+// table keeps the rows of a single table for every schema. System tables
+// (users, tokens, subscriptions) live in the empty schema.
+// Rows are pointers to model structs and are copied on the way in and out
+// so callers can never change stored data without calling save.
+type table struct {
+	nextID map[string]uint
+	rows   map[string]map[uint]interface{}
+}
+
+func newTable() *table {
+	return &table{
+		nextID: map[string]uint{},
+		rows:   map[string]map[uint]interface{}{},
+	}
+}
+
+// save inserts the row when its ID is zero and updates it otherwise,
+// CreatedAt and UpdatedAt are maintained like gorm does. Items start at
+// revision one like the default of their column.
+func (t *table) save(schema string, row interface{}) {
+	now := time.Now()
+	if t.rows[schema] == nil {
+		t.rows[schema] = map[uint]interface{}{}
+	}
+
+	id := rowID(row)
+	if id == 0 {
+		t.nextID[schema]++
+		id = t.nextID[schema]
+		setRowID(row, id)
+	} else if id > t.nextID[schema] {
+		t.nextID[schema] = id
+	}
+
+	if createdAt := field(row, "CreatedAt"); createdAt.IsValid() && createdAt.Interface().(time.Time).IsZero() {
+		createdAt.Set(reflect.ValueOf(now))
+	}
+	if updatedAt := field(row, "UpdatedAt"); updatedAt.IsValid() {
+		updatedAt.Set(reflect.ValueOf(now))
+	}
+	if revision := field(row, "Revision"); revision.IsValid() && revision.Uint() == 0 {
+		revision.SetUint(1)
+	}
+
+	stored := clone(row)
+	for i := 0; i < reflect.ValueOf(stored).Elem().NumField(); i++ {
+		// like a database, don't keep the fields without a column
+		if reflect.TypeOf(stored).Elem().Field(i).Tag.Get("gorm") == "-" {
+			value := reflect.ValueOf(stored).Elem().Field(i)
+			value.Set(reflect.Zero(value.Type()))
+		}
+	}
+	t.rows[schema][id] = stored
+}
+
+// saveRevision updates the row unless another update moved it past revision,
+// a revision of 0 updates it whatever its revision. Deleted rows are updated
+// too. The row moves to its next revision.
+func (t *table) saveRevision(schema string, row interface{}, revision uint) error {
+	stored, ok := t.rows[schema][rowID(row)]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	current := field(stored, "Revision").Uint()
+	if revision != 0 && current != uint64(revision) {
+		return update.ErrRevisionChanged
+	}
+	field(row, "Revision").SetUint(current + 1)
+	t.save(schema, row)
+	return nil
+}
+
+// saveFields copies the named fields of the row into the stored row, which
+// keeps its other fields, update time and revision. Deleted rows are updated too.
+func (t *table) saveFields(schema string, row interface{}, names ...string) error {
+	stored, ok := t.rows[schema][rowID(row)]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	copied := clone(row)
+	for _, name := range names {
+		field(stored, name).Set(field(copied, name))
+	}
+	return nil
+}
+
+// find copies the row with the given id into dst
+func (t *table) find(schema string, id uint, dst interface{}) error {
+	row, ok := t.rows[schema][id]
+	if !ok || isDeleted(row) {
+		return gorm.ErrRecordNotFound
+	}
+	reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(row).Elem())
+	return nil
+}
+
+// first copies the first row matching fn into dst
+func (t *table) first(schema string, dst interface{}, fn func(row interface{}) bool) error {
+	for _, row := range t.all(schema) {
+		if fn(row) {
+			reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(row).Elem())
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// all returns copies of the rows which are not deleted ordered by id
+func (t *table) all(schema string) []interface{} {
+	rows := []interface{}{}
+	for _, row := range t.rows[schema] {
+		if !isDeleted(row) {
+			rows = append(rows, clone(row))
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rowID(rows[i]) < rowID(rows[j])
+	})
+	return rows
+}
+
+// query mimics the FindAll queries of the database repositories. Rows are
+// searched in the given columns and filtered, then ordered and paginated.
+func (t *table) query(schema string, argsStr map[string]string, argsInt map[string]int, columns ...string) []interface{} {
+	return t.queryWhere(schema, argsStr, argsInt, nil, columns...)
+}
+
+// queryWhere is query with an additional filter, rows not matching
+// fn are left out before the pagination. A nil fn matches every row.
+func (t *table) queryWhere(schema string, argsStr map[string]string, argsInt map[string]int, fn func(row interface{}) bool, columns ...string) []interface{} {
+	rows := t.match(schema, argsStr, fn, columns...)
+
+	if cursor, err := pagination.Decode(argsStr["cursor"]); argsStr["cursor"] != "" && err == nil {
+		after := []interface{}{}
+		for _, row := range rows {
+			if cursor.After(field(row, "UpdatedAt").Interface().(time.Time), rowID(row)) {
+				after = append(after, row)
+			}
+		}
+		rows = after
+		orderRows(rows, pagination.Order)
+	} else {
+		orderRows(rows, argsStr["order"])
+	}
+
+	// negative limit and offset cancel their conditions
+	limit, offset := argsInt["limit"], argsInt["offset"]
+	if offset > 0 {
+		if offset >= len(rows) {
+			return []interface{}{}
+		}
+		rows = rows[offset:]
+	}
+	if limit > 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// count returns the number of rows query would find without pagination
+func (t *table) count(schema string, argsStr map[string]string, fn func(row interface{}) bool, columns ...string) int {
+	return len(t.match(schema, argsStr, fn, columns...))
+}
+
+// match returns the rows matching fn, the search and the filters
+func (t *table) match(schema string, argsStr map[string]string, fn func(row interface{}) bool, columns ...string) []interface{} {
+	rows := []interface{}{}
+	for _, row := range t.all(schema) {
+		if fn != nil && !fn(row) {
+			continue
+		}
+		if argsStr["search"] != "" && !contains(row, argsStr["search"], columns) && !search.Matches(searchIndex(row), argsStr) {
+			continue
+		}
+		if favorite := argsStr["favorite"]; favorite != "" && !isFavorite(row, favorite == "true") {
+			continue
+		}
+		if folder := argsStr["folder"]; folder != "" && !inFolder(row, folder) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// delete soft deletes the row when the model has DeletedAt field
+func (t *table) delete(schema string, id uint) {
+	row, ok := t.rows[schema][id]
+	if !ok {
+		return
+	}
+
+	if deletedAt := field(row, "DeletedAt"); deletedAt.IsValid() {
+		now := time.Now()
+		deletedAt.Set(reflect.ValueOf(&now))
+		return
+	}
+	delete(t.rows[schema], id)
+}
+
+// deleted returns copies of the soft deleted rows, recently deleted first
+func (t *table) deleted(schema string) []interface{} {
+	rows := []interface{}{}
+	for _, row := range t.rows[schema] {
+		if isDeleted(row) {
+			rows = append(rows, clone(row))
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return deletedAt(rows[j]).Before(deletedAt(rows[i]))
+	})
+	return rows
+}
+
+// changed returns copies of the rows, soft deleted ones included, updated
+// or deleted after since ordered by id
+func (t *table) changed(schema string, since time.Time) []interface{} {
+	rows := []interface{}{}
+	for _, row := range t.rows[schema] {
+		if field(row, "UpdatedAt").Interface().(time.Time).After(since) || (isDeleted(row) && deletedAt(row).After(since)) {
+			rows = append(rows, clone(row))
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rowID(rows[i]) < rowID(rows[j])
+	})
+	return rows
+}
+
+// restore brings back a soft deleted row
+func (t *table) restore(schema string, id uint) error {
+	row, ok := t.rows[schema][id]
+	if !ok || !isDeleted(row) {
+		return gorm.ErrRecordNotFound
+	}
+	field(row, "DeletedAt").Set(reflect.Zero(field(row, "DeletedAt").Type()))
+	field(row, "UpdatedAt").Set(reflect.ValueOf(time.Now()))
+	return nil
+}
+
+// purge removes a soft deleted row for good
+func (t *table) purge(schema string, id uint) error {
+	row, ok := t.rows[schema][id]
+	if !ok || !isDeleted(row) {
+		return gorm.ErrRecordNotFound
+	}
+	delete(t.rows[schema], id)
+	return nil
+}
+
+// purgeDeletedBefore removes the rows soft deleted before the given time
+func (t *table) purgeDeletedBefore(schema string, before time.Time) {
+	for id, row := range t.rows[schema] {
+		if isDeleted(row) && deletedAt(row).Before(before) {
+			delete(t.rows[schema], id)
+		}
+	}
+}
+
+// update changes every row matching fn, deleted rows included.
+// The changed items move to their next revision.
+func (t *table) update(schema string, fn func(row interface{}) bool, change func(row interface{})) {
+	for _, row := range t.rows[schema] {
+		if fn(row) {
+			change(row)
+			field(row, "UpdatedAt").Set(reflect.ValueOf(time.Now()))
+			if revision := field(row, "Revision"); revision.IsValid() {
+				revision.SetUint(revision.Uint() + 1)
+			}
+		}
+	}
+}
+
+// deleteWhere removes every row matching fn
+func (t *table) deleteWhere(schema string, fn func(row interface{}) bool) {
+	for _, row := range t.all(schema) {
+		if fn(row) {
+			t.delete(schema, rowID(row))
+		}
+	}
+}
+
+// copy returns a copy of the table with copies of its rows
+func (t *table) copy() *table {
+	copied := newTable()
+	for schema, id := range t.nextID {
+		copied.nextID[schema] = id
+	}
+	for schema, rows := range t.rows {
+		copied.rows[schema] = map[uint]interface{}{}
+		for id, row := range rows {
+			copied.rows[schema][id] = clone(row)
+		}
+	}
+	return copied
+}
+
+// rollback brings the rows back to a copy of the table
+func (t *table) rollback(copied *table) {
+	t.nextID, t.rows = copied.nextID, copied.rows
+}
+
+// drop removes all the rows of a schema
+func (t *table) drop(schema string) {
+	delete(t.rows, schema)
+	delete(t.nextID, schema)
+}
+
+func field(row interface{}, name string) reflect.Value {
+	return reflect.ValueOf(row).Elem().FieldByName(name)
+}
+
+func rowID(row interface{}) uint {
+	id := field(row, "ID")
+	switch id.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint(id.Int())
+	default:
+		return uint(id.Uint())
+	}
+}
+
+func setRowID(row interface{}, value uint) {
+	id := field(row, "ID")
+	switch id.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		id.SetInt(int64(value))
+	default:
+		id.SetUint(uint64(value))
+	}
+}
+
+func clone(row interface{}) interface{} {
+	value := reflect.ValueOf(row).Elem()
+	copied := reflect.New(value.Type())
+	copied.Elem().Set(value)
+	return copied.Interface()
+}
+
+func deletedAt(row interface{}) time.Time {
+	return *field(row, "DeletedAt").Interface().(*time.Time)
+}
+
+func isDeleted(row interface{}) bool {
+	deletedAt := field(row, "DeletedAt")
+	return deletedAt.IsValid() && !deletedAt.IsNil()
+}
+
+// column returns the field of the row stored in the given database column
+func column(row interface{}, name string) reflect.Value {
+	value := reflect.ValueOf(row).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if gorm.ToColumnName(value.Type().Field(i).Name) == name {
+			return value.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// contains ignores case like LIKE does in SQLite
+func contains(row interface{}, search string, columns []string) bool {
+	search = strings.ToLower(search)
+	for _, name := range columns {
+		value := column(row, name)
+		if value.IsValid() && value.Kind() == reflect.String && strings.Contains(strings.ToLower(value.String()), search) {
+			return true
+		}
+	}
+	return false
+}
+
+func searchIndex(row interface{}) string {
+	if value := column(row, "search_index"); value.IsValid() {
+		return value.String()
+	}
+	return ""
+}
+
+func isFavorite(row interface{}, favorite bool) bool {
+	value := column(row, "favorite")
+	return value.IsValid() && value.Bool() == favorite
+}
+
+// inFolder reports whether the row is in the folder with the given id,
+// "0" matches the rows which are not in any folder
+func inFolder(row interface{}, folder string) bool {
+	value := column(row, "folder_id")
+	if !value.IsValid() {
+		return false
+	}
+	if value.IsNil() {
+		return folder == "0"
+	}
+	return strconv.FormatUint(value.Elem().Uint(), 10) == folder
+}
+
+// orderRows sorts rows according to an order clause like "updated_at desc, id desc"
+func orderRows(rows []interface{}, order string) {
+	type key struct {
+		column string
+		desc   bool
+	}
+	keys := []key{}
+	for _, clause := range strings.Split(order, ",") {
+		parts := strings.Fields(clause)
+		if len(parts) == 0 {
+			continue
+		}
+		keys = append(keys, key{column: parts[0], desc: len(parts) > 1 && strings.EqualFold(parts[1], "desc")})
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, k := range keys {
+			a, b := column(rows[i], k.column), column(rows[j], k.column)
+			if !a.IsValid() || !b.IsValid() {
+				return false
+			}
+			if k.desc {
+				a, b = b, a
+			}
+			if less(a, b) {
+				return true
+			}
+			if less(b, a) {
+				return false
+			}
+		}
+		return false
+	})
+}
+
+func less(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	case reflect.Struct:
+		if t, ok := a.Interface().(time.Time); ok {
+			return t.Before(b.Interface().(time.Time))
+		}
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() && !b.IsNil()
+		}
+		return less(a.Elem(), b.Elem())
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/passwall/passwall-server/model"
+)
+
+// TagRepository keeps tags and their links to items of every user schema in memory
+type TagRepository struct {
+	s     *Store
+	t     *table
+	items *table
+}
+
+// All ...
+func (p *TagRepository) All(schema string) ([]model.Tag, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	tags := []model.Tag{}
+	for _, row := range p.t.all(schema) {
+		tags = append(tags, *row.(*model.Tag))
+	}
+	return tags, nil
+}
+
+// FindByID ...
+func (p *TagRepository) FindByID(id uint, schema string) (*model.Tag, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	tag := new(model.Tag)
+	err := p.t.find(schema, id, tag)
+	return tag, err
+}
+
+// Save ...
+func (p *TagRepository) Save(tag *model.Tag, schema string) (*model.Tag, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, tag)
+	return tag, nil
+}
+
+// Delete ...
+func (p *TagRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.items.deleteWhere(schema, func(row interface{}) bool {
+		return row.(*model.ItemTag).TagID == id
+	})
+	p.t.delete(schema, id)
+	return nil
+}
+
+// Merge ...
+func (p *TagRepository) Merge(id, targetID uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	tagged := map[model.ItemRef]bool{}
+	for _, row := range p.items.all(schema) {
+		if itemTag := row.(*model.ItemTag); itemTag.TagID == targetID {
+			tagged[model.ItemRef{Type: itemTag.ItemType, ID: itemTag.ItemID}] = true
+		}
+	}
+
+	for _, row := range p.items.all(schema) {
+		itemTag := row.(*model.ItemTag)
+		if itemTag.TagID != id {
+			continue
+		}
+		if tagged[model.ItemRef{Type: itemTag.ItemType, ID: itemTag.ItemID}] {
+			p.items.delete(schema, itemTag.ID)
+			continue
+		}
+		itemTag.TagID = targetID
+		p.items.save(schema, itemTag)
+	}
+	p.t.delete(schema, id)
+	return nil
+}
+
+// FindItemTags ...
+func (p *TagRepository) FindItemTags(itemType string, itemIDs []uint, schema string) ([]model.ItemTag, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	ids := map[uint]bool{}
+	for _, id := range itemIDs {
+		ids[id] = true
+	}
+
+	itemTags := []model.ItemTag{}
+	for _, row := range p.items.all(schema) {
+		if itemTag := row.(*model.ItemTag); itemTag.ItemType == itemType && ids[itemTag.ItemID] {
+			itemTags = append(itemTags, *itemTag)
+		}
+	}
+	return itemTags, nil
+}
+
+// SetItemTags ...
+func (p *TagRepository) SetItemTags(itemType string, itemID uint, tagIDs []uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.items.deleteWhere(schema, func(row interface{}) bool {
+		itemTag := row.(*model.ItemTag)
+		return itemTag.ItemType == itemType && itemTag.ItemID == itemID
+	})
+	for _, tagID := range tagIDs {
+		p.items.save(schema, &model.ItemTag{TagID: tagID, ItemType: itemType, ItemID: itemID})
+	}
+	return nil
+}
+
+// DeleteItemTags ...
+func (p *TagRepository) DeleteItemTags(itemType string, itemIDs []uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	ids := map[uint]bool{}
+	for _, id := range itemIDs {
+		ids[id] = true
+	}
+	p.items.deleteWhere(schema, func(row interface{}) bool {
+		itemTag := row.(*model.ItemTag)
+		return itemTag.ItemType == itemType && ids[itemTag.ItemID]
+	})
+	return nil
+}
+
+// tagged returns a filter matching the items of the type having any of
+// the tags in the comma separated list, nil when the list is empty
+func (p *TagRepository) tagged(schema, itemType, tags string) func(row interface{}) bool {
+	if tags == "" {
+		return nil
+	}
+
+	tagIDs := map[string]bool{}
+	for _, id := range strings.Split(tags, ",") {
+		tagIDs[id] = true
+	}
+	itemIDs := map[uint]bool{}
+	for _, row := range p.items.all(schema) {
+		itemTag := row.(*model.ItemTag)
+		if itemTag.ItemType == itemType && tagIDs[strconv.FormatUint(uint64(itemTag.TagID), 10)] {
+			itemIDs[itemTag.ItemID] = true
+		}
+	}
+	return func(row interface{}) bool {
+		return itemIDs[rowID(row)]
+	}
+}
+
+// Migrate ...
+func (p *TagRepository) Migrate(schema string) error {
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"github.com/passwall/passwall-server/model"
+)
+
+// TemplateRepository keeps templates of every user schema in memory
+type TemplateRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *TemplateRepository) All(schema string) ([]model.Template, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	templates := []model.Template{}
+	for _, row := range p.t.all(schema) {
+		templates = append(templates, *row.(*model.Template))
+	}
+	return templates, nil
+}
+
+// FindByID ...
+func (p *TemplateRepository) FindByID(id uint, schema string) (*model.Template, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	template := new(model.Template)
+	err := p.t.find(schema, id, template)
+	return template, err
+}
+
+// FindByType ...
+func (p *TemplateRepository) FindByType(itemType string, schema string) (*model.Template, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	template := new(model.Template)
+	err := p.t.first(schema, template, func(row interface{}) bool {
+		return row.(*model.Template).Type == itemType
+	})
+	return template, err
+}
+
+// Save ...
+func (p *TemplateRepository) Save(template *model.Template, schema string) (*model.Template, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, template)
+	return template, nil
+}
+
+// Delete ...
+func (p *TemplateRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.delete(schema, id)
+	return nil
+}
+
+// Migrate ...
+func (p *TemplateRepository) Migrate(schema string) error {
+	return nil
+}
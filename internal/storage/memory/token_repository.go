@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/model"
+	uuid "github.com/satori/go.uuid"
+)
+
+// TokenRepository keeps tokens in memory
+type TokenRepository struct {
+	s *Store
+	t *table
+}
+
+// Any represents any match
+func (p *TokenRepository) Any(uuid string) (model.Token, bool) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	token := model.Token{}
+	err := p.t.first("", &token, func(row interface{}) bool {
+		return row.(*model.Token).UUID.String() == uuid
+	})
+	return token, err == nil
+}
+
+// Save saves model to the store
+func (p *TokenRepository) Save(userid int, uid uuid.UUID, tkn string, expriydate time.Time, transmissionKey string) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save("", &model.Token{
+		UserID:          userid,
+		UUID:            uid,
+		Token:           tkn,
+		ExpiryTime:      expriydate,
+		TransmissionKey: transmissionKey,
+	})
+}
+
+// Delete deletes tokens of the user
+func (p *TokenRepository) Delete(userid int) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.deleteWhere("", func(row interface{}) bool {
+		return row.(*model.Token).UserID == userid
+	})
+}
+
+// DeleteByUUID deletes token by uuid
+func (p *TokenRepository) DeleteByUUID(uuid string) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.deleteWhere("", func(row interface{}) bool {
+		return row.(*model.Token).UUID.String() == uuid
+	})
+}
+
+// Migrate ...
+func (p *TokenRepository) Migrate() error {
+	return nil
+}
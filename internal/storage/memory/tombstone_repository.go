@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/model"
+)
+
+// TombstoneRepository keeps the purged items of every user schema in memory
+type TombstoneRepository struct {
+	s *Store
+	t *table
+}
+
+// FindAllSince ...
+func (p *TombstoneRepository) FindAllSince(since time.Time, schema string) ([]model.Tombstone, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	tombstones := []model.Tombstone{}
+	for _, row := range p.t.all(schema) {
+		if tombstone := row.(*model.Tombstone); tombstone.CreatedAt.After(since) {
+			tombstones = append(tombstones, *tombstone)
+		}
+	}
+	return tombstones, nil
+}
+
+// Save ...
+func (p *TombstoneRepository) Save(tombstone *model.Tombstone, schema string) (*model.Tombstone, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save(schema, tombstone)
+	return tombstone, nil
+}
+
+// DeleteBefore ...
+func (p *TombstoneRepository) DeleteBefore(t time.Time, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.deleteWhere(schema, func(row interface{}) bool {
+		return row.(*model.Tombstone).CreatedAt.Before(t)
+	})
+	return nil
+}
+
+// Migrate ...
+func (p *TombstoneRepository) Migrate(schema string) error {
+	return nil
+}
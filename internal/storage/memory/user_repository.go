@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"github.com/passwall/passwall-server/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserRepository keeps users in memory
+type UserRepository struct {
+	s *Store
+	t *table
+}
+
+// All ...
+func (p *UserRepository) All() ([]model.User, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	users := []model.User{}
+	for _, row := range p.t.all("") {
+		users = append(users, *row.(*model.User))
+	}
+	return users, nil
+}
+
+// FindAll ...
+func (p *UserRepository) FindAll(argsStr map[string]string, argsInt map[string]int) ([]model.User, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	users := []model.User{}
+	for _, row := range p.t.query("", argsStr, argsInt, "name", "email", "role") {
+		users = append(users, *row.(*model.User))
+	}
+	return users, nil
+}
+
+// Count ...
+func (p *UserRepository) Count(argsStr map[string]string) (int, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	return p.t.count("", argsStr, nil, "name", "email", "role"), nil
+}
+
+// FindByID ...
+func (p *UserRepository) FindByID(id uint) (*model.User, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	user := new(model.User)
+	err := p.t.find("", id, user)
+	return user, err
+}
+
+// FindByUUID ...
+func (p *UserRepository) FindByUUID(uuid string) (*model.User, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	user := new(model.User)
+	err := p.t.first("", user, func(row interface{}) bool {
+		return row.(*model.User).UUID.String() == uuid
+	})
+	return user, err
+}
+
+// FindByEmail ...
+func (p *UserRepository) FindByEmail(email string) (*model.User, error) {
+	p.s.mu.RLock()
+	defer p.s.mu.RUnlock()
+
+	user := new(model.User)
+	err := p.t.first("", user, func(row interface{}) bool {
+		return row.(*model.User).Email == email
+	})
+	return user, err
+}
+
+// FindByCredentials ...
+func (p *UserRepository) FindByCredentials(email, masterPassword string) (*model.User, error) {
+	user, err := p.FindByEmail(email)
+	if err != nil {
+		return user, err
+	}
+
+	// Comparing the password with the bcrypt hash
+	err = bcrypt.CompareHashAndPassword([]byte(user.MasterPassword), []byte(masterPassword))
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// Save ...
+func (p *UserRepository) Save(user *model.User) (*model.User, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.t.save("", user)
+	return user, nil
+}
+
+// Delete ...
+func (p *UserRepository) Delete(id uint, schema string) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	p.s.dropSchema(schema)
+	p.t.delete("", id)
+	return nil
+}
+
+// Migrate ...
+func (p *UserRepository) Migrate() error {
+	return nil
+}
+
+// CreateSchema ...
+func (p *UserRepository) CreateSchema(schema string) error {
+	return nil
+}
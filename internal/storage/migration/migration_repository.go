@@ -0,0 +1,215 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Migration is an ordered schema change with its rollback.
+// Up and Down run in a transaction, schema is empty for the system tables.
+// Table names must be built with the table function.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB, schema string) error
+	Down    func(tx *gorm.DB, schema string) error
+}
+
+// Repository ...
+type Repository struct {
+	db     *gorm.DB
+	system []Migration
+	user   []Migration
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db, system: systemMigrations, user: userMigrations}
+}
+
+// transaction runs fn in a transaction scoped to the schema.
+// On Postgres the search_path is set to the schema, so the tables
+// of the schema are found by gorm without a schema qualifier.
+func (p *Repository) transaction(schema string, fn func(tx *gorm.DB) error) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if schema != "" && !dialect.IsSQLite(tx) {
+			if err := tx.Exec("SET LOCAL search_path TO " + tx.Dialect().Quote(schema)).Error; err != nil {
+				return err
+			}
+		}
+		return fn(tx)
+	})
+}
+
+// migrations returns the migrations of the schema ordered by version
+func (p *Repository) migrations(schema string) []Migration {
+	migrations := p.user
+	if schema == "" {
+		migrations = p.system
+	}
+
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return sorted
+}
+
+// applied returns the applied migrations of the schema ordered by version
+func (p *Repository) applied(schema string) ([]model.Migration, error) {
+	applied := []model.Migration{}
+	err := p.transaction(schema, func(tx *gorm.DB) error {
+		if err := tx.Table(table(tx, schema, "schema_migrations")).AutoMigrate(&model.Migration{}).Error; err != nil {
+			return err
+		}
+		return tx.Table(table(tx, schema, "schema_migrations")).Order("version asc").Find(&applied).Error
+	})
+	return applied, err
+}
+
+// Up applies the pending migrations of the schema in order
+func (p *Repository) Up(schema string) ([]model.Migration, error) {
+	applied, err := p.applied(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	done := map[uint]bool{}
+	for _, m := range applied {
+		done[m.Version] = true
+	}
+
+	migrated := []model.Migration{}
+	for _, m := range p.migrations(schema) {
+		if done[m.Version] {
+			continue
+		}
+
+		record := model.Migration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}
+		err := p.transaction(schema, func(tx *gorm.DB) error {
+			if err := m.Up(tx, schema); err != nil {
+				return err
+			}
+			return tx.Table(table(tx, schema, "schema_migrations")).Create(&record).Error
+		})
+		if err != nil {
+			return migrated, fmt.Errorf("migration %d %s failed: %w", m.Version, m.Name, err)
+		}
+		migrated = append(migrated, record)
+	}
+
+	return migrated, nil
+}
+
+// Down rolls back the last steps applied migrations of the schema
+func (p *Repository) Down(schema string, steps int) ([]model.Migration, error) {
+	applied, err := p.applied(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[uint]Migration{}
+	for _, m := range p.migrations(schema) {
+		known[m.Version] = m
+	}
+
+	rolledBack := []model.Migration{}
+	for i := len(applied) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		record := applied[i]
+		m, ok := known[record.Version]
+		if !ok {
+			return rolledBack, fmt.Errorf("migration %d %s is unknown, can't roll it back", record.Version, record.Name)
+		}
+
+		err := p.transaction(schema, func(tx *gorm.DB) error {
+			if err := m.Down(tx, schema); err != nil {
+				return err
+			}
+			return tx.Table(table(tx, schema, "schema_migrations")).Where("version = ?", record.Version).Delete(&model.Migration{}).Error
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("rollback of migration %d %s failed: %w", m.Version, m.Name, err)
+		}
+		rolledBack = append(rolledBack, record)
+	}
+
+	return rolledBack, nil
+}
+
+// Status lists the known migrations of the schema and whether they are applied
+func (p *Repository) Status(schema string) ([]model.MigrationStatus, error) {
+	applied, err := p.applied(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	appliedAt := map[uint]time.Time{}
+	for _, m := range applied {
+		appliedAt[m.Version] = m.AppliedAt
+	}
+
+	statuses := []model.MigrationStatus{}
+	for _, m := range p.migrations(schema) {
+		status := model.MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := appliedAt[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// lockID is the ID of the single migration lock row
+const lockID = 1
+
+// ErrLockLost is returned when the migration lock expired and was taken over
+var ErrLockLost = errors.New("migration lock was lost")
+
+// Lock acquires the migration lock for owner. It fails while another owner
+// holds the lock, unless the lock is older than ttl (e.g. its owner crashed).
+func (p *Repository) Lock(owner string, ttl time.Duration) error {
+	if err := p.db.AutoMigrate(&model.MigrationLock{}).Error; err != nil {
+		return err
+	}
+
+	// Remove the expired lock
+	err := p.db.Where("id = ? AND locked_at < ?", lockID, time.Now().Add(-ttl)).Delete(&model.MigrationLock{}).Error
+	if err != nil {
+		return err
+	}
+
+	err = p.db.Create(&model.MigrationLock{ID: lockID, Owner: owner, LockedAt: time.Now()}).Error
+	if err != nil {
+		lock := model.MigrationLock{}
+		if p.db.Where("id = ?", lockID).First(&lock).Error == nil {
+			return fmt.Errorf("migrations are locked by %s since %s", lock.Owner, lock.LockedAt.Format(time.RFC3339))
+		}
+		return err
+	}
+	return nil
+}
+
+// Refresh renews the migration lock held by owner, so it doesn't expire while
+// owner is still migrating. It fails when owner doesn't hold the lock anymore.
+func (p *Repository) Refresh(owner string) error {
+	query := p.db.Model(&model.MigrationLock{}).Where("id = ? AND owner = ?", lockID, owner).UpdateColumn("locked_at", time.Now())
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Unlock releases the migration lock held by owner
+func (p *Repository) Unlock(owner string) error {
+	return p.db.Where("id = ? AND owner = ?", lockID, owner).Delete(&model.MigrationLock{}).Error
+}
@@ -0,0 +1,131 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/passwall/passwall-server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRepository(t *testing.T) (*Repository, *gorm.DB) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	require.Nil(t, err)
+	db.DB().SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	// The baseline and a test migration, so later migrations don't change the test
+	p := NewRepository(db)
+	p.user = []Migration{userMigrations[0], {
+		Version: 2,
+		Name:    "add login color",
+		Up: func(tx *gorm.DB, schema string) error {
+			return addColumn(tx, schema, "logins", "color", "varchar(20)")
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropColumn(tx, schema, "logins", "color")
+		},
+	}}
+	return p, db
+}
+
+func TestUpDown(t *testing.T) {
+	p, db := newRepository(t)
+
+	migrated, err := p.Up("user1")
+	require.Nil(t, err)
+	assert.Len(t, migrated, 2)
+	assert.True(t, db.HasTable("user1_logins"))
+	assert.True(t, db.Dialect().HasColumn("user1_logins", "color"))
+	assert.False(t, db.HasTable("user2_logins"), "other schemas shouldn't be migrated")
+
+	migrated, err = p.Up("user1")
+	require.Nil(t, err)
+	assert.Empty(t, migrated, "applied migrations shouldn't run twice")
+
+	rolledBack, err := p.Down("user1", 1)
+	require.Nil(t, err)
+	require.Len(t, rolledBack, 1)
+	assert.Equal(t, uint(2), rolledBack[0].Version)
+	assert.False(t, db.Dialect().HasColumn("user1_logins", "color"))
+	assert.True(t, db.HasTable("user1_logins"))
+
+	statuses, err := p.Status("user1")
+	require.Nil(t, err)
+	assert.Equal(t, []bool{true, false}, []bool{statuses[0].Applied, statuses[1].Applied})
+
+	rolledBack, err = p.Down("user1", 5)
+	require.Nil(t, err)
+	assert.Len(t, rolledBack, 1)
+	assert.False(t, db.HasTable("user1_logins"))
+}
+
+func TestUserMigrations(t *testing.T) {
+	p, db := newRepository(t)
+	p.user = userMigrations
+
+	migrated, err := p.Up("user1")
+	require.Nil(t, err)
+	assert.Len(t, migrated, len(userMigrations))
+	assert.True(t, db.Dialect().HasColumn("user1_ssh_keys", "revision"))
+
+	// back to the custom fields, the tables are as they were at that version
+	_, err = p.Down("user1", len(userMigrations)-9)
+	require.Nil(t, err)
+	assert.False(t, db.HasTable("user1_custom_items"))
+	assert.False(t, db.HasTable("user1_ssh_keys"))
+	assert.True(t, db.Dialect().HasColumn("user1_identities", "custom_fields"))
+	assert.False(t, db.Dialect().HasColumn("user1_logins", "revision"))
+
+	_, err = p.Down("user1", len(userMigrations))
+	require.Nil(t, err)
+	assert.False(t, db.HasTable("user1_logins"))
+	assert.False(t, db.HasTable("user1_identities"))
+}
+
+func TestSystemTables(t *testing.T) {
+	p, db := newRepository(t)
+
+	migrated, err := p.Up("")
+	require.Nil(t, err)
+	assert.Equal(t, []uint{1}, versions(migrated))
+	assert.True(t, db.HasTable("users"))
+	assert.True(t, db.HasTable("schema_migrations"))
+	assert.False(t, db.HasTable("logins"))
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	p, db := newRepository(t)
+	p.user = append(p.user, Migration{
+		Version: 3,
+		Name:    "broken",
+		Up: func(tx *gorm.DB, schema string) error {
+			if err := addColumn(tx, schema, "logins", "shape", "varchar(20)"); err != nil {
+				return err
+			}
+			return errors.New("broken migration")
+		},
+		Down: func(tx *gorm.DB, schema string) error { return nil },
+	})
+
+	migrated, err := p.Up("user1")
+	assert.NotNil(t, err)
+	assert.Equal(t, []uint{1, 2}, versions(migrated))
+	assert.False(t, db.Dialect().HasColumn("user1_logins", "shape"))
+
+	statuses, err := p.Status("user1")
+	require.Nil(t, err)
+	require.Len(t, statuses, 3)
+	assert.False(t, statuses[2].Applied)
+}
+
+func versions(migrations []model.Migration) []uint {
+	versions := []uint{}
+	for _, m := range migrations {
+		versions = append(versions, m.Version)
+	}
+	return versions
+}
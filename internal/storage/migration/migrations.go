@@ -0,0 +1,304 @@
+package migration
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/model"
+)
+
+// New migrations are appended to these lists with the next version number.
+// Released migrations must never be edited, add a new one instead. Every
+// migration lists the tables it changes itself, the tables which existed
+// when it was released, so item types added later don't change it.
+
+// systemMigrations run on the tables shared by all users
+var systemMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create system tables",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema,
+				&tableModel{"tokens", &model.Token{}},
+				&tableModel{"users", &model.User{}},
+				&tableModel{"subscriptions", &model.Subscription{}},
+			)
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "tokens", "users", "subscriptions")
+		},
+	},
+}
+
+// userMigrations run on every user schema
+var userMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create item tables",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema,
+				&tableModel{"logins", &model.Login{}},
+				&tableModel{"credit_cards", &model.CreditCard{}},
+				&tableModel{"bank_accounts", &model.BankAccount{}},
+				&tableModel{"notes", &model.Note{}},
+				&tableModel{"emails", &model.Email{}},
+				&tableModel{"servers", &model.Server{}},
+			)
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "logins", "credit_cards", "bank_accounts", "notes", "emails", "servers")
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create revisions table",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema, &tableModel{"revisions", &model.Revision{}})
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "revisions")
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add favorite flag",
+		Up: func(tx *gorm.DB, schema string) error {
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers"} {
+				if err := addColumn(tx, schema, name, "favorite", "boolean DEFAULT false"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers"} {
+				if err := dropColumn(tx, schema, name, "favorite"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create folders",
+		Up: func(tx *gorm.DB, schema string) error {
+			if err := autoMigrate(tx, schema, &tableModel{"folders", &model.Folder{}}); err != nil {
+				return err
+			}
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers"} {
+				if err := addColumn(tx, schema, name, "folder_id", "integer"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers"} {
+				if err := dropColumn(tx, schema, name, "folder_id"); err != nil {
+					return err
+				}
+			}
+			return dropTables(tx, schema, "folders")
+		},
+	},
+	{
+		Version: 5,
+		Name:    "create tags",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema,
+				&tableModel{"tags", &model.Tag{}},
+				&tableModel{"item_tags", &model.ItemTag{}},
+			)
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "item_tags", "tags")
+		},
+	},
+	{
+		// The indexes of the existing items are built by the reindex command
+		Version: 6,
+		Name:    "add search index",
+		Up: func(tx *gorm.DB, schema string) error {
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers"} {
+				if err := addColumn(tx, schema, name, "search_index", "text"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers"} {
+				if err := dropColumn(tx, schema, name, "search_index"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 7,
+		Name:    "create identities table",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema, &tableModel{"identities", &model.Identity{}})
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "identities")
+		},
+	},
+	{
+		Version: 8,
+		Name:    "create license keys table",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema, &tableModel{"license_keys", &model.LicenseKey{}})
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "license_keys")
+		},
+	},
+	{
+		Version: 9,
+		Name:    "add custom fields",
+		Up: func(tx *gorm.DB, schema string) error {
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers", "identities", "license_keys"} {
+				if err := addColumn(tx, schema, name, "custom_fields", "text"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers", "identities", "license_keys"} {
+				if err := dropColumn(tx, schema, name, "custom_fields"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 10,
+		Name:    "create templates and custom items",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema,
+				&tableModel{"templates", &model.Template{}},
+				&tableModel{"custom_items", &model.CustomItem{}},
+			)
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "custom_items", "templates")
+		},
+	},
+	{
+		Version: 11,
+		Name:    "create attachments table",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema, &tableModel{"attachments", &model.Attachment{}})
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "attachments")
+		},
+	},
+	{
+		Version: 12,
+		Name:    "add totp to logins",
+		Up: func(tx *gorm.DB, schema string) error {
+			return addColumn(tx, schema, "logins", "totp", "text")
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropColumn(tx, schema, "logins", "totp")
+		},
+	},
+	{
+		Version: 13,
+		Name:    "create ssh keys table",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema, &tableModel{"ssh_keys", &model.SSHKey{}})
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "ssh_keys")
+		},
+	},
+	{
+		Version: 14,
+		Name:    "create tombstones table",
+		Up: func(tx *gorm.DB, schema string) error {
+			return autoMigrate(tx, schema, &tableModel{"tombstones", &model.Tombstone{}})
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			return dropTables(tx, schema, "tombstones")
+		},
+	},
+	{
+		Version: 15,
+		Name:    "add item revisions",
+		Up: func(tx *gorm.DB, schema string) error {
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers", "identities", "license_keys", "custom_items", "ssh_keys"} {
+				if err := addColumn(tx, schema, name, "revision", "integer NOT NULL DEFAULT 1"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB, schema string) error {
+			for _, name := range []string{"logins", "credit_cards", "bank_accounts", "notes", "emails", "servers", "identities", "license_keys", "custom_items", "ssh_keys"} {
+				if err := dropColumn(tx, schema, name, "revision"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// table returns the name of a table in the migrated schema.
+// On Postgres the search_path is set to the schema during a migration.
+func table(tx *gorm.DB, schema, name string) string {
+	if dialect.IsSQLite(tx) {
+		return dialect.Table(tx, schema, name)
+	}
+	return name
+}
+
+type tableModel struct {
+	name  string
+	model interface{}
+}
+
+// autoMigrate creates the tables or adds their missing columns
+func autoMigrate(tx *gorm.DB, schema string, tables ...*tableModel) error {
+	for _, t := range tables {
+		if err := tx.Table(table(tx, schema, t.name)).AutoMigrate(t.model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropTables drops the tables if they exist
+func dropTables(tx *gorm.DB, schema string, names ...string) error {
+	for _, name := range names {
+		if err := tx.DropTableIfExists(table(tx, schema, name)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumn adds a column unless it already exists. Tables which don't exist
+// yet are skipped, they get the column when they are created.
+func addColumn(tx *gorm.DB, schema, name, column, sqlType string) error {
+	name = table(tx, schema, name)
+	if !tx.Dialect().HasTable(name) || tx.Dialect().HasColumn(name, column) {
+		return nil
+	}
+	return tx.Exec("ALTER TABLE " + tx.Dialect().Quote(name) + " ADD COLUMN " + tx.Dialect().Quote(column) + " " + sqlType).Error
+}
+
+// dropColumn drops a column if it exists
+func dropColumn(tx *gorm.DB, schema, name, column string) error {
+	name = table(tx, schema, name)
+	if !tx.Dialect().HasTable(name) || !tx.Dialect().HasColumn(name, column) {
+		return nil
+	}
+	return tx.Exec("ALTER TABLE " + tx.Dialect().Quote(name) + " DROP COLUMN " + tx.Dialect().Quote(column)).Error
+}
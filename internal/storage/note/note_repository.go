@@ -1,7 +1,14 @@
 package note
 
 import (
+	"strings"
+	"time"
+
 	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
 	"github.com/passwall/passwall-server/model"
 )
 
@@ -15,56 +22,138 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "notes")
+}
+
 // All ...
 func (p *Repository) All(schema string) ([]model.Note, error) {
 	notes := []model.Note{}
-	err := p.db.Table(schema + ".notes").Find(&notes).Error
+	err := p.db.Table(p.table(schema)).Find(&notes).Error
 	return notes, err
 }
 
 // FindAll ...
 func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Note, error) {
 	notes := []model.Note{}
+	query := pagination.Apply(p.filter(argsStr, schema), argsStr, argsInt)
+	err := query.Find(&notes).Error
+	return notes, err
+}
 
-	query := p.db
-	query = query.Table(schema + ".notes")
-	query = query.Limit(argsInt["limit"])
-	if argsInt["limit"] > 0 {
-		// offset can't be declared without a valid limit
-		query = query.Offset(argsInt["offset"])
-	}
+// Count returns the number of entities matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string, schema string) (int, error) {
+	count := 0
+	err := p.filter(argsStr, schema).Model(&model.Note{}).Count(&count).Error
+	return count, err
+}
 
-	query = query.Order(argsStr["order"])
+// filter returns the query of the entities matching the search and the filters
+func (p *Repository) filter(argsStr map[string]string, schema string) *gorm.DB {
+	query := p.db.Table(p.table(schema))
 
 	// TODO: This is not working because notes are encrypted
 	if argsStr["search"] != "" {
-		query = query.Where("note LIKE ?", "%"+argsStr["search"]+"%")
+		condition, values := search.Condition(argsStr, "title")
+		query = query.Where(condition, values...)
 	}
 
-	err := query.Find(&notes).Error
-	return notes, err
+	if argsStr["favorite"] != "" {
+		query = query.Where("favorite = ?", argsStr["favorite"] == "true")
+	}
+
+	if argsStr["folder"] == "0" {
+		query = query.Where("folder_id IS NULL")
+	} else if argsStr["folder"] != "" {
+		query = query.Where("folder_id = ?", argsStr["folder"])
+	}
+
+	if argsStr["tags"] != "" {
+		tagged := p.db.Table(dialect.Table(p.db, schema, "item_tags")).Select("item_id").
+			Where("item_type = ? AND tag_id IN (?)", model.NoteItem, strings.Split(argsStr["tags"], ",")).
+			SubQuery()
+		query = query.Where("id IN ?", tagged)
+	}
+
+	return query
 }
 
 // FindByID ...
 func (p *Repository) FindByID(id uint, schema string) (*model.Note, error) {
 	note := new(model.Note)
-	err := p.db.Table(schema+".notes").Where(`id = ?`, id).First(&note).Error
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&note).Error
 	return note, err
 }
 
 // Save ...
 func (p *Repository) Save(note *model.Note, schema string) (*model.Note, error) {
-	err := p.db.Table(schema + ".notes").Save(&note).Error
+	err := p.db.Unscoped().Table(p.table(schema)).Save(&note).Error
+	return note, err
+}
+
+// Update ...
+func (p *Repository) Update(note *model.Note, revision uint, schema string) (*model.Note, error) {
+	err := update.Revision(p.db, p.table(schema), note, revision)
+	return note, err
+}
+
+// SaveEncrypted ...
+func (p *Repository) SaveEncrypted(note *model.Note, schema string) (*model.Note, error) {
+	err := update.Encrypted(p.db, p.table(schema), note)
 	return note, err
 }
 
 // Delete ...
 func (p *Repository) Delete(id uint, schema string) error {
-	err := p.db.Table(schema + ".notes").Delete(&model.Note{ID: id}).Error
+	err := p.db.Table(p.table(schema)).Delete(&model.Note{ID: id}).Error
 	return err
 }
 
+// FindAllDeleted ...
+func (p *Repository) FindAllDeleted(schema string) ([]model.Note, error) {
+	notes := []model.Note{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&notes).Error
+	return notes, err
+}
+
+// FindAllChanged ...
+func (p *Repository) FindAllChanged(since time.Time, schema string) ([]model.Note, error) {
+	notes := []model.Note{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("updated_at > ? OR deleted_at > ?", since, since).Order("id").Find(&notes).Error
+	return notes, err
+}
+
+// Restore ...
+func (p *Repository) Restore(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge ...
+func (p *Repository) Purge(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.Note{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedBefore ...
+func (p *Repository) PurgeDeletedBefore(t time.Time, schema string) error {
+	return p.db.Unscoped().Table(p.table(schema)).Where("deleted_at < ?", t).Delete(&model.Note{}).Error
+}
+
 // Migrate ...
 func (p *Repository) Migrate(schema string) error {
-	return p.db.Table(schema + ".notes").AutoMigrate(&model.Note{}).Error
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Note{}).Error
 }
@@ -0,0 +1,77 @@
+// Package pagination pages the FindAll queries. A page starts at an offset,
+// or after the row an opaque cursor points to. Cursors are keysets of the
+// updated_at and id columns, so pages don't shift when rows are added.
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+)
+
+// Order is the default order of the lists, the only one cursors work with
+const Order = "updated_at desc, id desc"
+
+// ErrInvalidCursor represents message for a cursor which can't be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points to the last row of a page
+type Cursor struct {
+	UpdatedAt time.Time
+	ID        uint
+}
+
+// Encode returns the opaque cursor of the row
+func Encode(updatedAt time.Time, id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", updatedAt.UnixNano(), id)))
+}
+
+// Decode parses an opaque cursor
+func Decode(cursor string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var nanos int64
+	var id uint
+	if _, err := fmt.Sscanf(string(data), "%d:%d", &nanos, &id); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{UpdatedAt: dialect.Time(time.Unix(0, nanos)), ID: id}, nil
+}
+
+// After reports whether a row comes after the cursor in the default order
+func (c Cursor) After(updatedAt time.Time, id uint) bool {
+	return updatedAt.Before(c.UpdatedAt) || (updatedAt.Equal(c.UpdatedAt) && id < c.ID)
+}
+
+// Apply orders and pages the query. With a cursor in argsStr the rows after
+// it are found in the default order, otherwise the order of argsStr is kept.
+// Negative limits and offsets cancel the conditions.
+func Apply(query *gorm.DB, argsStr map[string]string, argsInt map[string]int) *gorm.DB {
+	if cursor, err := Decode(argsStr["cursor"]); argsStr["cursor"] != "" && err == nil {
+		query = query.Where("(updated_at < ? OR (updated_at = ? AND id < ?))", cursor.UpdatedAt, cursor.UpdatedAt, cursor.ID)
+		query = query.Order(Order)
+	} else {
+		query = query.Order(argsStr["order"])
+	}
+
+	limit, offset := argsInt["limit"], argsInt["offset"]
+	if limit < 1 && offset > 0 {
+		// SQLite and MySQL don't allow an offset without a limit
+		limit = math.MaxInt32
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	return query
+}
@@ -14,12 +14,30 @@ type LoginRepository interface {
 	All(schema string) ([]model.Login, error)
 	// FindAll returns the entities matching the arguments.
 	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Login, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
 	// FindByID finds the entity regarding to its ID.
 	FindByID(id uint, schema string) (*model.Login, error)
 	// Save stores the entity to the repository
 	Save(login *model.Login, schema string) (*model.Login, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(login *model.Login, revision uint, schema string) (*model.Login, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(login *model.Login, schema string) (*model.Login, error)
 	// Delete removes the entity from the store
 	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.Login, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.Login, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
 	// Migrate migrates the repository
 	Migrate(schema string) error
 }
@@ -31,12 +49,30 @@ type CreditCardRepository interface {
 	All(schema string) ([]model.CreditCard, error)
 	// FindAll returns the entities matching the arguments.
 	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.CreditCard, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
 	// FindByID finds the entity regarding to its ID.
 	FindByID(id uint, schema string) (*model.CreditCard, error)
 	// Save stores the entity to the repository
 	Save(card *model.CreditCard, schema string) (*model.CreditCard, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(card *model.CreditCard, revision uint, schema string) (*model.CreditCard, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(card *model.CreditCard, schema string) (*model.CreditCard, error)
 	// Delete removes the entity from the store
 	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.CreditCard, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.CreditCard, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
 	// Migrate migrates the repository
 	Migrate(schema string) error
 }
@@ -48,12 +84,30 @@ type BankAccountRepository interface {
 	All(schema string) ([]model.BankAccount, error)
 	// FindAll returns the entities matching the arguments.
 	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.BankAccount, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
 	// FindByID finds the entity regarding to its ID.
 	FindByID(id uint, schema string) (*model.BankAccount, error)
 	// Save stores the entity to the repository
 	Save(account *model.BankAccount, schema string) (*model.BankAccount, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(account *model.BankAccount, revision uint, schema string) (*model.BankAccount, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(account *model.BankAccount, schema string) (*model.BankAccount, error)
 	// Delete removes the entity from the store
 	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.BankAccount, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.BankAccount, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
 	// Migrate migrates the repository
 	Migrate(schema string) error
 }
@@ -65,12 +119,30 @@ type NoteRepository interface {
 	All(schema string) ([]model.Note, error)
 	// FindAll returns the entities matching the arguments.
 	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Note, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
 	// FindByID finds the entity regarding to its ID.
 	FindByID(id uint, schema string) (*model.Note, error)
 	// Save stores the entity to the repository
 	Save(account *model.Note, schema string) (*model.Note, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(account *model.Note, revision uint, schema string) (*model.Note, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(account *model.Note, schema string) (*model.Note, error)
 	// Delete removes the entity from the store
 	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.Note, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.Note, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
 	// Migrate migrates the repository
 	Migrate(schema string) error
 }
@@ -82,12 +154,170 @@ type EmailRepository interface {
 	All(schema string) ([]model.Email, error)
 	// FindAll returns the entities matching the arguments.
 	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Email, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
 	// FindByID finds the entity regarding to its ID.
 	FindByID(id uint, schema string) (*model.Email, error)
 	// Save stores the entity to the repository
 	Save(account *model.Email, schema string) (*model.Email, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(account *model.Email, revision uint, schema string) (*model.Email, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(account *model.Email, schema string) (*model.Email, error)
 	// Delete removes the entity from the store
 	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.Email, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.Email, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
+
+// IdentityRepository interface is the common interface for a repository
+// Each method checks the entity type.
+type IdentityRepository interface {
+	// All returns all the data in the repository.
+	All(schema string) ([]model.Identity, error)
+	// FindAll returns the entities matching the arguments.
+	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Identity, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
+	// FindByID finds the entity regarding to its ID.
+	FindByID(id uint, schema string) (*model.Identity, error)
+	// Save stores the entity to the repository
+	Save(identity *model.Identity, schema string) (*model.Identity, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(identity *model.Identity, revision uint, schema string) (*model.Identity, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(identity *model.Identity, schema string) (*model.Identity, error)
+	// Delete removes the entity from the store
+	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.Identity, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.Identity, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
+
+// LicenseKeyRepository interface is the common interface for a repository
+// Each method checks the entity type.
+type LicenseKeyRepository interface {
+	// All returns all the data in the repository.
+	All(schema string) ([]model.LicenseKey, error)
+	// FindAll returns the entities matching the arguments.
+	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.LicenseKey, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
+	// FindByID finds the entity regarding to its ID.
+	FindByID(id uint, schema string) (*model.LicenseKey, error)
+	// Save stores the entity to the repository
+	Save(licenseKey *model.LicenseKey, schema string) (*model.LicenseKey, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(licenseKey *model.LicenseKey, revision uint, schema string) (*model.LicenseKey, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(licenseKey *model.LicenseKey, schema string) (*model.LicenseKey, error)
+	// Delete removes the entity from the store
+	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.LicenseKey, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.LicenseKey, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
+
+// CustomItemRepository interface is the common interface for the items of
+// every template, FindAll and Count find the items of a template by the "type" argument.
+type CustomItemRepository interface {
+	// All returns all the data in the repository.
+	All(schema string) ([]model.CustomItem, error)
+	// FindAll returns the entities matching the arguments.
+	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.CustomItem, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
+	// FindByID finds the entity regarding to its ID.
+	FindByID(id uint, schema string) (*model.CustomItem, error)
+	// Save stores the entity to the repository
+	Save(customItem *model.CustomItem, schema string) (*model.CustomItem, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(customItem *model.CustomItem, revision uint, schema string) (*model.CustomItem, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(customItem *model.CustomItem, schema string) (*model.CustomItem, error)
+	// Delete removes the entity from the store
+	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.CustomItem, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.CustomItem, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
+
+// SSHKeyRepository interface is the common interface for a repository
+// Each method checks the entity type.
+type SSHKeyRepository interface {
+	// All returns all the data in the repository.
+	All(schema string) ([]model.SSHKey, error)
+	// FindAll returns the entities matching the arguments.
+	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.SSHKey, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
+	// FindByID finds the entity regarding to its ID.
+	FindByID(id uint, schema string) (*model.SSHKey, error)
+	// Save stores the entity to the repository
+	Save(sshKey *model.SSHKey, schema string) (*model.SSHKey, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(sshKey *model.SSHKey, revision uint, schema string) (*model.SSHKey, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(sshKey *model.SSHKey, schema string) (*model.SSHKey, error)
+	// Delete removes the entity from the store
+	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.SSHKey, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.SSHKey, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
 	// Migrate migrates the repository
 	Migrate(schema string) error
 }
@@ -109,6 +339,8 @@ type UserRepository interface {
 	All() ([]model.User, error)
 	// FindAll returns the entities matching the arguments.
 	FindAll(argsStr map[string]string, argsInt map[string]int) ([]model.User, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string) (int, error)
 	// FindByID finds the entity regarding to its ID.
 	FindByID(id uint) (*model.User, error)
 	// FindByUUID finds the entity regarding to its UUID.
@@ -134,12 +366,30 @@ type ServerRepository interface {
 	All(schema string) ([]model.Server, error)
 	// FindAll returns the entities matching the arguments.
 	FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Server, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string, schema string) (int, error)
 	// FindByID finds the entity regarding to its ID.
 	FindByID(id uint, schema string) (*model.Server, error)
 	// Save stores the entity to the repository
 	Save(server *model.Server, schema string) (*model.Server, error)
+	// Update stores the entity unless another update moved it past revision, 0 is any revision.
+	// The entity moves to its next revision.
+	Update(server *model.Server, revision uint, schema string) (*model.Server, error)
+	// SaveEncrypted stores the encrypted fields and the search index of the entity only,
+	// its revision and update time stay as they are.
+	SaveEncrypted(server *model.Server, schema string) (*model.Server, error)
 	// Delete removes the entity from the store
 	Delete(id uint, schema string) error
+	// FindAllDeleted returns the soft deleted entities, recently deleted first.
+	FindAllDeleted(schema string) ([]model.Server, error)
+	// FindAllChanged returns the entities, soft deleted ones included, updated or deleted after since
+	FindAllChanged(since time.Time, schema string) ([]model.Server, error)
+	// Restore brings back a soft deleted entity
+	Restore(id uint, schema string) error
+	// Purge permanently removes a soft deleted entity
+	Purge(id uint, schema string) error
+	// PurgeDeletedBefore permanently removes the entities deleted before t
+	PurgeDeletedBefore(t time.Time, schema string) error
 	// Migrate migrates the repository
 	Migrate(schema string) error
 }
@@ -151,6 +401,8 @@ type SubscriptionRepository interface {
 	All() ([]model.Subscription, error)
 	// FindAll returns the entities matching the arguments.
 	FindAll(argsStr map[string]string, argsInt map[string]int) ([]model.Subscription, error)
+	// Count returns the number of entities matching the arguments, regardless of the page.
+	Count(argsStr map[string]string) (int, error)
 	// FindByID finds the entity regarding to its ID.
 	FindByID(id uint) (*model.Subscription, error)
 	// FindByEmail finds the entity regarding to its email.
@@ -164,3 +416,118 @@ type SubscriptionRepository interface {
 	// Migrate migrates the repository
 	Migrate() error
 }
+
+// MigrationRepository applies the versioned schema migrations.
+// An empty schema stands for the system tables.
+type MigrationRepository interface {
+	// Up applies the pending migrations and returns them
+	Up(schema string) ([]model.Migration, error)
+	// Down rolls back the last steps applied migrations and returns them
+	Down(schema string, steps int) ([]model.Migration, error)
+	// Status lists the known migrations and whether they are applied
+	Status(schema string) ([]model.MigrationStatus, error)
+	// Lock acquires the migration lock, ttl expires locks of crashed owners
+	Lock(owner string, ttl time.Duration) error
+	// Refresh renews the migration lock, it fails when owner lost the lock
+	Refresh(owner string) error
+	// Unlock releases the migration lock
+	Unlock(owner string) error
+}
+
+// TemplateRepository interface is the common interface for custom item templates
+type TemplateRepository interface {
+	// All returns all the templates ordered by id.
+	All(schema string) ([]model.Template, error)
+	// FindByID finds the entity regarding to its ID.
+	FindByID(id uint, schema string) (*model.Template, error)
+	// FindByType finds the template of a custom item type.
+	FindByType(itemType string, schema string) (*model.Template, error)
+	// Save stores the entity to the repository
+	Save(template *model.Template, schema string) (*model.Template, error)
+	// Delete removes the entity from the store
+	Delete(id uint, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
+
+// AttachmentRepository interface is the common interface for item attachments
+type AttachmentRepository interface {
+	// FindAll returns the attachments of an item ordered by id.
+	FindAll(itemType string, itemID uint, schema string) ([]model.Attachment, error)
+	// FindAllByItems returns the attachments of the items ordered by id.
+	FindAllByItems(itemType string, itemIDs []uint, schema string) ([]model.Attachment, error)
+	// FindByID finds the entity regarding to its ID.
+	FindByID(id uint, schema string) (*model.Attachment, error)
+	// TotalSize returns the size of every attachment of the schema in bytes.
+	TotalSize(schema string) (int64, error)
+	// Save stores the entity to the repository
+	Save(attachment *model.Attachment, schema string) (*model.Attachment, error)
+	// Delete removes the entity from the store, the blob is left as it is
+	Delete(id uint, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
+
+// FolderRepository interface is the common interface for item folders
+type FolderRepository interface {
+	// All returns all the folders ordered by id.
+	All(schema string) ([]model.Folder, error)
+	// FindByID finds the entity regarding to its ID.
+	FindByID(id uint, schema string) (*model.Folder, error)
+	// Save stores the entity to the repository
+	Save(folder *model.Folder, schema string) (*model.Folder, error)
+	// Delete removes the entity from the store
+	Delete(id uint, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
+
+// TagRepository interface is the common interface for tags and their links to items
+type TagRepository interface {
+	// All returns all the tags ordered by id.
+	All(schema string) ([]model.Tag, error)
+	// FindByID finds the entity regarding to its ID.
+	FindByID(id uint, schema string) (*model.Tag, error)
+	// Save stores the entity to the repository
+	Save(tag *model.Tag, schema string) (*model.Tag, error)
+	// Delete removes the tag and unlinks it from every item
+	Delete(id uint, schema string) error
+	// Merge links the items of a tag to the target tag and removes the tag
+	Merge(id, targetID uint, schema string) error
+	// FindItemTags returns the links of the items to their tags
+	FindItemTags(itemType string, itemIDs []uint, schema string) ([]model.ItemTag, error)
+	// SetItemTags replaces the tags of an item
+	SetItemTags(itemType string, itemID uint, tagIDs []uint, schema string) error
+	// DeleteItemTags unlinks the items from every tag
+	DeleteItemTags(itemType string, itemIDs []uint, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
+
+// RevisionRepository interface is the common interface for item revisions
+type RevisionRepository interface {
+	// FindAll returns the revisions of an item, newest first.
+	FindAll(itemType string, itemID uint, schema string) ([]model.Revision, error)
+	// FindByID finds the entity regarding to its ID.
+	FindByID(id uint, schema string) (*model.Revision, error)
+	// Save stores the entity to the repository
+	Save(revision *model.Revision, schema string) (*model.Revision, error)
+	// Prune removes the revisions of an item except the newest keep ones
+	Prune(itemType string, itemID uint, keep int, schema string) error
+	// DeleteAll removes every revision of the items
+	DeleteAll(itemType string, itemIDs []uint, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
+
+// TombstoneRepository interface is the common interface for the records of purged items
+type TombstoneRepository interface {
+	// FindAllSince returns the items purged after since, in the order they were purged.
+	FindAllSince(since time.Time, schema string) ([]model.Tombstone, error)
+	// Save stores the entity to the repository
+	Save(tombstone *model.Tombstone, schema string) (*model.Tombstone, error)
+	// DeleteBefore removes the tombstones of the items purged before t.
+	DeleteBefore(t time.Time, schema string) error
+	// Migrate migrates the repository
+	Migrate(schema string) error
+}
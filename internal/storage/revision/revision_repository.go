@@ -0,0 +1,72 @@
+package revision
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Repository ...
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "revisions")
+}
+
+// FindAll ...
+func (p *Repository) FindAll(itemType string, itemID uint, schema string) ([]model.Revision, error) {
+	revisions := []model.Revision{}
+	err := p.db.Table(p.table(schema)).
+		Where("item_type = ? AND item_id = ?", itemType, itemID).
+		Order("id desc").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// FindByID ...
+func (p *Repository) FindByID(id uint, schema string) (*model.Revision, error) {
+	revision := new(model.Revision)
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&revision).Error
+	return revision, err
+}
+
+// Save ...
+func (p *Repository) Save(revision *model.Revision, schema string) (*model.Revision, error) {
+	err := p.db.Table(p.table(schema)).Save(&revision).Error
+	return revision, err
+}
+
+// Prune ...
+func (p *Repository) Prune(itemType string, itemID uint, keep int, schema string) error {
+	var ids []uint
+	err := p.db.Table(p.table(schema)).
+		Where("item_type = ? AND item_id = ?", itemType, itemID).
+		Order("id desc").
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) <= keep {
+		return err
+	}
+	return p.db.Table(p.table(schema)).Where("id IN (?)", ids[keep:]).Delete(&model.Revision{}).Error
+}
+
+// DeleteAll ...
+func (p *Repository) DeleteAll(itemType string, itemIDs []uint, schema string) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	return p.db.Table(p.table(schema)).
+		Where("item_type = ? AND item_id IN (?)", itemType, itemIDs).
+		Delete(&model.Revision{}).Error
+}
+
+// Migrate ...
+func (p *Repository) Migrate(schema string) error {
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Revision{}).Error
+}
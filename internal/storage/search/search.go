@@ -0,0 +1,53 @@
+// Package search builds the conditions of the item searches. Plain columns
+// are searched with LIKE, encrypted fields through their blind indexes in
+// the search_index column, see app.SearchIndexQuery.
+package search
+
+import "strings"
+
+// Condition returns a grouped condition matching the rows which contain the
+// search in any of the columns ignoring case, or whose search index has the exact blind
+// index of the search or the prefix blind indexes of all its words
+func Condition(argsStr map[string]string, columns ...string) (string, []interface{}) {
+	conditions := []string{}
+	values := []interface{}{}
+	for _, column := range columns {
+		conditions = append(conditions, "LOWER("+column+") LIKE ?")
+		values = append(values, "%"+strings.ToLower(argsStr["search"])+"%")
+	}
+
+	if exact := argsStr["search_exact"]; exact != "" {
+		conditions = append(conditions, "search_index LIKE ?")
+		values = append(values, "% "+exact+" %")
+	}
+
+	if tokens := strings.Fields(argsStr["search_tokens"]); len(tokens) > 0 {
+		prefixes := make([]string, len(tokens))
+		for i, token := range tokens {
+			prefixes[i] = "search_index LIKE ?"
+			values = append(values, "% "+token+" %")
+		}
+		conditions = append(conditions, "("+strings.Join(prefixes, " AND ")+")")
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", values
+}
+
+// Matches reports whether a search index matches the blind indexes
+// of the search the same way Condition does
+func Matches(index string, argsStr map[string]string) bool {
+	if exact := argsStr["search_exact"]; exact != "" && strings.Contains(index, " "+exact+" ") {
+		return true
+	}
+
+	tokens := strings.Fields(argsStr["search_tokens"])
+	if len(tokens) == 0 {
+		return false
+	}
+	for _, token := range tokens {
+		if !strings.Contains(index, " "+token+" ") {
+			return false
+		}
+	}
+	return true
+}
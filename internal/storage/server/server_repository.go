@@ -1,7 +1,14 @@
 package server
 
 import (
+	"strings"
+	"time"
+
 	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
 	"github.com/passwall/passwall-server/model"
 )
 
@@ -15,55 +22,137 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "servers")
+}
+
 // All ...
 func (p *Repository) All(schema string) ([]model.Server, error) {
 	servers := []model.Server{}
-	err := p.db.Table(schema + ".servers").Find(&servers).Error
+	err := p.db.Table(p.table(schema)).Find(&servers).Error
 	return servers, err
 }
 
 // FindAll ...
 func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.Server, error) {
 	servers := []model.Server{}
+	query := pagination.Apply(p.filter(argsStr, schema), argsStr, argsInt)
+	err := query.Find(&servers).Error
+	return servers, err
+}
 
-	query := p.db
-	query = query.Table(schema + ".servers")
-	query = query.Limit(argsInt["limit"])
-	if argsInt["limit"] > 0 {
-		// offset can't be declared without a valid limit
-		query = query.Offset(argsInt["offset"])
-	}
+// Count returns the number of entities matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string, schema string) (int, error) {
+	count := 0
+	err := p.filter(argsStr, schema).Model(&model.Server{}).Count(&count).Error
+	return count, err
+}
 
-	query = query.Order(argsStr["order"])
+// filter returns the query of the entities matching the search and the filters
+func (p *Repository) filter(argsStr map[string]string, schema string) *gorm.DB {
+	query := p.db.Table(p.table(schema))
 
 	if argsStr["search"] != "" {
-		query = query.Where("title LIKE ? OR ip LIKE ?", "%"+argsStr["search"]+"%", "%"+argsStr["search"]+"%")
+		condition, values := search.Condition(argsStr, "title", "url")
+		query = query.Where(condition, values...)
 	}
 
-	err := query.Find(&servers).Error
-	return servers, err
+	if argsStr["favorite"] != "" {
+		query = query.Where("favorite = ?", argsStr["favorite"] == "true")
+	}
+
+	if argsStr["folder"] == "0" {
+		query = query.Where("folder_id IS NULL")
+	} else if argsStr["folder"] != "" {
+		query = query.Where("folder_id = ?", argsStr["folder"])
+	}
+
+	if argsStr["tags"] != "" {
+		tagged := p.db.Table(dialect.Table(p.db, schema, "item_tags")).Select("item_id").
+			Where("item_type = ? AND tag_id IN (?)", model.ServerItem, strings.Split(argsStr["tags"], ",")).
+			SubQuery()
+		query = query.Where("id IN ?", tagged)
+	}
+
+	return query
 }
 
 // FindByID ...
 func (p *Repository) FindByID(id uint, schema string) (*model.Server, error) {
 	server := new(model.Server)
-	err := p.db.Table(schema+".servers").Where(`id = ?`, id).First(&server).Error
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&server).Error
 	return server, err
 }
 
 // Save ...
 func (p *Repository) Save(server *model.Server, schema string) (*model.Server, error) {
-	err := p.db.Table(schema + ".servers").Save(&server).Error
+	err := p.db.Unscoped().Table(p.table(schema)).Save(&server).Error
+	return server, err
+}
+
+// Update ...
+func (p *Repository) Update(server *model.Server, revision uint, schema string) (*model.Server, error) {
+	err := update.Revision(p.db, p.table(schema), server, revision)
+	return server, err
+}
+
+// SaveEncrypted ...
+func (p *Repository) SaveEncrypted(server *model.Server, schema string) (*model.Server, error) {
+	err := update.Encrypted(p.db, p.table(schema), server)
 	return server, err
 }
 
 // Delete ...
 func (p *Repository) Delete(id uint, schema string) error {
-	err := p.db.Table(schema + ".servers").Delete(&model.Server{ID: id}).Error
+	err := p.db.Table(p.table(schema)).Delete(&model.Server{ID: id}).Error
 	return err
 }
 
+// FindAllDeleted ...
+func (p *Repository) FindAllDeleted(schema string) ([]model.Server, error) {
+	servers := []model.Server{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&servers).Error
+	return servers, err
+}
+
+// FindAllChanged ...
+func (p *Repository) FindAllChanged(since time.Time, schema string) ([]model.Server, error) {
+	servers := []model.Server{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("updated_at > ? OR deleted_at > ?", since, since).Order("id").Find(&servers).Error
+	return servers, err
+}
+
+// Restore ...
+func (p *Repository) Restore(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge ...
+func (p *Repository) Purge(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.Server{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedBefore ...
+func (p *Repository) PurgeDeletedBefore(t time.Time, schema string) error {
+	return p.db.Unscoped().Table(p.table(schema)).Where("deleted_at < ?", t).Delete(&model.Server{}).Error
+}
+
 // Migrate ...
 func (p *Repository) Migrate(schema string) error {
-	return p.db.Table(schema + ".servers").AutoMigrate(&model.Server{}).Error
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Server{}).Error
 }
@@ -0,0 +1,158 @@
+package sshkey
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/internal/storage/search"
+	"github.com/passwall/passwall-server/internal/storage/update"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Repository ...
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "ssh_keys")
+}
+
+// All ...
+func (p *Repository) All(schema string) ([]model.SSHKey, error) {
+	sshKeys := []model.SSHKey{}
+	err := p.db.Table(p.table(schema)).Find(&sshKeys).Error
+	return sshKeys, err
+}
+
+// FindAll ...
+func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int, schema string) ([]model.SSHKey, error) {
+	sshKeys := []model.SSHKey{}
+	query := pagination.Apply(p.filter(argsStr, schema), argsStr, argsInt)
+	err := query.Find(&sshKeys).Error
+	return sshKeys, err
+}
+
+// Count returns the number of entities matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string, schema string) (int, error) {
+	count := 0
+	err := p.filter(argsStr, schema).Model(&model.SSHKey{}).Count(&count).Error
+	return count, err
+}
+
+// filter returns the query of the entities matching the search and the filters
+func (p *Repository) filter(argsStr map[string]string, schema string) *gorm.DB {
+	query := p.db.Table(p.table(schema))
+
+	if argsStr["search"] != "" {
+		condition, values := search.Condition(argsStr, "title", "fingerprint")
+		query = query.Where(condition, values...)
+	}
+
+	if argsStr["favorite"] != "" {
+		query = query.Where("favorite = ?", argsStr["favorite"] == "true")
+	}
+
+	if argsStr["folder"] == "0" {
+		query = query.Where("folder_id IS NULL")
+	} else if argsStr["folder"] != "" {
+		query = query.Where("folder_id = ?", argsStr["folder"])
+	}
+
+	if argsStr["tags"] != "" {
+		tagged := p.db.Table(dialect.Table(p.db, schema, "item_tags")).Select("item_id").
+			Where("item_type = ? AND tag_id IN (?)", model.SSHKeyItem, strings.Split(argsStr["tags"], ",")).
+			SubQuery()
+		query = query.Where("id IN ?", tagged)
+	}
+
+	return query
+}
+
+// FindByID ...
+func (p *Repository) FindByID(id uint, schema string) (*model.SSHKey, error) {
+	sshKey := new(model.SSHKey)
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&sshKey).Error
+	return sshKey, err
+}
+
+// Save ...
+func (p *Repository) Save(sshKey *model.SSHKey, schema string) (*model.SSHKey, error) {
+	err := p.db.Unscoped().Table(p.table(schema)).Save(&sshKey).Error
+	return sshKey, err
+}
+
+// Update ...
+func (p *Repository) Update(sshKey *model.SSHKey, revision uint, schema string) (*model.SSHKey, error) {
+	err := update.Revision(p.db, p.table(schema), sshKey, revision)
+	return sshKey, err
+}
+
+// SaveEncrypted ...
+func (p *Repository) SaveEncrypted(sshKey *model.SSHKey, schema string) (*model.SSHKey, error) {
+	err := update.Encrypted(p.db, p.table(schema), sshKey)
+	return sshKey, err
+}
+
+// Delete ...
+func (p *Repository) Delete(id uint, schema string) error {
+	err := p.db.Table(p.table(schema)).Delete(&model.SSHKey{ID: id}).Error
+	return err
+}
+
+// FindAllDeleted ...
+func (p *Repository) FindAllDeleted(schema string) ([]model.SSHKey, error) {
+	sshKeys := []model.SSHKey{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&sshKeys).Error
+	return sshKeys, err
+}
+
+// FindAllChanged ...
+func (p *Repository) FindAllChanged(since time.Time, schema string) ([]model.SSHKey, error) {
+	sshKeys := []model.SSHKey{}
+	err := p.db.Unscoped().Table(p.table(schema)).Where("updated_at > ? OR deleted_at > ?", since, since).Order("id").Find(&sshKeys).Error
+	return sshKeys, err
+}
+
+// Restore ...
+func (p *Repository) Restore(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge ...
+func (p *Repository) Purge(id uint, schema string) error {
+	query := p.db.Unscoped().Table(p.table(schema)).Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&model.SSHKey{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedBefore ...
+func (p *Repository) PurgeDeletedBefore(t time.Time, schema string) error {
+	return p.db.Unscoped().Table(p.table(schema)).Where("deleted_at < ?", t).Delete(&model.SSHKey{}).Error
+}
+
+// Migrate ...
+func (p *Repository) Migrate(schema string) error {
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.SSHKey{}).Error
+}
@@ -1,5 +1,15 @@
 package storage
 
+import (
+	"github.com/passwall/passwall-server/internal/storage/blob"
+	"github.com/passwall/passwall-server/internal/storage/event"
+	"github.com/passwall/passwall-server/internal/storage/update"
+)
+
+// ErrRevisionChanged is returned by the Update of the item repositories when
+// another update moved the item past the revision it was read at
+var ErrRevisionChanged = update.ErrRevisionChanged
+
 // Store is the minimal interface for the various repositories
 type Store interface {
 	Logins() LoginRepository
@@ -10,6 +20,27 @@ type Store interface {
 	Tokens() TokenRepository
 	Users() UserRepository
 	Servers() ServerRepository
+	Identities() IdentityRepository
+	LicenseKeys() LicenseKeyRepository
+	CustomItems() CustomItemRepository
+	SSHKeys() SSHKeyRepository
+	Templates() TemplateRepository
+	Attachments() AttachmentRepository
+	Revisions() RevisionRepository
+	Tombstones() TombstoneRepository
+	Folders() FolderRepository
+	Tags() TagRepository
 	Subscriptions() SubscriptionRepository
+	Migrations() MigrationRepository
+	// Blobs returns the store of the attachment contents. Blobs are not
+	// part of transactions, they are written before their attachments are saved.
+	Blobs() blob.Store
+	// Events returns the broker of the item events. The events published in
+	// a transaction are delivered once it is committed.
+	Events() event.Broker
+	// Transaction runs fn with a store whose changes are committed when fn
+	// succeeds and rolled back when it returns an error. Transactions
+	// started by fn on the store join the running one.
+	Transaction(fn func(tx Store) error) error
 	Ping() error
 }
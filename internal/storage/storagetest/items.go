@@ -0,0 +1,1006 @@
+package storagetest
+
+import (
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/model"
+)
+
+// items adapts an item repository to the behaviour shared by every item type.
+// Items are created with a title and a value which is stored in an encrypted
+// column and stands for its blind index in the search index.
+type items struct {
+	itemType    string
+	titleColumn string
+	migrate     func(schema string) error
+	create      func(title, search, schema string) (uint, error)
+	find        func(id uint, schema string) (string, error)
+	all         func(schema string) ([]string, error)
+	findAll     func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error)
+	count       func(argsStr map[string]string, schema string) (int, error)
+	cursor      func(id uint, schema string) (string, error)
+	revision    func(id uint, schema string) (uint, error)
+	update      func(id uint, title, schema string) error
+	updateAt    func(id uint, title string, revision uint, schema string) (uint, error)
+	favorite    func(id uint, schema string) error
+	folder      func(id, folderID uint, schema string) error
+	delete      func(id uint, schema string) error
+	deleted     func(schema string) ([]string, error)
+	saveDeleted func(title, schema string) error
+	changed     func(since time.Time, schema string) ([]string, error)
+	restore     func(id uint, schema string) error
+	purge       func(id uint, schema string) error
+	purgeBefore func(t time.Time, schema string) error
+}
+
+func logins(s storage.Store) *items {
+	titles := func(logins []model.Login, err error) ([]string, error) {
+		titles := []string{}
+		for i := range logins {
+			titles = append(titles, logins[i].Title)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.LoginItem,
+		titleColumn: "title",
+		migrate:     s.Logins().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			login, err := s.Logins().Save(&model.Login{Title: title, URL: search, SearchIndex: " " + search + " "}, schema)
+			return login.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			login, err := s.Logins().FindByID(id, schema)
+			return login.Title, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.Logins().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.Logins().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.Logins().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			login, err := s.Logins().FindByID(id, schema)
+			return pagination.Encode(login.UpdatedAt, login.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			login, err := s.Logins().FindByID(id, schema)
+			return login.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			login, err := s.Logins().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			login.Title = title
+			_, err = s.Logins().Save(login, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			login, err := s.Logins().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			login.Title = title
+			login, err = s.Logins().Update(login, revision, schema)
+			return login.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			login, err := s.Logins().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			login.Favorite = true
+			_, err = s.Logins().Save(login, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			login, err := s.Logins().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			login.FolderID = &folderID
+			_, err = s.Logins().Save(login, schema)
+			return err
+		},
+		delete: s.Logins().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.Logins().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.Logins().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			login := deleted[0]
+			login.Title = title
+			_, err = s.Logins().Save(&login, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.Logins().FindAllChanged(since, schema))
+		},
+		restore:     s.Logins().Restore,
+		purge:       s.Logins().Purge,
+		purgeBefore: s.Logins().PurgeDeletedBefore,
+	}
+}
+
+func creditCards(s storage.Store) *items {
+	titles := func(cards []model.CreditCard, err error) ([]string, error) {
+		titles := []string{}
+		for i := range cards {
+			titles = append(titles, cards[i].CardName)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.CreditCardItem,
+		titleColumn: "card_name",
+		migrate:     s.CreditCards().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			card, err := s.CreditCards().Save(&model.CreditCard{CardName: title, CardholderName: search, SearchIndex: " " + search + " "}, schema)
+			return card.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			card, err := s.CreditCards().FindByID(id, schema)
+			return card.CardName, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.CreditCards().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.CreditCards().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.CreditCards().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			card, err := s.CreditCards().FindByID(id, schema)
+			return pagination.Encode(card.UpdatedAt, card.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			card, err := s.CreditCards().FindByID(id, schema)
+			return card.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			card, err := s.CreditCards().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			card.CardName = title
+			_, err = s.CreditCards().Save(card, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			card, err := s.CreditCards().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			card.CardName = title
+			card, err = s.CreditCards().Update(card, revision, schema)
+			return card.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			card, err := s.CreditCards().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			card.Favorite = true
+			_, err = s.CreditCards().Save(card, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			card, err := s.CreditCards().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			card.FolderID = &folderID
+			_, err = s.CreditCards().Save(card, schema)
+			return err
+		},
+		delete: s.CreditCards().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.CreditCards().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.CreditCards().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			card := deleted[0]
+			card.CardName = title
+			_, err = s.CreditCards().Save(&card, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.CreditCards().FindAllChanged(since, schema))
+		},
+		restore:     s.CreditCards().Restore,
+		purge:       s.CreditCards().Purge,
+		purgeBefore: s.CreditCards().PurgeDeletedBefore,
+	}
+}
+
+func bankAccounts(s storage.Store) *items {
+	titles := func(accounts []model.BankAccount, err error) ([]string, error) {
+		titles := []string{}
+		for i := range accounts {
+			titles = append(titles, accounts[i].BankName)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.BankAccountItem,
+		titleColumn: "bank_name",
+		migrate:     s.BankAccounts().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			account, err := s.BankAccounts().Save(&model.BankAccount{BankName: title, AccountName: search, SearchIndex: " " + search + " "}, schema)
+			return account.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			account, err := s.BankAccounts().FindByID(id, schema)
+			return account.BankName, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.BankAccounts().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.BankAccounts().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.BankAccounts().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			account, err := s.BankAccounts().FindByID(id, schema)
+			return pagination.Encode(account.UpdatedAt, account.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			account, err := s.BankAccounts().FindByID(id, schema)
+			return account.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			account, err := s.BankAccounts().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			account.BankName = title
+			_, err = s.BankAccounts().Save(account, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			account, err := s.BankAccounts().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			account.BankName = title
+			account, err = s.BankAccounts().Update(account, revision, schema)
+			return account.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			account, err := s.BankAccounts().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			account.Favorite = true
+			_, err = s.BankAccounts().Save(account, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			account, err := s.BankAccounts().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			account.FolderID = &folderID
+			_, err = s.BankAccounts().Save(account, schema)
+			return err
+		},
+		delete: s.BankAccounts().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.BankAccounts().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.BankAccounts().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			account := deleted[0]
+			account.BankName = title
+			_, err = s.BankAccounts().Save(&account, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.BankAccounts().FindAllChanged(since, schema))
+		},
+		restore:     s.BankAccounts().Restore,
+		purge:       s.BankAccounts().Purge,
+		purgeBefore: s.BankAccounts().PurgeDeletedBefore,
+	}
+}
+
+func notes(s storage.Store) *items {
+	titles := func(notes []model.Note, err error) ([]string, error) {
+		titles := []string{}
+		for i := range notes {
+			titles = append(titles, notes[i].Title)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.NoteItem,
+		titleColumn: "title",
+		migrate:     s.Notes().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			note, err := s.Notes().Save(&model.Note{Title: title, Note: search, SearchIndex: " " + search + " "}, schema)
+			return note.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			note, err := s.Notes().FindByID(id, schema)
+			return note.Title, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.Notes().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.Notes().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.Notes().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			note, err := s.Notes().FindByID(id, schema)
+			return pagination.Encode(note.UpdatedAt, note.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			note, err := s.Notes().FindByID(id, schema)
+			return note.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			note, err := s.Notes().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			note.Title = title
+			_, err = s.Notes().Save(note, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			note, err := s.Notes().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			note.Title = title
+			note, err = s.Notes().Update(note, revision, schema)
+			return note.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			note, err := s.Notes().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			note.Favorite = true
+			_, err = s.Notes().Save(note, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			note, err := s.Notes().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			note.FolderID = &folderID
+			_, err = s.Notes().Save(note, schema)
+			return err
+		},
+		delete: s.Notes().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.Notes().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.Notes().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			note := deleted[0]
+			note.Title = title
+			_, err = s.Notes().Save(&note, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.Notes().FindAllChanged(since, schema))
+		},
+		restore:     s.Notes().Restore,
+		purge:       s.Notes().Purge,
+		purgeBefore: s.Notes().PurgeDeletedBefore,
+	}
+}
+
+func emails(s storage.Store) *items {
+	titles := func(emails []model.Email, err error) ([]string, error) {
+		titles := []string{}
+		for i := range emails {
+			titles = append(titles, emails[i].Title)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.EmailItem,
+		titleColumn: "title",
+		migrate:     s.Emails().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			email, err := s.Emails().Save(&model.Email{Title: title, Email: search, SearchIndex: " " + search + " "}, schema)
+			return email.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			email, err := s.Emails().FindByID(id, schema)
+			return email.Title, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.Emails().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.Emails().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.Emails().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			email, err := s.Emails().FindByID(id, schema)
+			return pagination.Encode(email.UpdatedAt, email.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			email, err := s.Emails().FindByID(id, schema)
+			return email.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			email, err := s.Emails().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			email.Title = title
+			_, err = s.Emails().Save(email, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			email, err := s.Emails().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			email.Title = title
+			email, err = s.Emails().Update(email, revision, schema)
+			return email.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			email, err := s.Emails().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			email.Favorite = true
+			_, err = s.Emails().Save(email, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			email, err := s.Emails().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			email.FolderID = &folderID
+			_, err = s.Emails().Save(email, schema)
+			return err
+		},
+		delete: s.Emails().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.Emails().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.Emails().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			email := deleted[0]
+			email.Title = title
+			_, err = s.Emails().Save(&email, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.Emails().FindAllChanged(since, schema))
+		},
+		restore:     s.Emails().Restore,
+		purge:       s.Emails().Purge,
+		purgeBefore: s.Emails().PurgeDeletedBefore,
+	}
+}
+
+func identities(s storage.Store) *items {
+	titles := func(identities []model.Identity, err error) ([]string, error) {
+		titles := []string{}
+		for i := range identities {
+			titles = append(titles, identities[i].Title)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.IdentityItem,
+		titleColumn: "title",
+		migrate:     s.Identities().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			identity, err := s.Identities().Save(&model.Identity{Title: title, Email: search, SearchIndex: " " + search + " "}, schema)
+			return identity.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			identity, err := s.Identities().FindByID(id, schema)
+			return identity.Title, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.Identities().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.Identities().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.Identities().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			identity, err := s.Identities().FindByID(id, schema)
+			return pagination.Encode(identity.UpdatedAt, identity.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			identity, err := s.Identities().FindByID(id, schema)
+			return identity.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			identity, err := s.Identities().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			identity.Title = title
+			_, err = s.Identities().Save(identity, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			identity, err := s.Identities().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			identity.Title = title
+			identity, err = s.Identities().Update(identity, revision, schema)
+			return identity.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			identity, err := s.Identities().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			identity.Favorite = true
+			_, err = s.Identities().Save(identity, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			identity, err := s.Identities().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			identity.FolderID = &folderID
+			_, err = s.Identities().Save(identity, schema)
+			return err
+		},
+		delete: s.Identities().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.Identities().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.Identities().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			identity := deleted[0]
+			identity.Title = title
+			_, err = s.Identities().Save(&identity, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.Identities().FindAllChanged(since, schema))
+		},
+		restore:     s.Identities().Restore,
+		purge:       s.Identities().Purge,
+		purgeBefore: s.Identities().PurgeDeletedBefore,
+	}
+}
+
+func licenseKeys(s storage.Store) *items {
+	titles := func(licenseKeys []model.LicenseKey, err error) ([]string, error) {
+		titles := []string{}
+		for i := range licenseKeys {
+			titles = append(titles, licenseKeys[i].Product)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.LicenseKeyItem,
+		titleColumn: "product",
+		migrate:     s.LicenseKeys().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			licenseKey, err := s.LicenseKeys().Save(&model.LicenseKey{Product: title, Licensee: search, SearchIndex: " " + search + " "}, schema)
+			return licenseKey.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			licenseKey, err := s.LicenseKeys().FindByID(id, schema)
+			return licenseKey.Product, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.LicenseKeys().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.LicenseKeys().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.LicenseKeys().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			licenseKey, err := s.LicenseKeys().FindByID(id, schema)
+			return pagination.Encode(licenseKey.UpdatedAt, licenseKey.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			licenseKey, err := s.LicenseKeys().FindByID(id, schema)
+			return licenseKey.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			licenseKey, err := s.LicenseKeys().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			licenseKey.Product = title
+			_, err = s.LicenseKeys().Save(licenseKey, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			licenseKey, err := s.LicenseKeys().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			licenseKey.Product = title
+			licenseKey, err = s.LicenseKeys().Update(licenseKey, revision, schema)
+			return licenseKey.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			licenseKey, err := s.LicenseKeys().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			licenseKey.Favorite = true
+			_, err = s.LicenseKeys().Save(licenseKey, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			licenseKey, err := s.LicenseKeys().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			licenseKey.FolderID = &folderID
+			_, err = s.LicenseKeys().Save(licenseKey, schema)
+			return err
+		},
+		delete: s.LicenseKeys().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.LicenseKeys().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.LicenseKeys().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			licenseKey := deleted[0]
+			licenseKey.Product = title
+			_, err = s.LicenseKeys().Save(&licenseKey, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.LicenseKeys().FindAllChanged(since, schema))
+		},
+		restore:     s.LicenseKeys().Restore,
+		purge:       s.LicenseKeys().Purge,
+		purgeBefore: s.LicenseKeys().PurgeDeletedBefore,
+	}
+}
+
+func customItems(s storage.Store) *items {
+	titles := func(customItems []model.CustomItem, err error) ([]string, error) {
+		titles := []string{}
+		for i := range customItems {
+			titles = append(titles, customItems[i].Title)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.CustomItemItem,
+		titleColumn: "title",
+		migrate:     s.CustomItems().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			customItem, err := s.CustomItems().Save(&model.CustomItem{Type: "test", Title: title, SearchIndex: " " + search + " "}, schema)
+			return customItem.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			customItem, err := s.CustomItems().FindByID(id, schema)
+			return customItem.Title, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.CustomItems().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.CustomItems().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.CustomItems().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			customItem, err := s.CustomItems().FindByID(id, schema)
+			return pagination.Encode(customItem.UpdatedAt, customItem.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			customItem, err := s.CustomItems().FindByID(id, schema)
+			return customItem.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			customItem, err := s.CustomItems().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			customItem.Title = title
+			_, err = s.CustomItems().Save(customItem, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			customItem, err := s.CustomItems().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			customItem.Title = title
+			customItem, err = s.CustomItems().Update(customItem, revision, schema)
+			return customItem.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			customItem, err := s.CustomItems().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			customItem.Favorite = true
+			_, err = s.CustomItems().Save(customItem, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			customItem, err := s.CustomItems().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			customItem.FolderID = &folderID
+			_, err = s.CustomItems().Save(customItem, schema)
+			return err
+		},
+		delete: s.CustomItems().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.CustomItems().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.CustomItems().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			customItem := deleted[0]
+			customItem.Title = title
+			_, err = s.CustomItems().Save(&customItem, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.CustomItems().FindAllChanged(since, schema))
+		},
+		restore:     s.CustomItems().Restore,
+		purge:       s.CustomItems().Purge,
+		purgeBefore: s.CustomItems().PurgeDeletedBefore,
+	}
+}
+
+func servers(s storage.Store) *items {
+	titles := func(servers []model.Server, err error) ([]string, error) {
+		titles := []string{}
+		for i := range servers {
+			titles = append(titles, servers[i].Title)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.ServerItem,
+		titleColumn: "title",
+		migrate:     s.Servers().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			server, err := s.Servers().Save(&model.Server{Title: title, IP: search, SearchIndex: " " + search + " "}, schema)
+			return server.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			server, err := s.Servers().FindByID(id, schema)
+			return server.Title, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.Servers().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.Servers().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.Servers().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			server, err := s.Servers().FindByID(id, schema)
+			return pagination.Encode(server.UpdatedAt, server.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			server, err := s.Servers().FindByID(id, schema)
+			return server.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			server, err := s.Servers().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			server.Title = title
+			_, err = s.Servers().Save(server, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			server, err := s.Servers().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			server.Title = title
+			server, err = s.Servers().Update(server, revision, schema)
+			return server.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			server, err := s.Servers().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			server.Favorite = true
+			_, err = s.Servers().Save(server, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			server, err := s.Servers().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			server.FolderID = &folderID
+			_, err = s.Servers().Save(server, schema)
+			return err
+		},
+		delete: s.Servers().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.Servers().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.Servers().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			server := deleted[0]
+			server.Title = title
+			_, err = s.Servers().Save(&server, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.Servers().FindAllChanged(since, schema))
+		},
+		restore:     s.Servers().Restore,
+		purge:       s.Servers().Purge,
+		purgeBefore: s.Servers().PurgeDeletedBefore,
+	}
+}
+
+func sshKeys(s storage.Store) *items {
+	titles := func(sshKeys []model.SSHKey, err error) ([]string, error) {
+		titles := []string{}
+		for i := range sshKeys {
+			titles = append(titles, sshKeys[i].Title)
+		}
+		return titles, err
+	}
+
+	return &items{
+		itemType:    model.SSHKeyItem,
+		titleColumn: "title",
+		migrate:     s.SSHKeys().Migrate,
+		create: func(title, search, schema string) (uint, error) {
+			sshKey, err := s.SSHKeys().Save(&model.SSHKey{Title: title, Comment: search, SearchIndex: " " + search + " "}, schema)
+			return sshKey.ID, err
+		},
+		find: func(id uint, schema string) (string, error) {
+			sshKey, err := s.SSHKeys().FindByID(id, schema)
+			return sshKey.Title, err
+		},
+		all: func(schema string) ([]string, error) {
+			return titles(s.SSHKeys().All(schema))
+		},
+		findAll: func(argsStr map[string]string, argsInt map[string]int, schema string) ([]string, error) {
+			return titles(s.SSHKeys().FindAll(argsStr, argsInt, schema))
+		},
+		count: func(argsStr map[string]string, schema string) (int, error) {
+			return s.SSHKeys().Count(argsStr, schema)
+		},
+		cursor: func(id uint, schema string) (string, error) {
+			sshKey, err := s.SSHKeys().FindByID(id, schema)
+			return pagination.Encode(sshKey.UpdatedAt, sshKey.ID), err
+		},
+		revision: func(id uint, schema string) (uint, error) {
+			sshKey, err := s.SSHKeys().FindByID(id, schema)
+			return sshKey.Revision, err
+		},
+		update: func(id uint, title, schema string) error {
+			sshKey, err := s.SSHKeys().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			sshKey.Title = title
+			_, err = s.SSHKeys().Save(sshKey, schema)
+			return err
+		},
+		updateAt: func(id uint, title string, revision uint, schema string) (uint, error) {
+			sshKey, err := s.SSHKeys().FindByID(id, schema)
+			if err != nil {
+				return 0, err
+			}
+			sshKey.Title = title
+			sshKey, err = s.SSHKeys().Update(sshKey, revision, schema)
+			return sshKey.Revision, err
+		},
+		favorite: func(id uint, schema string) error {
+			sshKey, err := s.SSHKeys().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			sshKey.Favorite = true
+			_, err = s.SSHKeys().Save(sshKey, schema)
+			return err
+		},
+		folder: func(id, folderID uint, schema string) error {
+			sshKey, err := s.SSHKeys().FindByID(id, schema)
+			if err != nil {
+				return err
+			}
+			sshKey.FolderID = &folderID
+			_, err = s.SSHKeys().Save(sshKey, schema)
+			return err
+		},
+		delete: s.SSHKeys().Delete,
+		deleted: func(schema string) ([]string, error) {
+			return titles(s.SSHKeys().FindAllDeleted(schema))
+		},
+		saveDeleted: func(title, schema string) error {
+			deleted, err := s.SSHKeys().FindAllDeleted(schema)
+			if err != nil {
+				return err
+			}
+			sshKey := deleted[0]
+			sshKey.Title = title
+			_, err = s.SSHKeys().Save(&sshKey, schema)
+			return err
+		},
+		changed: func(since time.Time, schema string) ([]string, error) {
+			return titles(s.SSHKeys().FindAllChanged(since, schema))
+		},
+		restore:     s.SSHKeys().Restore,
+		purge:       s.SSHKeys().Purge,
+		purgeBefore: s.SSHKeys().PurgeDeletedBefore,
+	}
+}
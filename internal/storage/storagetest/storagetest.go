@@ -0,0 +1,888 @@
+// Package storagetest contains the behavioural tests every storage.Store
+// implementation has to pass. Backends run them from their own tests:
+//
+//	func TestStore(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) storage.Store {
+//			return memory.New()
+//		})
+//	}
+package storagetest
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/passwall/passwall-server/internal/storage"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
+	"github.com/passwall/passwall-server/model"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// NewStore returns the store the tests run against. Tests use unique
+// emails and schemas, so the store doesn't need to be empty.
+type NewStore func(t *testing.T) storage.Store
+
+// Run runs the repository contract tests against the stores created by newStore
+func Run(t *testing.T, newStore NewStore) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s storage.Store)
+	}{
+		{name: "Ping", run: testPing},
+		{name: "Users", run: testUsers},
+		{name: "Tokens", run: testTokens},
+		{name: "Subscriptions", run: testSubscriptions},
+		{name: "Logins", run: func(t *testing.T, s storage.Store) { testItems(t, s, logins(s)) }},
+		{name: "CreditCards", run: func(t *testing.T, s storage.Store) { testItems(t, s, creditCards(s)) }},
+		{name: "BankAccounts", run: func(t *testing.T, s storage.Store) { testItems(t, s, bankAccounts(s)) }},
+		{name: "Notes", run: func(t *testing.T, s storage.Store) { testItems(t, s, notes(s)) }},
+		{name: "Emails", run: func(t *testing.T, s storage.Store) { testItems(t, s, emails(s)) }},
+		{name: "Servers", run: func(t *testing.T, s storage.Store) { testItems(t, s, servers(s)) }},
+		{name: "Identities", run: func(t *testing.T, s storage.Store) { testItems(t, s, identities(s)) }},
+		{name: "LicenseKeys", run: func(t *testing.T, s storage.Store) { testItems(t, s, licenseKeys(s)) }},
+		{name: "LicenseKeyExpiry", run: testLicenseKeyExpiry},
+		{name: "CustomFields", run: testCustomFields},
+		{name: "SaveEncrypted", run: testSaveEncrypted},
+		{name: "CustomItems", run: func(t *testing.T, s storage.Store) { testItems(t, s, customItems(s)) }},
+		{name: "SSHKeys", run: func(t *testing.T, s storage.Store) { testItems(t, s, sshKeys(s)) }},
+		{name: "Templates", run: testTemplates},
+		{name: "Attachments", run: testAttachments},
+		{name: "Folders", run: testFolders},
+		{name: "Tags", run: testTags},
+		{name: "Revisions", run: testRevisions},
+		{name: "Tombstones", run: testTombstones},
+		{name: "Transaction", run: testTransaction},
+		{name: "SchemaIsolation", run: testSchemaIsolation},
+		{name: "MigrationLock", run: testMigrationLock},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStore(t)
+			migrateSystemTables(t, s)
+			tt.run(t, s)
+		})
+	}
+}
+
+var counter int64
+
+// unique returns a value which is not used by any other test
+func unique(prefix string) string {
+	return fmt.Sprintf("%s%d%d", prefix, time.Now().UnixNano(), atomic.AddInt64(&counter, 1))
+}
+
+func migrateSystemTables(t *testing.T, s storage.Store) {
+	require.Nil(t, s.Tokens().Migrate())
+	require.Nil(t, s.Users().Migrate())
+	require.Nil(t, s.Subscriptions().Migrate())
+}
+
+// createUser creates a user with its schema and tables,
+// the user is deleted with its schema when the test ends
+func createUser(t *testing.T, s storage.Store) *model.User {
+	user, err := s.Users().Save(&model.User{
+		UUID:           uuid.NewV4(),
+		Name:           "Contract Test",
+		Email:          unique("contract") + "@passwall.io",
+		MasterPassword: hash(t, "dummypassword"),
+		Role:           "Member",
+	})
+	require.Nil(t, err)
+
+	user.Schema = fmt.Sprintf("user%d", user.ID)
+	user, err = s.Users().Save(user)
+	require.Nil(t, err)
+
+	require.Nil(t, s.Users().CreateSchema(user.Schema))
+	for _, items := range []*items{logins(s), creditCards(s), bankAccounts(s), notes(s), emails(s), servers(s), identities(s), licenseKeys(s), customItems(s), sshKeys(s)} {
+		require.Nil(t, items.migrate(user.Schema))
+	}
+	require.Nil(t, s.Templates().Migrate(user.Schema))
+	require.Nil(t, s.Attachments().Migrate(user.Schema))
+	require.Nil(t, s.Revisions().Migrate(user.Schema))
+	require.Nil(t, s.Tombstones().Migrate(user.Schema))
+	require.Nil(t, s.Folders().Migrate(user.Schema))
+	require.Nil(t, s.Tags().Migrate(user.Schema))
+
+	t.Cleanup(func() {
+		s.Users().Delete(user.ID, user.Schema)
+	})
+	return user
+}
+
+func hash(t *testing.T, password string) string {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.Nil(t, err)
+	return string(hashed)
+}
+
+func testPing(t *testing.T, s storage.Store) {
+	assert.Nil(t, s.Ping())
+}
+
+func testUsers(t *testing.T, s storage.Store) {
+	user := createUser(t, s)
+	assert.NotZero(t, user.ID)
+	assert.False(t, user.CreatedAt.IsZero())
+
+	found, err := s.Users().FindByID(user.ID)
+	require.Nil(t, err)
+	assert.Equal(t, user.Email, found.Email)
+	assert.Equal(t, user.Schema, found.Schema)
+
+	found, err = s.Users().FindByUUID(user.UUID.String())
+	require.Nil(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	found, err = s.Users().FindByEmail(user.Email)
+	require.Nil(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	_, err = s.Users().FindByEmail(unique("missing") + "@passwall.io")
+	assert.NotNil(t, err)
+
+	found, err = s.Users().FindByCredentials(user.Email, "dummypassword")
+	require.Nil(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	_, err = s.Users().FindByCredentials(user.Email, "wrongpassword")
+	assert.NotNil(t, err)
+
+	found.Name = "Updated Name"
+	_, err = s.Users().Save(found)
+	require.Nil(t, err)
+	found, err = s.Users().FindByID(user.ID)
+	require.Nil(t, err)
+	assert.Equal(t, "Updated Name", found.Name)
+
+	all, err := s.Users().All()
+	require.Nil(t, err)
+	assert.Contains(t, emailsOf(all), user.Email)
+
+	argsStr := map[string]string{"order": pagination.Order}
+	users, err := s.Users().FindAll(argsStr, map[string]int{"limit": -1, "offset": -1})
+	require.Nil(t, err)
+	assert.Equal(t, emailsOf(all), emailsOf(users))
+	count, err := s.Users().Count(argsStr)
+	require.Nil(t, err)
+	assert.Equal(t, len(all), count)
+
+	require.Nil(t, s.Users().Delete(user.ID, user.Schema))
+	_, err = s.Users().FindByID(user.ID)
+	assert.NotNil(t, err)
+
+	all, err = s.Users().All()
+	require.Nil(t, err)
+	assert.NotContains(t, emailsOf(all), user.Email)
+}
+
+func emailsOf(users []model.User) []string {
+	emails := make([]string, len(users))
+	for i := range users {
+		emails[i] = users[i].Email
+	}
+	return emails
+}
+
+func testTokens(t *testing.T, s storage.Store) {
+	userID := int(time.Now().UnixNano() % 1000000000)
+	first, second := uuid.NewV4(), uuid.NewV4()
+	expiry := time.Now().Add(time.Hour)
+
+	s.Tokens().Save(userID, first, "access-token", expiry, "transmission-key")
+	s.Tokens().Save(userID, second, "refresh-token", expiry, "")
+
+	token, ok := s.Tokens().Any(first.String())
+	require.True(t, ok)
+	assert.Equal(t, userID, token.UserID)
+	assert.Equal(t, "access-token", token.Token)
+	assert.Equal(t, "transmission-key", token.TransmissionKey)
+
+	s.Tokens().DeleteByUUID(first.String())
+	_, ok = s.Tokens().Any(first.String())
+	assert.False(t, ok)
+	_, ok = s.Tokens().Any(second.String())
+	assert.True(t, ok)
+
+	s.Tokens().Delete(userID)
+	_, ok = s.Tokens().Any(second.String())
+	assert.False(t, ok)
+}
+
+func testSubscriptions(t *testing.T, s storage.Store) {
+	subscriptionID := int(time.Now().UnixNano() % 1000000000)
+	subscription, err := s.Subscriptions().Save(&model.Subscription{
+		Type:           "pro",
+		SubscriptionID: subscriptionID,
+		Email:          unique("subscription") + "@passwall.io",
+		Status:         "active",
+	})
+	require.Nil(t, err)
+	assert.NotZero(t, subscription.ID)
+
+	found, err := s.Subscriptions().FindByID(subscription.ID)
+	require.Nil(t, err)
+	assert.Equal(t, subscription.Email, found.Email)
+
+	found, err = s.Subscriptions().FindByEmail(subscription.Email)
+	require.Nil(t, err)
+	assert.Equal(t, subscription.ID, found.ID)
+
+	found, err = s.Subscriptions().FindBySubscriptionID(uint(subscriptionID))
+	require.Nil(t, err)
+	assert.Equal(t, subscription.ID, found.ID)
+
+	found.Status = "past_due"
+	_, err = s.Subscriptions().Save(found)
+	require.Nil(t, err)
+	found, err = s.Subscriptions().FindByID(subscription.ID)
+	require.Nil(t, err)
+	assert.Equal(t, "past_due", found.Status)
+
+	argsStr := map[string]string{"order": pagination.Order}
+	subscriptions, err := s.Subscriptions().FindAll(argsStr, map[string]int{"limit": 1, "offset": -1})
+	require.Nil(t, err)
+	require.Len(t, subscriptions, 1)
+	count, err := s.Subscriptions().Count(argsStr)
+	require.Nil(t, err)
+	assert.GreaterOrEqual(t, count, 1)
+
+	require.Nil(t, s.Subscriptions().Delete(subscription.ID))
+	_, err = s.Subscriptions().FindByEmail(subscription.Email)
+	assert.NotNil(t, err)
+}
+
+func testItems(t *testing.T, s storage.Store, items *items) {
+	schema := createUser(t, s).Schema
+
+	b, err := items.create("title-b", "needle-b", schema)
+	require.Nil(t, err)
+	a, err := items.create("title-a", "needle-a", schema)
+	require.Nil(t, err)
+	c, err := items.create("title-c", "other-c", schema)
+	require.Nil(t, err)
+	assert.NotEqual(t, a, b)
+
+	title, err := items.find(a, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "title-a", title)
+	revision, err := items.revision(a, schema)
+	require.Nil(t, err)
+	assert.Equal(t, uint(1), revision, "items should start at the first revision")
+
+	_, err = items.find(c+1000, schema)
+	assert.NotNil(t, err, "finding a missing item should fail")
+
+	all := map[string]int{"limit": -1, "offset": -1}
+	titles, err := items.findAll(map[string]string{"order": items.titleColumn + " asc"}, all, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-a", "title-b", "title-c"}, titles)
+
+	titles, err = items.findAll(map[string]string{"order": items.titleColumn + " desc"}, all, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-c", "title-b", "title-a"}, titles)
+
+	page := map[string]int{"limit": 2, "offset": 1}
+	titles, err = items.findAll(map[string]string{"order": items.titleColumn + " asc"}, page, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-b", "title-c"}, titles)
+
+	offset := map[string]int{"limit": -1, "offset": 2}
+	titles, err = items.findAll(map[string]string{"order": items.titleColumn + " asc"}, offset, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-c"}, titles, "an offset should work without a limit")
+
+	testCursor(t, items, schema, a, c)
+
+	search := map[string]string{"order": items.titleColumn + " asc", "search": "title-b"}
+	titles, err = items.findAll(search, all, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-b"}, titles)
+	count, err := items.count(search, schema)
+	require.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	// Encrypted columns are searched through the blind indexes only,
+	// the search itself is in no plain column
+	search = map[string]string{"order": items.titleColumn + " asc", "search": "secret", "search_exact": "needle-a"}
+	titles, err = items.findAll(search, all, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-a"}, titles)
+
+	search = map[string]string{"order": items.titleColumn + " asc", "search": "secret", "search_tokens": "needle-b"}
+	titles, err = items.findAll(search, all, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-b"}, titles)
+
+	search["search_tokens"] = "needle-b other-c"
+	titles, err = items.findAll(search, all, schema)
+	require.Nil(t, err)
+	assert.Empty(t, titles, "every word of the search should match")
+
+	require.Nil(t, items.favorite(c, schema))
+	favorite := map[string]string{"order": items.titleColumn + " asc", "favorite": "true"}
+	titles, err = items.findAll(favorite, all, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-c"}, titles)
+
+	favorite["favorite"], favorite["search"] = "false", "title-"
+	titles, err = items.findAll(favorite, all, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-a", "title-b"}, titles)
+
+	testFolderFilter(t, items, schema, a, b, c)
+	testTagFilter(t, s, items, schema, a, b, c)
+
+	since := time.Now()
+	require.Nil(t, items.update(b, "title-d", schema))
+	title, err = items.find(b, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "title-d", title)
+
+	// updates at a revision fail once another update moved the item past it
+	revision, err = items.revision(b, schema)
+	require.Nil(t, err)
+	next, err := items.updateAt(b, "title-d", revision, schema)
+	require.Nil(t, err)
+	assert.Equal(t, revision+1, next)
+	_, err = items.updateAt(b, "title-e", revision, schema)
+	assert.Equal(t, storage.ErrRevisionChanged, err)
+	next, err = items.updateAt(b, "title-d", 0, schema)
+	require.Nil(t, err)
+	assert.Equal(t, revision+2, next, "updates at revision 0 should overwrite the item")
+	title, err = items.find(b, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "title-d", title)
+
+	require.Nil(t, items.delete(a, schema))
+	_, err = items.find(a, schema)
+	assert.NotNil(t, err, "deleted items shouldn't be found")
+
+	titles, err = items.findAll(map[string]string{"order": items.titleColumn + " asc"}, all, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-c", "title-d"}, titles)
+
+	titles, err = items.all(schema)
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []string{"title-c", "title-d"}, titles)
+
+	// deleted items are changed too, so clients can drop them
+	titles, err = items.changed(since, schema)
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []string{"title-a", "title-d"}, titles)
+
+	testTrash(t, items, schema, a, b, c)
+}
+
+// testFolderFilter puts a and c into a folder, b stays out of any folder
+// testCursor pages the items by cursor. They are b, a and c from the least
+// recently updated, so the cursor of c points to the page of a and b.
+func testCursor(t *testing.T, items *items, schema string, a, c uint) {
+	byCursor := map[string]string{"order": items.titleColumn + " asc"}
+	titles, err := items.findAll(byCursor, map[string]int{"limit": 1, "offset": -1}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-a"}, titles)
+
+	byCursor["cursor"], err = items.cursor(c, schema)
+	require.Nil(t, err)
+	titles, err = items.findAll(byCursor, map[string]int{"limit": 5, "offset": -1}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-a", "title-b"}, titles, "a cursor should page in the default order")
+
+	byCursor["cursor"], err = items.cursor(a, schema)
+	require.Nil(t, err)
+	titles, err = items.findAll(byCursor, map[string]int{"limit": 5, "offset": -1}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-b"}, titles)
+
+	// the total count ignores the page
+	count, err := items.count(byCursor, schema)
+	require.Nil(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func testFolderFilter(t *testing.T, items *items, schema string, a, b, c uint) {
+	all := map[string]int{"limit": -1, "offset": -1}
+	inFolder := func(folder string) []string {
+		titles, err := items.findAll(map[string]string{"order": items.titleColumn + " asc", "folder": folder}, all, schema)
+		require.Nil(t, err)
+		return titles
+	}
+
+	require.Nil(t, items.folder(a, 7, schema))
+	require.Nil(t, items.folder(c, 7, schema))
+	assert.Equal(t, []string{"title-a", "title-c"}, inFolder("7"))
+	assert.Equal(t, []string{"title-b"}, inFolder("0"), "0 should find the items out of any folder")
+}
+
+func testTagFilter(t *testing.T, s storage.Store, items *items, schema string, a, b, c uint) {
+	all := map[string]int{"limit": -1, "offset": -1}
+	tagged := func(tags string) []string {
+		titles, err := items.findAll(map[string]string{"order": items.titleColumn + " asc", "tags": tags}, all, schema)
+		require.Nil(t, err)
+		return titles
+	}
+
+	prod, err := s.Tags().Save(&model.Tag{Name: "prod"}, schema)
+	require.Nil(t, err)
+	customer, err := s.Tags().Save(&model.Tag{Name: "customer"}, schema)
+	require.Nil(t, err)
+	require.Nil(t, s.Tags().SetItemTags(items.itemType, a, []uint{prod.ID}, schema))
+	require.Nil(t, s.Tags().SetItemTags(items.itemType, c, []uint{prod.ID, customer.ID}, schema))
+	// Same ids of another item type shouldn't match
+	require.Nil(t, s.Tags().SetItemTags("other", b, []uint{customer.ID}, schema))
+
+	id := func(tag *model.Tag) string { return fmt.Sprint(tag.ID) }
+	assert.Equal(t, []string{"title-a", "title-c"}, tagged(id(prod)))
+	assert.Equal(t, []string{"title-c"}, tagged(id(customer)))
+	assert.Equal(t, []string{"title-a", "title-c"}, tagged(id(prod)+","+id(customer)), "items having any of the tags should match")
+
+	require.Nil(t, s.Tags().Delete(prod.ID, schema))
+	require.Nil(t, s.Tags().Delete(customer.ID, schema))
+	assert.Empty(t, tagged(id(prod)))
+}
+
+// testTrash expects a to be deleted, b (title-d) and c to be alive
+func testTrash(t *testing.T, items *items, schema string, a, b, c uint) {
+	titles, err := items.deleted(schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-a"}, titles)
+
+	// items in the trash are saved again when the passphrase is rotated
+	require.Nil(t, items.saveDeleted("title-a", schema))
+	titles, err = items.deleted(schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-a"}, titles, "saving should keep items in the trash")
+
+	assert.NotNil(t, items.restore(b, schema), "alive items can't be restored")
+	assert.NotNil(t, items.purge(b, schema), "alive items can't be purged")
+
+	require.Nil(t, items.restore(a, schema))
+	title, err := items.find(a, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "title-a", title)
+
+	time.Sleep(10 * time.Millisecond)
+	require.Nil(t, items.delete(a, schema))
+	time.Sleep(10 * time.Millisecond)
+	require.Nil(t, items.delete(b, schema))
+	titles, err = items.deleted(schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"title-d", "title-a"}, titles, "recently deleted items should come first")
+
+	require.Nil(t, items.purge(b, schema))
+	assert.NotNil(t, items.restore(b, schema), "purged items can't be restored")
+	assert.NotNil(t, items.purge(b, schema), "purged items can't be purged again")
+
+	require.Nil(t, items.delete(c, schema))
+	require.Nil(t, items.purgeBefore(time.Now().Add(time.Hour), schema))
+	titles, err = items.deleted(schema)
+	require.Nil(t, err)
+	assert.Empty(t, titles)
+	assert.NotNil(t, items.restore(c, schema))
+}
+
+func testFolders(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+
+	parent, err := s.Folders().Save(&model.Folder{Name: "Work"}, schema)
+	require.Nil(t, err)
+	child, err := s.Folders().Save(&model.Folder{Name: "Servers", ParentID: &parent.ID}, schema)
+	require.Nil(t, err)
+
+	folder, err := s.Folders().FindByID(child.ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "Servers", folder.Name)
+	require.NotNil(t, folder.ParentID)
+	assert.Equal(t, parent.ID, *folder.ParentID)
+
+	folder.Name = "Hosts"
+	_, err = s.Folders().Save(folder, schema)
+	require.Nil(t, err)
+
+	folders, err := s.Folders().All(schema)
+	require.Nil(t, err)
+	require.Len(t, folders, 2)
+	assert.Equal(t, "Work", folders[0].Name)
+	assert.Equal(t, "Hosts", folders[1].Name)
+
+	require.Nil(t, s.Folders().Delete(parent.ID, schema))
+	_, err = s.Folders().FindByID(parent.ID, schema)
+	assert.NotNil(t, err, "deleted folders shouldn't be found")
+}
+
+func testTags(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+
+	prod, err := s.Tags().Save(&model.Tag{Name: "prod"}, schema)
+	require.Nil(t, err)
+	live, err := s.Tags().Save(&model.Tag{Name: "live"}, schema)
+	require.Nil(t, err)
+	other, err := s.Tags().Save(&model.Tag{Name: "other"}, schema)
+	require.Nil(t, err)
+
+	tag, err := s.Tags().FindByID(prod.ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "prod", tag.Name)
+
+	require.Nil(t, s.Tags().SetItemTags(model.LoginItem, 1, []uint{prod.ID}, schema))
+	require.Nil(t, s.Tags().SetItemTags(model.LoginItem, 2, []uint{prod.ID, live.ID}, schema))
+	require.Nil(t, s.Tags().SetItemTags(model.LoginItem, 3, []uint{live.ID, other.ID}, schema))
+	require.Nil(t, s.Tags().SetItemTags(model.NoteItem, 1, []uint{live.ID}, schema))
+
+	// Item 2 is tagged with both, it should keep a single link to prod
+	require.Nil(t, s.Tags().Merge(live.ID, prod.ID, schema))
+	_, err = s.Tags().FindByID(live.ID, schema)
+	assert.NotNil(t, err, "merged tags should be deleted")
+
+	itemTags, err := s.Tags().FindItemTags(model.LoginItem, []uint{1, 2, 3}, schema)
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []string{"1-prod", "2-prod", "3-prod", "3-other"}, itemTagNames(itemTags, prod, other))
+
+	itemTags, err = s.Tags().FindItemTags(model.NoteItem, []uint{1}, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"1-prod"}, itemTagNames(itemTags, prod, other))
+
+	require.Nil(t, s.Tags().SetItemTags(model.LoginItem, 3, []uint{other.ID}, schema))
+	require.Nil(t, s.Tags().Delete(other.ID, schema))
+	itemTags, err = s.Tags().FindItemTags(model.LoginItem, []uint{3}, schema)
+	require.Nil(t, err)
+	assert.Empty(t, itemTags, "deleted tags should be removed from the items")
+
+	require.Nil(t, s.Tags().DeleteItemTags(model.LoginItem, []uint{1, 2}, schema))
+	itemTags, err = s.Tags().FindItemTags(model.LoginItem, []uint{1, 2}, schema)
+	require.Nil(t, err)
+	assert.Empty(t, itemTags)
+
+	tags, err := s.Tags().All(schema)
+	require.Nil(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "prod", tags[0].Name)
+}
+
+func itemTagNames(itemTags []model.ItemTag, tags ...*model.Tag) []string {
+	names := map[uint]string{}
+	for _, tag := range tags {
+		names[tag.ID] = tag.Name
+	}
+	result := []string{}
+	for _, itemTag := range itemTags {
+		result = append(result, fmt.Sprintf("%d-%s", itemTag.ItemID, names[itemTag.TagID]))
+	}
+	return result
+}
+
+func testRevisions(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+
+	ids := []uint{}
+	for _, data := range []string{"first", "second", "third"} {
+		revision, err := s.Revisions().Save(&model.Revision{ItemType: model.LoginItem, ItemID: 1, Data: data}, schema)
+		require.Nil(t, err)
+		ids = append(ids, revision.ID)
+	}
+	_, err := s.Revisions().Save(&model.Revision{ItemType: model.NoteItem, ItemID: 1, Data: "note"}, schema)
+	require.Nil(t, err)
+
+	revisions, err := s.Revisions().FindAll(model.LoginItem, 1, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"third", "second", "first"}, revisionData(revisions), "newest revisions should come first")
+
+	revision, err := s.Revisions().FindByID(ids[0], schema)
+	require.Nil(t, err)
+	assert.Equal(t, "first", revision.Data)
+
+	require.Nil(t, s.Revisions().Prune(model.LoginItem, 1, 2, schema))
+	revisions, err = s.Revisions().FindAll(model.LoginItem, 1, schema)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"third", "second"}, revisionData(revisions))
+
+	require.Nil(t, s.Revisions().DeleteAll(model.LoginItem, []uint{1}, schema))
+	revisions, err = s.Revisions().FindAll(model.LoginItem, 1, schema)
+	require.Nil(t, err)
+	assert.Empty(t, revisions)
+
+	revisions, err = s.Revisions().FindAll(model.NoteItem, 1, schema)
+	require.Nil(t, err)
+	assert.Len(t, revisions, 1, "revisions of other items should be kept")
+}
+
+func testTombstones(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+
+	_, err := s.Tombstones().Save(&model.Tombstone{ItemType: model.LoginItem, ItemID: 1}, schema)
+	require.Nil(t, err)
+	since := time.Now()
+	_, err = s.Tombstones().Save(&model.Tombstone{ItemType: model.NoteItem, ItemID: 2}, schema)
+	require.Nil(t, err)
+
+	tombstones, err := s.Tombstones().FindAllSince(time.Time{}, schema)
+	require.Nil(t, err)
+	assert.Len(t, tombstones, 2)
+
+	tombstones, err = s.Tombstones().FindAllSince(since, schema)
+	require.Nil(t, err)
+	require.Len(t, tombstones, 1)
+	assert.Equal(t, model.NoteItem, tombstones[0].ItemType)
+	assert.Equal(t, uint(2), tombstones[0].ItemID)
+
+	require.Nil(t, s.Tombstones().DeleteBefore(since, schema))
+	tombstones, err = s.Tombstones().FindAllSince(time.Time{}, schema)
+	require.Nil(t, err)
+	require.Len(t, tombstones, 1)
+	assert.Equal(t, uint(2), tombstones[0].ItemID)
+}
+
+func revisionData(revisions []model.Revision) []string {
+	data := []string{}
+	for _, revision := range revisions {
+		data = append(data, revision.Data)
+	}
+	return data
+}
+
+func testLicenseKeyExpiry(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+	argsInt := map[string]int{"limit": -1, "offset": -1}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expired, soon, later := now.AddDate(0, -1, 0), now.AddDate(0, 0, 10), now.AddDate(1, 0, 0)
+	for product, expiresAt := range map[string]*time.Time{"expired": &expired, "soon": &soon, "later": &later, "perpetual": nil} {
+		_, err := s.LicenseKeys().Save(&model.LicenseKey{Product: product, ExpiresAt: expiresAt}, schema)
+		require.Nil(t, err)
+	}
+
+	products := func(argsStr map[string]string) []string {
+		argsStr["order"] = "expires_at asc"
+		licenseKeys, err := s.LicenseKeys().FindAll(argsStr, argsInt, schema)
+		require.Nil(t, err)
+		count, err := s.LicenseKeys().Count(argsStr, schema)
+		require.Nil(t, err)
+		assert.Equal(t, len(licenseKeys), count)
+
+		products := []string{}
+		for _, licenseKey := range licenseKeys {
+			products = append(products, licenseKey.Product)
+		}
+		return products
+	}
+
+	assert.Equal(t, []string{"expired"}, products(map[string]string{"expires_before": now.Format(time.RFC3339)}))
+	assert.Equal(t, []string{"soon"}, products(map[string]string{
+		"expires_after":  now.Format(time.RFC3339),
+		"expires_before": now.AddDate(0, 1, 0).Format(time.RFC3339),
+	}))
+	assert.Equal(t, []string{"soon", "later"}, products(map[string]string{"expires_after": now.Format(time.RFC3339)}))
+	assert.Len(t, products(map[string]string{}), 4, "keys are found regardless of expiry without a filter")
+}
+
+func testCustomFields(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+
+	fields := model.CustomFields{
+		{Name: "PIN", Value: "1234", Type: model.HiddenField},
+		{Name: "Admin", Value: "true", Type: model.BooleanField},
+	}
+	note, err := s.Notes().Save(&model.Note{Title: "custom", CustomFields: fields}, schema)
+	require.Nil(t, err)
+	found, err := s.Notes().FindByID(note.ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, fields, found.CustomFields)
+
+	note, err = s.Notes().Save(&model.Note{Title: "none"}, schema)
+	require.Nil(t, err)
+	found, err = s.Notes().FindByID(note.ID, schema)
+	require.Nil(t, err)
+	assert.Empty(t, found.CustomFields)
+}
+
+func testSaveEncrypted(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+
+	login, err := s.Logins().Save(&model.Login{Title: "title", Password: "old", SearchIndex: "old-index"}, schema)
+	require.Nil(t, err)
+	stored, err := s.Logins().FindByID(login.ID, schema)
+	require.Nil(t, err)
+
+	// only the encrypted fields and the search index are written
+	stored.Title = "other title"
+	stored.Password = "new"
+	stored.SearchIndex = "new-index"
+	stored.CustomFields = model.CustomFields{{Name: "PIN", Value: "1234", Type: model.HiddenField}}
+	_, err = s.Logins().SaveEncrypted(stored, schema)
+	require.Nil(t, err)
+
+	found, err := s.Logins().FindByID(login.ID, schema)
+	require.Nil(t, err)
+	assert.Equal(t, "title", found.Title)
+	assert.Equal(t, "new", found.Password)
+	assert.Equal(t, "new-index", found.SearchIndex)
+	assert.Equal(t, stored.CustomFields, found.CustomFields)
+	assert.Equal(t, login.Revision, found.Revision, "the revision should stay")
+	assert.True(t, login.UpdatedAt.Equal(found.UpdatedAt), "the update time should stay")
+
+	_, err = s.Logins().SaveEncrypted(&model.Login{ID: login.ID + 1000}, schema)
+	assert.NotNil(t, err, "missing items can't be saved")
+}
+
+func testTemplates(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+
+	fields := model.TemplateFields{
+		{Name: "ssid", Type: model.TextField, Required: true},
+		{Name: "password", Type: model.HiddenField, Secret: true},
+	}
+	template, err := s.Templates().Save(&model.Template{Type: "wifi-network", Name: "Wi-Fi", Fields: fields}, schema)
+	require.Nil(t, err)
+	assert.NotZero(t, template.ID)
+
+	found, err := s.Templates().FindByType("wifi-network", schema)
+	require.Nil(t, err)
+	assert.Equal(t, template.ID, found.ID)
+	assert.Equal(t, fields, found.Fields)
+
+	_, err = s.Templates().FindByType("unknown", schema)
+	assert.NotNil(t, err)
+
+	found.Name = "Wireless"
+	_, err = s.Templates().Save(found, schema)
+	require.Nil(t, err)
+	templates, err := s.Templates().All(schema)
+	require.Nil(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "Wireless", templates[0].Name)
+
+	require.Nil(t, s.Templates().Delete(template.ID, schema))
+	_, err = s.Templates().FindByID(template.ID, schema)
+	assert.NotNil(t, err)
+}
+
+func testAttachments(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+
+	save := func(itemType string, itemID uint, size int64) *model.Attachment {
+		attachment, err := s.Attachments().Save(&model.Attachment{ItemType: itemType, ItemID: itemID, Name: "file", Size: size, BlobKey: unique("blob")}, schema)
+		require.Nil(t, err)
+		return attachment
+	}
+	a := save(model.ServerItem, 1, 100)
+	b := save(model.ServerItem, 1, 20)
+	save(model.ServerItem, 2, 3)
+	save(model.NoteItem, 1, 4)
+
+	attachments, err := s.Attachments().FindAll(model.ServerItem, 1, schema)
+	require.Nil(t, err)
+	require.Len(t, attachments, 2)
+	assert.Equal(t, []uint{a.ID, b.ID}, []uint{attachments[0].ID, attachments[1].ID})
+	assert.Equal(t, a.BlobKey, attachments[0].BlobKey)
+
+	attachments, err = s.Attachments().FindAllByItems(model.ServerItem, []uint{1, 2}, schema)
+	require.Nil(t, err)
+	assert.Len(t, attachments, 3)
+	attachments, err = s.Attachments().FindAllByItems(model.ServerItem, nil, schema)
+	require.Nil(t, err)
+	assert.Empty(t, attachments)
+
+	total, err := s.Attachments().TotalSize(schema)
+	require.Nil(t, err)
+	assert.Equal(t, int64(127), total)
+
+	require.Nil(t, s.Attachments().Delete(a.ID, schema))
+	_, err = s.Attachments().FindByID(a.ID, schema)
+	assert.NotNil(t, err)
+	total, err = s.Attachments().TotalSize(schema)
+	require.Nil(t, err)
+	assert.Equal(t, int64(27), total)
+
+	// An empty schema has no attachments
+	total, err = s.Attachments().TotalSize(createUser(t, s).Schema)
+	require.Nil(t, err)
+	assert.Zero(t, total)
+}
+
+func testTransaction(t *testing.T, s storage.Store) {
+	schema := createUser(t, s).Schema
+	errRollback := fmt.Errorf("rollback")
+
+	err := s.Transaction(func(tx storage.Store) error {
+		if _, err := tx.Logins().Save(&model.Login{Title: "rolled back"}, schema); err != nil {
+			return err
+		}
+		// transactions started in a transaction join it
+		return tx.Transaction(func(tx storage.Store) error {
+			if _, err := tx.Notes().Save(&model.Note{Title: "rolled back"}, schema); err != nil {
+				return err
+			}
+			return errRollback
+		})
+	})
+	assert.Equal(t, errRollback, err)
+
+	logins, err := s.Logins().All(schema)
+	require.Nil(t, err)
+	assert.Empty(t, logins)
+	notes, err := s.Notes().All(schema)
+	require.Nil(t, err)
+	assert.Empty(t, notes)
+
+	err = s.Transaction(func(tx storage.Store) error {
+		_, err := tx.Logins().Save(&model.Login{Title: "committed"}, schema)
+		return err
+	})
+	require.Nil(t, err)
+	logins, err = s.Logins().All(schema)
+	require.Nil(t, err)
+	require.Len(t, logins, 1)
+	assert.Equal(t, "committed", logins[0].Title)
+}
+
+func testSchemaIsolation(t *testing.T, s storage.Store) {
+	first := createUser(t, s)
+	second := createUser(t, s)
+
+	login, err := s.Logins().Save(&model.Login{Title: "first user's login"}, first.Schema)
+	require.Nil(t, err)
+
+	logins, err := s.Logins().All(second.Schema)
+	require.Nil(t, err)
+	assert.Empty(t, logins)
+
+	logins, err = s.Logins().All(first.Schema)
+	require.Nil(t, err)
+	assert.Len(t, logins, 1)
+
+	// Deleting a user removes the data in its schema
+	require.Nil(t, s.Users().Delete(first.ID, first.Schema))
+	_, err = s.Logins().FindByID(login.ID, first.Schema)
+	assert.NotNil(t, err)
+}
+
+func testMigrationLock(t *testing.T, s storage.Store) {
+	first, second := unique("first"), unique("second")
+
+	require.Nil(t, s.Migrations().Lock(first, time.Hour))
+	assert.NotNil(t, s.Migrations().Lock(second, time.Hour), "lock should be held by the first owner")
+
+	// Only the owner can release its lock
+	require.Nil(t, s.Migrations().Unlock(second))
+	assert.NotNil(t, s.Migrations().Lock(second, time.Hour))
+
+	require.Nil(t, s.Migrations().Refresh(first))
+	assert.NotNil(t, s.Migrations().Refresh(second), "only the owner can refresh its lock")
+
+	require.Nil(t, s.Migrations().Unlock(first))
+	require.Nil(t, s.Migrations().Lock(second, time.Hour))
+	assert.NotNil(t, s.Migrations().Refresh(first), "a released lock can't be refreshed")
+
+	// An expired lock is taken over
+	time.Sleep(10 * time.Millisecond)
+	require.Nil(t, s.Migrations().Lock(first, time.Millisecond))
+	require.Nil(t, s.Migrations().Unlock(first))
+}
@@ -2,6 +2,7 @@ package subscription
 
 import (
 	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
 	"github.com/passwall/passwall-server/model"
 )
 
@@ -25,22 +26,27 @@ func (p *Repository) All() ([]model.Subscription, error) {
 // FindAll ...
 func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int) ([]model.Subscription, error) {
 	subscriptions := []model.Subscription{}
+	query := pagination.Apply(p.filter(argsStr), argsStr, argsInt)
+	err := query.Find(&subscriptions).Error
+	return subscriptions, err
+}
 
-	query := p.db
-	query = query.Limit(argsInt["limit"])
-	if argsInt["limit"] > 0 {
-		// offset can't be declared without a valid limit
-		query = query.Offset(argsInt["offset"])
-	}
+// Count returns the number of subscriptions matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string) (int, error) {
+	count := 0
+	err := p.filter(argsStr).Model(&model.Subscription{}).Count(&count).Error
+	return count, err
+}
 
-	query = query.Order(argsStr["order"])
+// filter returns the query of the subscriptions matching the search
+func (p *Repository) filter(argsStr map[string]string) *gorm.DB {
+	query := p.db
 
 	if argsStr["search"] != "" {
 		query = query.Where("title LIKE ? OR ip LIKE ?", "%"+argsStr["search"]+"%", "%"+argsStr["search"]+"%")
 	}
 
-	err := query.Find(&subscriptions).Error
-	return subscriptions, err
+	return query
 }
 
 // FindByID ...
@@ -0,0 +1,131 @@
+package tag
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Repository ...
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "tags")
+}
+
+func (p *Repository) itemTable(schema string) string {
+	return dialect.Table(p.db, schema, "item_tags")
+}
+
+// All ...
+func (p *Repository) All(schema string) ([]model.Tag, error) {
+	tags := []model.Tag{}
+	err := p.db.Table(p.table(schema)).Order("id").Find(&tags).Error
+	return tags, err
+}
+
+// FindByID ...
+func (p *Repository) FindByID(id uint, schema string) (*model.Tag, error) {
+	tag := new(model.Tag)
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&tag).Error
+	return tag, err
+}
+
+// Save ...
+func (p *Repository) Save(tag *model.Tag, schema string) (*model.Tag, error) {
+	err := p.db.Table(p.table(schema)).Save(&tag).Error
+	return tag, err
+}
+
+// Delete ...
+func (p *Repository) Delete(id uint, schema string) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(p.itemTable(schema)).Where("tag_id = ?", id).Delete(&model.ItemTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Table(p.table(schema)).Delete(&model.Tag{ID: id}).Error
+	})
+}
+
+// Merge ...
+func (p *Repository) Merge(id, targetID uint, schema string) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		// Items having both tags keep a single link to the target
+		targetTags := []model.ItemTag{}
+		if err := tx.Table(p.itemTable(schema)).Where("tag_id = ?", targetID).Find(&targetTags).Error; err != nil {
+			return err
+		}
+		for _, t := range targetTags {
+			err := tx.Table(p.itemTable(schema)).
+				Where("tag_id = ? AND item_type = ? AND item_id = ?", id, t.ItemType, t.ItemID).
+				Delete(&model.ItemTag{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err := tx.Table(p.itemTable(schema)).Where("tag_id = ?", id).Update("tag_id", targetID).Error
+		if err != nil {
+			return err
+		}
+		return tx.Table(p.table(schema)).Delete(&model.Tag{ID: id}).Error
+	})
+}
+
+// FindItemTags ...
+func (p *Repository) FindItemTags(itemType string, itemIDs []uint, schema string) ([]model.ItemTag, error) {
+	itemTags := []model.ItemTag{}
+	if len(itemIDs) == 0 {
+		return itemTags, nil
+	}
+	err := p.db.Table(p.itemTable(schema)).
+		Where("item_type = ? AND item_id IN (?)", itemType, itemIDs).
+		Order("id").
+		Find(&itemTags).Error
+	return itemTags, err
+}
+
+// SetItemTags ...
+func (p *Repository) SetItemTags(itemType string, itemID uint, tagIDs []uint, schema string) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Table(p.itemTable(schema)).
+			Where("item_type = ? AND item_id = ?", itemType, itemID).
+			Delete(&model.ItemTag{}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, tagID := range tagIDs {
+			itemTag := &model.ItemTag{TagID: tagID, ItemType: itemType, ItemID: itemID}
+			if err := tx.Table(p.itemTable(schema)).Create(itemTag).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteItemTags ...
+func (p *Repository) DeleteItemTags(itemType string, itemIDs []uint, schema string) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	return p.db.Table(p.itemTable(schema)).
+		Where("item_type = ? AND item_id IN (?)", itemType, itemIDs).
+		Delete(&model.ItemTag{}).Error
+}
+
+// Migrate ...
+func (p *Repository) Migrate(schema string) error {
+	if err := p.db.Table(p.table(schema)).AutoMigrate(&model.Tag{}).Error; err != nil {
+		return err
+	}
+	return p.db.Table(p.itemTable(schema)).AutoMigrate(&model.ItemTag{}).Error
+}
@@ -0,0 +1,59 @@
+package template
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Repository ...
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "templates")
+}
+
+// All ...
+func (p *Repository) All(schema string) ([]model.Template, error) {
+	templates := []model.Template{}
+	err := p.db.Table(p.table(schema)).Order("id").Find(&templates).Error
+	return templates, err
+}
+
+// FindByID ...
+func (p *Repository) FindByID(id uint, schema string) (*model.Template, error) {
+	template := new(model.Template)
+	err := p.db.Table(p.table(schema)).Where(`id = ?`, id).First(&template).Error
+	return template, err
+}
+
+// FindByType ...
+func (p *Repository) FindByType(itemType string, schema string) (*model.Template, error) {
+	template := new(model.Template)
+	err := p.db.Table(p.table(schema)).Where(`type = ?`, itemType).First(&template).Error
+	return template, err
+}
+
+// Save ...
+func (p *Repository) Save(template *model.Template, schema string) (*model.Template, error) {
+	err := p.db.Table(p.table(schema)).Save(&template).Error
+	return template, err
+}
+
+// Delete ...
+func (p *Repository) Delete(id uint, schema string) error {
+	err := p.db.Table(p.table(schema)).Delete(&model.Template{ID: id}).Error
+	return err
+}
+
+// Migrate ...
+func (p *Repository) Migrate(schema string) error {
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Template{}).Error
+}
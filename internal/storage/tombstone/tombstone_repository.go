@@ -0,0 +1,46 @@
+package tombstone
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/model"
+)
+
+// Repository ...
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository ...
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (p *Repository) table(schema string) string {
+	return dialect.Table(p.db, schema, "tombstones")
+}
+
+// FindAllSince ...
+func (p *Repository) FindAllSince(since time.Time, schema string) ([]model.Tombstone, error) {
+	tombstones := []model.Tombstone{}
+	err := p.db.Table(p.table(schema)).Where("created_at > ?", since).Order("id").Find(&tombstones).Error
+	return tombstones, err
+}
+
+// Save ...
+func (p *Repository) Save(tombstone *model.Tombstone, schema string) (*model.Tombstone, error) {
+	err := p.db.Table(p.table(schema)).Save(&tombstone).Error
+	return tombstone, err
+}
+
+// DeleteBefore ...
+func (p *Repository) DeleteBefore(t time.Time, schema string) error {
+	return p.db.Table(p.table(schema)).Where("created_at < ?", t).Delete(&model.Tombstone{}).Error
+}
+
+// Migrate ...
+func (p *Repository) Migrate(schema string) error {
+	return p.db.Table(p.table(schema)).AutoMigrate(&model.Tombstone{}).Error
+}
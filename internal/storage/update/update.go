@@ -0,0 +1,95 @@
+// Package update writes the item rows which gorm's Save can't: updates which
+// only succeed when the item is still at the revision it was read at, and
+// updates of the encrypted columns which leave the item as it is otherwise.
+package update
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrRevisionChanged is returned when an item isn't at the revision it was
+// read at anymore, another update changed it meanwhile
+var ErrRevisionChanged = errors.New("item was changed since its revision")
+
+// Revision writes every column of the item and moves it to its next revision,
+// unless another update moved it past revision first. A revision of 0 writes
+// the item whatever its revision. Items in the trash are written too.
+func Revision(db *gorm.DB, table string, item interface{}, revision uint) error {
+	scope := db.NewScope(item)
+	columns := map[string]interface{}{}
+	for _, field := range scope.Fields() {
+		if !field.IsNormal || field.IsIgnored || field.IsPrimaryKey || field.DBName == "created_at" {
+			continue
+		}
+		columns[field.DBName] = field.Field.Interface()
+	}
+	now := gorm.NowFunc()
+	columns["updated_at"] = now
+	columns["revision"] = gorm.Expr("revision + 1")
+
+	id := scope.PrimaryKeyValue()
+	query := db.Unscoped().Table(table).Where("id = ?", id)
+	if revision != 0 {
+		query = query.Where("revision = ?", revision)
+	}
+	query = query.UpdateColumns(columns)
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 && revision != 0 {
+		return ErrRevisionChanged
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	if revision == 0 {
+		var current struct{ Revision uint }
+		if err := db.Unscoped().Table(table).Select("revision").Where("id = ?", id).Scan(&current).Error; err != nil {
+			return err
+		}
+		revision = current.Revision - 1
+	}
+	if err := scope.SetColumn("UpdatedAt", now); err != nil {
+		return err
+	}
+	return scope.SetColumn("Revision", revision+1)
+}
+
+// Encrypted writes the encrypted columns and the search index of the item
+// only. Its revision and update time stay as they are, so encrypting the item
+// again isn't a change to sync. Items in the trash are written too.
+func Encrypted(db *gorm.DB, table string, item interface{}) error {
+	scope := db.NewScope(item)
+	columns := map[string]interface{}{}
+	for _, name := range EncryptedFields(item) {
+		if field, ok := scope.FieldByName(name); ok {
+			columns[field.DBName] = field.Field.Interface()
+		}
+	}
+
+	query := db.Unscoped().Table(table).Where("id = ?", scope.PrimaryKeyValue()).UpdateColumns(columns)
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// EncryptedFields returns the names of the fields of the item tagged
+// encrypt:"true" and of its search index, which is keyed like them
+func EncryptedFields(item interface{}) []string {
+	t := reflect.TypeOf(item).Elem()
+	names := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("encrypt") == "true" || t.Field(i).Name == "SearchIndex" {
+			names = append(names, t.Field(i).Name)
+		}
+	}
+	return names
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 
 	"github.com/jinzhu/gorm"
+	"github.com/passwall/passwall-server/internal/storage/dialect"
+	"github.com/passwall/passwall-server/internal/storage/pagination"
 	"github.com/passwall/passwall-server/model"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -28,15 +30,21 @@ func (p *Repository) All() ([]model.User, error) {
 // FindAll ...
 func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int) ([]model.User, error) {
 	users := []model.User{}
+	query := pagination.Apply(p.filter(argsStr), argsStr, argsInt)
+	err := query.Find(&users).Error
+	return users, err
+}
 
-	query := p.db
-	query = query.Limit(argsInt["limit"])
-	if argsInt["limit"] > 0 {
-		// offset can't be declared without a valid limit
-		query = query.Offset(argsInt["offset"])
-	}
+// Count returns the number of users matching the arguments, regardless of the page
+func (p *Repository) Count(argsStr map[string]string) (int, error) {
+	count := 0
+	err := p.filter(argsStr).Model(&model.User{}).Count(&count).Error
+	return count, err
+}
 
-	query = query.Order(argsStr["order"])
+// filter returns the query of the users matching the search
+func (p *Repository) filter(argsStr map[string]string) *gorm.DB {
+	query := p.db
 
 	if argsStr["search"] != "" {
 		query = query.Where("name LIKE ? OR email LIKE ? OR plan LIKE ? OR role LIKE ?",
@@ -46,8 +54,7 @@ func (p *Repository) FindAll(argsStr map[string]string, argsInt map[string]int)
 			"%"+argsStr["search"]+"%")
 	}
 
-	err := query.Find(&users).Error
-	return users, err
+	return query
 }
 
 // FindByID ...
@@ -97,7 +104,7 @@ func (p *Repository) Save(user *model.User) (*model.User, error) {
 // Delete ...
 func (p *Repository) Delete(id uint, schema string) error {
 
-	err := p.db.Exec("DROP SCHEMA " + schema + " CASCADE").Error
+	err := p.dropSchema(schema)
 	if err != nil {
 		log.Println(err)
 	}
@@ -114,6 +121,10 @@ func (p *Repository) Migrate() error {
 // CreateSchema ...
 func (p *Repository) CreateSchema(schema string) error {
 	var err error
+	// SQLite has no schemas, user tables are prefixed instead
+	if dialect.IsSQLite(p.db) {
+		return nil
+	}
 	if schema != "" && schema != "public" {
 		err := p.db.Exec("CREATE SCHEMA IF NOT EXISTS " + schema).Error
 		if err != nil {
@@ -122,3 +133,25 @@ func (p *Repository) CreateSchema(schema string) error {
 	}
 	return err
 }
+
+// dropSchema removes the schema and all the user tables in it
+func (p *Repository) dropSchema(schema string) error {
+	if !dialect.IsSQLite(p.db) {
+		return p.db.Exec("DROP SCHEMA " + schema + " CASCADE").Error
+	}
+
+	var tables []string
+	err := p.db.Table("sqlite_master").
+		Where("type = 'table' AND name LIKE ? ESCAPE '\\'", schema+"\\_%").
+		Pluck("name", &tables).Error
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if err := p.db.DropTable(table).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
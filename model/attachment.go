@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// Attachment is a file attached to an item. The content is encrypted with
+// a random key of its own and kept in the blob store, the key is stored
+// here encrypted with the passphrase.
+type Attachment struct {
+	ID          uint      `gorm:"primary_key" json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	ItemType    string    `json:"item_type"`
+	ItemID      uint      `json:"item_id"`
+	Name        string    `json:"name" encrypt:"true"`
+	ContentType string    `json:"content_type" encrypt:"true"`
+	Size        int64     `json:"size"`
+	BlobKey     string    `json:"-"`
+	Key         string    `gorm:"type:text" json:"-" encrypt:"true"`
+}
+
+// AttachmentDTO DTO object for Attachment type
+type AttachmentDTO struct {
+	ID          uint      `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	ItemType    string    `json:"item_type"`
+	ItemID      uint      `json:"item_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+}
+
+// ToAttachmentDTO ...
+func ToAttachmentDTO(attachment *Attachment) *AttachmentDTO {
+	return &AttachmentDTO{
+		ID:          attachment.ID,
+		CreatedAt:   attachment.CreatedAt,
+		ItemType:    attachment.ItemType,
+		ItemID:      attachment.ItemID,
+		Name:        attachment.Name,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+	}
+}
+
+// ToAttachmentDTOs ...
+func ToAttachmentDTOs(attachments []Attachment) []*AttachmentDTO {
+	attachmentDTOs := make([]*AttachmentDTO, len(attachments))
+
+	for i := range attachments {
+		attachmentDTOs[i] = ToAttachmentDTO(&attachments[i])
+	}
+
+	return attachmentDTOs
+}
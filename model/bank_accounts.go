@@ -6,29 +6,40 @@ import (
 
 // BankAccount ...
 type BankAccount struct {
-	ID            uint       `gorm:"primary_key" json:"id"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
-	DeletedAt     *time.Time `json:"deleted_at"`
-	BankName      string     `json:"title"`
-	BankCode      string     `json:"bank_code"`
-	AccountName   string     `json:"account_name" encrypt:"true"`
-	AccountNumber string     `json:"account_number" encrypt:"true"`
-	IBAN          string     `json:"iban" encrypt:"true"`
-	Currency      string     `json:"currency" encrypt:"true"`
-	Password      string     `json:"password" encrypt:"true"`
+	ID            uint         `gorm:"primary_key" json:"id"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	DeletedAt     *time.Time   `json:"deleted_at"`
+	Revision      uint         `gorm:"not null;default:1" json:"revision"`
+	BankName      string       `json:"title"`
+	BankCode      string       `json:"bank_code"`
+	AccountName   string       `json:"account_name" encrypt:"true" search:"token"`
+	AccountNumber string       `json:"account_number" encrypt:"true"`
+	IBAN          string       `json:"iban" encrypt:"true" search:"exact"`
+	Currency      string       `json:"currency" encrypt:"true" search:"exact"`
+	Password      string       `json:"password" encrypt:"true"`
+	CustomFields  CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite      bool         `json:"favorite"`
+	FolderID      *uint        `json:"folder_id"`
+	TagIDs        []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex   string       `gorm:"type:text" json:"-"`
 }
 
 //BankAccountDTO DTO object for BankAccount type
 type BankAccountDTO struct {
-	ID            uint   `json:"id"`
-	BankName      string `json:"title"`
-	BankCode      string `json:"bank_code"`
-	AccountName   string `json:"account_name"`
-	AccountNumber string `json:"account_number"`
-	IBAN          string `json:"iban"`
-	Currency      string `json:"currency"`
-	Password      string `json:"password"`
+	ID            uint         `json:"id"`
+	Revision      uint         `json:"revision"`
+	BankName      string       `json:"title"`
+	BankCode      string       `json:"bank_code"`
+	AccountName   string       `json:"account_name"`
+	AccountNumber string       `json:"account_number"`
+	IBAN          string       `json:"iban"`
+	Currency      string       `json:"currency"`
+	Password      string       `json:"password"`
+	CustomFields  CustomFields `json:"custom_fields"`
+	Favorite      bool         `json:"favorite"`
+	FolderID      *uint        `json:"folder_id"`
+	TagIDs        []uint       `json:"tag_ids"`
 }
 
 // ToBankAccount ...
@@ -41,6 +52,10 @@ func ToBankAccount(bankAccountDTO *BankAccountDTO) *BankAccount {
 		IBAN:          bankAccountDTO.IBAN,
 		Currency:      bankAccountDTO.Currency,
 		Password:      bankAccountDTO.Password,
+		CustomFields:  bankAccountDTO.CustomFields,
+		Favorite:      bankAccountDTO.Favorite,
+		FolderID:      bankAccountDTO.FolderID,
+		TagIDs:        bankAccountDTO.TagIDs,
 	}
 }
 
@@ -48,6 +63,7 @@ func ToBankAccount(bankAccountDTO *BankAccountDTO) *BankAccount {
 func ToBankAccountDTO(bankAccount *BankAccount) *BankAccountDTO {
 	return &BankAccountDTO{
 		ID:            bankAccount.ID,
+		Revision:      bankAccount.Revision,
 		BankName:      bankAccount.BankName,
 		BankCode:      bankAccount.BankCode,
 		AccountName:   bankAccount.AccountName,
@@ -55,6 +71,10 @@ func ToBankAccountDTO(bankAccount *BankAccount) *BankAccountDTO {
 		IBAN:          bankAccount.IBAN,
 		Currency:      bankAccount.Currency,
 		Password:      bankAccount.Password,
+		CustomFields:  bankAccount.CustomFields,
+		Favorite:      bankAccount.Favorite,
+		FolderID:      bankAccount.FolderID,
+		TagIDs:        bankAccount.TagIDs,
 	}
 }
 
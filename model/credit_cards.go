@@ -6,27 +6,38 @@ import (
 
 // CreditCard ...
 type CreditCard struct {
-	ID                 uint       `gorm:"primary_key" json:"id"`
-	CreatedAt          time.Time  `json:"created_at"`
-	UpdatedAt          time.Time  `json:"updated_at"`
-	DeletedAt          *time.Time `json:"deleted_at"`
-	CardName           string     `json:"title"`
-	CardholderName     string     `json:"cardholder_name" encrypt:"true"`
-	Type               string     `json:"type" encrypt:"true"`
-	Number             string     `json:"number" encrypt:"true"`
-	VerificationNumber string     `json:"verification_number" encrypt:"true"`
-	ExpiryDate         string     `json:"expiry_date" encrypt:"true"`
+	ID                 uint         `gorm:"primary_key" json:"id"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+	DeletedAt          *time.Time   `json:"deleted_at"`
+	Revision           uint         `gorm:"not null;default:1" json:"revision"`
+	CardName           string       `json:"title"`
+	CardholderName     string       `json:"cardholder_name" encrypt:"true" search:"token"`
+	Type               string       `json:"type" encrypt:"true" search:"exact"`
+	Number             string       `json:"number" encrypt:"true"`
+	VerificationNumber string       `json:"verification_number" encrypt:"true"`
+	ExpiryDate         string       `json:"expiry_date" encrypt:"true"`
+	CustomFields       CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite           bool         `json:"favorite"`
+	FolderID           *uint        `json:"folder_id"`
+	TagIDs             []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex        string       `gorm:"type:text" json:"-"`
 }
 
 //CreditCardDTO DTO object for CreditCard type
 type CreditCardDTO struct {
-	ID                 uint   `json:"id"`
-	CardName           string `json:"title"`
-	CardholderName     string `json:"cardholder_name"`
-	Type               string `json:"type"`
-	Number             string `json:"number"`
-	VerificationNumber string `json:"verification_number"`
-	ExpiryDate         string `json:"expiry_date"`
+	ID                 uint         `json:"id"`
+	Revision           uint         `json:"revision"`
+	CardName           string       `json:"title"`
+	CardholderName     string       `json:"cardholder_name"`
+	Type               string       `json:"type"`
+	Number             string       `json:"number"`
+	VerificationNumber string       `json:"verification_number"`
+	ExpiryDate         string       `json:"expiry_date"`
+	CustomFields       CustomFields `json:"custom_fields"`
+	Favorite           bool         `json:"favorite"`
+	FolderID           *uint        `json:"folder_id"`
+	TagIDs             []uint       `json:"tag_ids"`
 }
 
 // ToCreditCard ...
@@ -38,6 +49,10 @@ func ToCreditCard(creditCardDTO *CreditCardDTO) *CreditCard {
 		Number:             creditCardDTO.Number,
 		VerificationNumber: creditCardDTO.VerificationNumber,
 		ExpiryDate:         creditCardDTO.ExpiryDate,
+		CustomFields:       creditCardDTO.CustomFields,
+		Favorite:           creditCardDTO.Favorite,
+		FolderID:           creditCardDTO.FolderID,
+		TagIDs:             creditCardDTO.TagIDs,
 	}
 }
 
@@ -45,12 +60,17 @@ func ToCreditCard(creditCardDTO *CreditCardDTO) *CreditCard {
 func ToCreditCardDTO(creditCard *CreditCard) *CreditCardDTO {
 	return &CreditCardDTO{
 		ID:                 creditCard.ID,
+		Revision:           creditCard.Revision,
 		CardName:           creditCard.CardName,
 		CardholderName:     creditCard.CardholderName,
 		Type:               creditCard.Type,
 		Number:             creditCard.Number,
 		VerificationNumber: creditCard.VerificationNumber,
 		ExpiryDate:         creditCard.ExpiryDate,
+		CustomFields:       creditCard.CustomFields,
+		Favorite:           creditCard.Favorite,
+		FolderID:           creditCard.FolderID,
+		TagIDs:             creditCard.TagIDs,
 	}
 }
 
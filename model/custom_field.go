@@ -0,0 +1,63 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Custom field types
+const (
+	TextField    = "text"
+	HiddenField  = "hidden"
+	BooleanField = "boolean"
+	LinkField    = "link"
+)
+
+// CustomFieldTypes lists every custom field type
+var CustomFieldTypes = []string{TextField, HiddenField, BooleanField, LinkField}
+
+// CustomField is a named value added to an item beyond the fields of its type.
+// The name and the value are encrypted, the type tells clients how to show it.
+type CustomField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+// CustomFields are the custom fields of an item, stored as JSON in a single column
+type CustomFields []CustomField
+
+// MarshalJSON encodes missing custom fields as an empty list
+func (f CustomFields) MarshalJSON() ([]byte, error) {
+	if f == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]CustomField(f))
+}
+
+// Value implements driver.Valuer
+func (f CustomFields) Value() (driver.Value, error) {
+	data, err := f.MarshalJSON()
+	return string(data), err
+}
+
+// Scan implements sql.Scanner
+func (f *CustomFields) Scan(src interface{}) error {
+	data, err := columnBytes(src)
+	if err != nil {
+		return err
+	}
+
+	fields := []CustomField{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+	}
+	if len(fields) == 0 {
+		*f = nil
+		return nil
+	}
+	*f = fields
+	return nil
+}
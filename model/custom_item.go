@@ -0,0 +1,130 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// CustomItem is an item of a user defined template. The template decides
+// which of its fields are secret, those are encrypted when the item is saved.
+type CustomItem struct {
+	ID           uint         `gorm:"primary_key" json:"id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	DeletedAt    *time.Time   `json:"deleted_at"`
+	Revision     uint         `gorm:"not null;default:1" json:"revision"`
+	Type         string       `json:"type"`
+	Title        string       `json:"title"`
+	Fields       ItemFields   `gorm:"type:text" json:"fields" encrypt:"true"`
+	CustomFields CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex  string       `gorm:"type:text" json:"-"`
+}
+
+// ItemField is a field value of a custom item, a secret value is encrypted
+type ItemField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Secret bool   `json:"secret"`
+}
+
+// ItemFields are the field values of a custom item, stored as JSON in a single column
+type ItemFields []ItemField
+
+// Value implements driver.Valuer
+func (f ItemFields) Value() (driver.Value, error) {
+	if f == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]ItemField(f))
+	return string(data), err
+}
+
+// Scan implements sql.Scanner
+func (f *ItemFields) Scan(src interface{}) error {
+	data, err := columnBytes(src)
+	if err != nil || len(data) == 0 {
+		*f = nil
+		return err
+	}
+	return json.Unmarshal(data, (*[]ItemField)(f))
+}
+
+// CustomItemDTO DTO object for CustomItem type, the fields are keyed by
+// their names in the template
+type CustomItemDTO struct {
+	ID           uint              `json:"id"`
+	Revision     uint              `json:"revision"`
+	Type         string            `json:"type"`
+	Title        string            `json:"title"`
+	Fields       map[string]string `json:"fields"`
+	CustomFields CustomFields      `json:"custom_fields"`
+	Favorite     bool              `json:"favorite"`
+	FolderID     *uint             `json:"folder_id"`
+	TagIDs       []uint            `json:"tag_ids"`
+}
+
+// ToCustomItem creates an item of the template, its fields are ordered
+// like the template and marked secret according to it
+func ToCustomItem(customItemDTO *CustomItemDTO, template *Template) *CustomItem {
+	fields := ItemFields{}
+	for _, field := range template.Fields {
+		if value, ok := customItemDTO.Fields[field.Name]; ok {
+			fields = append(fields, ItemField{Name: field.Name, Value: value, Secret: field.Secret})
+		}
+	}
+
+	return &CustomItem{
+		Type:         template.Type,
+		Title:        customItemDTO.Title,
+		Fields:       fields,
+		CustomFields: customItemDTO.CustomFields,
+		Favorite:     customItemDTO.Favorite,
+		FolderID:     customItemDTO.FolderID,
+		TagIDs:       customItemDTO.TagIDs,
+	}
+}
+
+// ToCustomItemDTO ...
+func ToCustomItemDTO(customItem *CustomItem) *CustomItemDTO {
+	fields := map[string]string{}
+	for _, field := range customItem.Fields {
+		fields[field.Name] = field.Value
+	}
+
+	return &CustomItemDTO{
+		ID:           customItem.ID,
+		Revision:     customItem.Revision,
+		Type:         customItem.Type,
+		Title:        customItem.Title,
+		Fields:       fields,
+		CustomFields: customItem.CustomFields,
+		Favorite:     customItem.Favorite,
+		FolderID:     customItem.FolderID,
+		TagIDs:       customItem.TagIDs,
+	}
+}
+
+// ToCustomItemDTOs ...
+func ToCustomItemDTOs(customItems []*CustomItem) []*CustomItemDTO {
+	customItemDTOs := make([]*CustomItemDTO, len(customItems))
+
+	for i, itm := range customItems {
+		customItemDTOs[i] = ToCustomItemDTO(itm)
+	}
+
+	return customItemDTOs
+}
+
+/* EXAMPLE JSON OBJECT
+{
+	"title":"Office",
+	"fields":{
+		"ssid":"passwall",
+		"password":"dummypassword"
+	}
+}
+*/
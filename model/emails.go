@@ -6,39 +6,59 @@ import (
 
 // Email ...
 type Email struct {
-	ID        uint       `gorm:"primary_key" json:"id"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at"`
-	Title     string     `json:"title"`
-	Email     string     `json:"email" encrypt:"true"`
-	Password  string     `json:"password" encrypt:"true"`
+	ID           uint         `gorm:"primary_key" json:"id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	DeletedAt    *time.Time   `json:"deleted_at"`
+	Revision     uint         `gorm:"not null;default:1" json:"revision"`
+	Title        string       `json:"title"`
+	Email        string       `json:"email" encrypt:"true" search:"token"`
+	Password     string       `json:"password" encrypt:"true"`
+	CustomFields CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex  string       `gorm:"type:text" json:"-"`
 }
 
 // EmailDTO ...
 type EmailDTO struct {
-	ID       uint   `json:"id"`
-	Title    string `json:"title"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	ID           uint         `json:"id"`
+	Revision     uint         `json:"revision"`
+	Title        string       `json:"title"`
+	Email        string       `json:"email"`
+	Password     string       `json:"password"`
+	CustomFields CustomFields `json:"custom_fields"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `json:"tag_ids"`
 }
 
 // ToEmail ...
 func ToEmail(emailDTO *EmailDTO) *Email {
 	return &Email{
-		Title:    emailDTO.Title,
-		Email:    emailDTO.Email,
-		Password: emailDTO.Password,
+		Title:        emailDTO.Title,
+		Email:        emailDTO.Email,
+		Password:     emailDTO.Password,
+		CustomFields: emailDTO.CustomFields,
+		Favorite:     emailDTO.Favorite,
+		FolderID:     emailDTO.FolderID,
+		TagIDs:       emailDTO.TagIDs,
 	}
 }
 
 // ToEmailDTO ...
 func ToEmailDTO(email *Email) *EmailDTO {
 	return &EmailDTO{
-		ID:       email.ID,
-		Title:    email.Title,
-		Email:    email.Email,
-		Password: email.Password,
+		ID:           email.ID,
+		Revision:     email.Revision,
+		Title:        email.Title,
+		Email:        email.Email,
+		Password:     email.Password,
+		CustomFields: email.CustomFields,
+		Favorite:     email.Favorite,
+		FolderID:     email.FolderID,
+		TagIDs:       email.TagIDs,
 	}
 }
 
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Types of the events sent to the devices of a user
+const (
+	ItemChangedEvent = "item-changed"
+	ItemDeletedEvent = "item-deleted"
+)
+
+// Event tells the devices of a user that an item was saved or moved to the trash
+type Event struct {
+	Type     string    `json:"type"`
+	ItemType string    `json:"item_type"`
+	ItemID   uint      `json:"item_id"`
+	Time     time.Time `json:"time"`
+}
+
+// EventTokenDTO is a short-lived token which opens an event stream
+type EventTokenDTO struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Folder groups items of any type, folders can be nested by ParentID
+type Folder struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	ParentID  *uint     `json:"parent_id"`
+}
+
+// FolderDTO DTO object for Folder type
+type FolderDTO struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name" validate:"required"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// ToFolder ...
+func ToFolder(folderDTO *FolderDTO) *Folder {
+	return &Folder{
+		Name:     folderDTO.Name,
+		ParentID: folderDTO.ParentID,
+	}
+}
+
+// ToFolderDTO ...
+func ToFolderDTO(folder *Folder) *FolderDTO {
+	return &FolderDTO{
+		ID:       folder.ID,
+		Name:     folder.Name,
+		ParentID: folder.ParentID,
+	}
+}
+
+// ToFolderDTOs ...
+func ToFolderDTOs(folders []*Folder) []*FolderDTO {
+	folderDTOs := make([]*FolderDTO, len(folders))
+
+	for i, itm := range folders {
+		folderDTOs[i] = ToFolderDTO(itm)
+	}
+
+	return folderDTOs
+}
+
+// ItemRef points to an item of any type
+type ItemRef struct {
+	Type string `json:"type"`
+	ID   uint   `json:"id"`
+}
+
+// MoveItemsDTO moves items into a folder, a nil FolderID moves them out of any folder
+type MoveItemsDTO struct {
+	FolderID *uint     `json:"folder_id"`
+	Items    []ItemRef `json:"items"`
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"time"
+)
+
+// Identity keeps the personal details used to fill in forms
+type Identity struct {
+	ID             uint         `gorm:"primary_key" json:"id"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	DeletedAt      *time.Time   `json:"deleted_at"`
+	Revision       uint         `gorm:"not null;default:1" json:"revision"`
+	Title          string       `json:"title"`
+	FirstName      string       `json:"first_name" encrypt:"true" search:"token"`
+	MiddleName     string       `json:"middle_name" encrypt:"true"`
+	LastName       string       `json:"last_name" encrypt:"true" search:"token"`
+	BirthDate      string       `json:"birth_date" encrypt:"true"`
+	Company        string       `json:"company" encrypt:"true" search:"token"`
+	Email          string       `json:"email" encrypt:"true" search:"token"`
+	Phone          string       `json:"phone" encrypt:"true" search:"exact"`
+	Address1       string       `json:"address1" encrypt:"true"`
+	Address2       string       `json:"address2" encrypt:"true"`
+	City           string       `json:"city" encrypt:"true"`
+	State          string       `json:"state" encrypt:"true"`
+	PostalCode     string       `json:"postal_code" encrypt:"true"`
+	Country        string       `json:"country" encrypt:"true"`
+	PassportNumber string       `json:"passport_number" encrypt:"true" search:"exact"`
+	IDNumber       string       `json:"id_number" encrypt:"true" search:"exact"`
+	LicenseNumber  string       `json:"license_number" encrypt:"true" search:"exact"`
+	Extra          string       `json:"extra" encrypt:"true"`
+	CustomFields   CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite       bool         `json:"favorite"`
+	FolderID       *uint        `json:"folder_id"`
+	TagIDs         []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex    string       `gorm:"type:text" json:"-"`
+}
+
+// IdentityDTO ...
+type IdentityDTO struct {
+	ID             uint         `json:"id"`
+	Revision       uint         `json:"revision"`
+	Title          string       `json:"title"`
+	FirstName      string       `json:"first_name"`
+	MiddleName     string       `json:"middle_name"`
+	LastName       string       `json:"last_name"`
+	BirthDate      string       `json:"birth_date"`
+	Company        string       `json:"company"`
+	Email          string       `json:"email"`
+	Phone          string       `json:"phone"`
+	Address1       string       `json:"address1"`
+	Address2       string       `json:"address2"`
+	City           string       `json:"city"`
+	State          string       `json:"state"`
+	PostalCode     string       `json:"postal_code"`
+	Country        string       `json:"country"`
+	PassportNumber string       `json:"passport_number"`
+	IDNumber       string       `json:"id_number"`
+	LicenseNumber  string       `json:"license_number"`
+	Extra          string       `json:"extra"`
+	CustomFields   CustomFields `json:"custom_fields"`
+	Favorite       bool         `json:"favorite"`
+	FolderID       *uint        `json:"folder_id"`
+	TagIDs         []uint       `json:"tag_ids"`
+}
+
+// ToIdentity ...
+func ToIdentity(identityDTO *IdentityDTO) *Identity {
+	return &Identity{
+		Title:          identityDTO.Title,
+		FirstName:      identityDTO.FirstName,
+		MiddleName:     identityDTO.MiddleName,
+		LastName:       identityDTO.LastName,
+		BirthDate:      identityDTO.BirthDate,
+		Company:        identityDTO.Company,
+		Email:          identityDTO.Email,
+		Phone:          identityDTO.Phone,
+		Address1:       identityDTO.Address1,
+		Address2:       identityDTO.Address2,
+		City:           identityDTO.City,
+		State:          identityDTO.State,
+		PostalCode:     identityDTO.PostalCode,
+		Country:        identityDTO.Country,
+		PassportNumber: identityDTO.PassportNumber,
+		IDNumber:       identityDTO.IDNumber,
+		LicenseNumber:  identityDTO.LicenseNumber,
+		Extra:          identityDTO.Extra,
+		CustomFields:   identityDTO.CustomFields,
+		Favorite:       identityDTO.Favorite,
+		FolderID:       identityDTO.FolderID,
+		TagIDs:         identityDTO.TagIDs,
+	}
+}
+
+// ToIdentityDTO ...
+func ToIdentityDTO(identity *Identity) *IdentityDTO {
+	return &IdentityDTO{
+		ID:             identity.ID,
+		Revision:       identity.Revision,
+		Title:          identity.Title,
+		FirstName:      identity.FirstName,
+		MiddleName:     identity.MiddleName,
+		LastName:       identity.LastName,
+		BirthDate:      identity.BirthDate,
+		Company:        identity.Company,
+		Email:          identity.Email,
+		Phone:          identity.Phone,
+		Address1:       identity.Address1,
+		Address2:       identity.Address2,
+		City:           identity.City,
+		State:          identity.State,
+		PostalCode:     identity.PostalCode,
+		Country:        identity.Country,
+		PassportNumber: identity.PassportNumber,
+		IDNumber:       identity.IDNumber,
+		LicenseNumber:  identity.LicenseNumber,
+		Extra:          identity.Extra,
+		CustomFields:   identity.CustomFields,
+		Favorite:       identity.Favorite,
+		FolderID:       identity.FolderID,
+		TagIDs:         identity.TagIDs,
+	}
+}
+
+// ToIdentityDTOs ...
+func ToIdentityDTOs(identities []*Identity) []*IdentityDTO {
+	identityDTOs := make([]*IdentityDTO, len(identities))
+
+	for i, itm := range identities {
+		identityDTOs[i] = ToIdentityDTO(itm)
+	}
+
+	return identityDTOs
+}
+
+/* EXAMPLE JSON OBJECT
+{
+	"title":"Personal",
+	"first_name":"John",
+	"last_name":"Doe",
+	"birth_date":"1990-01-31",
+	"email":"hello@passwall.io",
+	"phone":"+90 555 555 55 55",
+	"address1":"Street 1",
+	"city":"Istanbul",
+	"postal_code":"34000",
+	"country":"Turkey",
+	"passport_number":"U12345678",
+	"id_number":"12345678901"
+}
+*/
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// Item types used in endpoints handling more than one type of item
+const (
+	LoginItem       = "login"
+	CreditCardItem  = "credit_card"
+	BankAccountItem = "bank_account"
+	NoteItem        = "note"
+	EmailItem       = "email"
+	ServerItem      = "server"
+	IdentityItem    = "identity"
+	LicenseKeyItem  = "license_key"
+	CustomItemItem  = "custom_item"
+	SSHKeyItem      = "ssh_key"
+)
+
+// ItemTypes lists every item type
+var ItemTypes = []string{LoginItem, CreditCardItem, BankAccountItem, NoteItem, EmailItem, ServerItem, IdentityItem, LicenseKeyItem, CustomItemItem, SSHKeyItem}
+
+// TrashItem is a soft deleted item of any type
+type TrashItem struct {
+	ID        uint      `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Favorites groups the favorite items by type
+type Favorites struct {
+	Logins       []Login       `json:"logins"`
+	CreditCards  []CreditCard  `json:"credit_cards"`
+	BankAccounts []BankAccount `json:"bank_accounts"`
+	Notes        []Note        `json:"notes"`
+	Emails       []Email       `json:"emails"`
+	Servers      []Server      `json:"servers"`
+	Identities   []Identity    `json:"identities"`
+	LicenseKeys  []LicenseKey  `json:"license_keys"`
+	CustomItems  []CustomItem  `json:"custom_items"`
+	SSHKeys      []SSHKey      `json:"ssh_keys"`
+}
+
+// SearchHit is an item of any type found by a search
+type SearchHit struct {
+	Type      string      `json:"type"`
+	ID        uint        `json:"id"`
+	Title     string      `json:"title"`
+	Score     int         `json:"score"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	Item      interface{} `json:"item"`
+}
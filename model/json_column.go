@@ -0,0 +1,16 @@
+package model
+
+import "fmt"
+
+// columnBytes returns the content of a text column holding JSON
+func columnBytes(src interface{}) ([]byte, error) {
+	switch src := src.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(src), nil
+	case []byte:
+		return src, nil
+	}
+	return nil, fmt.Errorf("can't scan %T as JSON", src)
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"time"
+)
+
+// LicenseKey is a software license. The product names the item and the
+// dates stay in plain text so the licenses can be filtered by expiry.
+type LicenseKey struct {
+	ID           uint         `gorm:"primary_key" json:"id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	DeletedAt    *time.Time   `json:"deleted_at"`
+	Revision     uint         `gorm:"not null;default:1" json:"revision"`
+	Product      string       `json:"product"`
+	Version      string       `json:"version" encrypt:"true"`
+	Licensee     string       `json:"licensee" encrypt:"true" search:"token"`
+	Key          string       `json:"key" encrypt:"true" search:"exact"`
+	Seats        int          `json:"seats"`
+	PurchaseDate *time.Time   `json:"purchase_date"`
+	ExpiresAt    *time.Time   `json:"expires_at"`
+	VendorURL    string       `json:"vendor_url" encrypt:"true" search:"token"`
+	Extra        string       `json:"extra" encrypt:"true"`
+	CustomFields CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex  string       `gorm:"type:text" json:"-"`
+}
+
+// LicenseKeyDTO ...
+type LicenseKeyDTO struct {
+	ID           uint         `json:"id"`
+	Revision     uint         `json:"revision"`
+	Product      string       `json:"product"`
+	Version      string       `json:"version"`
+	Licensee     string       `json:"licensee"`
+	Key          string       `json:"key"`
+	Seats        int          `json:"seats"`
+	PurchaseDate *time.Time   `json:"purchase_date"`
+	ExpiresAt    *time.Time   `json:"expires_at"`
+	VendorURL    string       `json:"vendor_url"`
+	Extra        string       `json:"extra"`
+	CustomFields CustomFields `json:"custom_fields"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `json:"tag_ids"`
+}
+
+// ToLicenseKey ...
+func ToLicenseKey(licenseKeyDTO *LicenseKeyDTO) *LicenseKey {
+	return &LicenseKey{
+		Product:      licenseKeyDTO.Product,
+		Version:      licenseKeyDTO.Version,
+		Licensee:     licenseKeyDTO.Licensee,
+		Key:          licenseKeyDTO.Key,
+		Seats:        licenseKeyDTO.Seats,
+		PurchaseDate: licenseKeyDTO.PurchaseDate,
+		ExpiresAt:    licenseKeyDTO.ExpiresAt,
+		VendorURL:    licenseKeyDTO.VendorURL,
+		Extra:        licenseKeyDTO.Extra,
+		CustomFields: licenseKeyDTO.CustomFields,
+		Favorite:     licenseKeyDTO.Favorite,
+		FolderID:     licenseKeyDTO.FolderID,
+		TagIDs:       licenseKeyDTO.TagIDs,
+	}
+}
+
+// ToLicenseKeyDTO ...
+func ToLicenseKeyDTO(licenseKey *LicenseKey) *LicenseKeyDTO {
+	return &LicenseKeyDTO{
+		ID:           licenseKey.ID,
+		Revision:     licenseKey.Revision,
+		Product:      licenseKey.Product,
+		Version:      licenseKey.Version,
+		Licensee:     licenseKey.Licensee,
+		Key:          licenseKey.Key,
+		Seats:        licenseKey.Seats,
+		PurchaseDate: licenseKey.PurchaseDate,
+		ExpiresAt:    licenseKey.ExpiresAt,
+		VendorURL:    licenseKey.VendorURL,
+		Extra:        licenseKey.Extra,
+		CustomFields: licenseKey.CustomFields,
+		Favorite:     licenseKey.Favorite,
+		FolderID:     licenseKey.FolderID,
+		TagIDs:       licenseKey.TagIDs,
+	}
+}
+
+// ToLicenseKeyDTOs ...
+func ToLicenseKeyDTOs(licenseKeys []*LicenseKey) []*LicenseKeyDTO {
+	licenseKeyDTOs := make([]*LicenseKeyDTO, len(licenseKeys))
+
+	for i, itm := range licenseKeys {
+		licenseKeyDTOs[i] = ToLicenseKeyDTO(itm)
+	}
+
+	return licenseKeyDTOs
+}
+
+/* EXAMPLE JSON OBJECT
+{
+	"product":"GoLand",
+	"version":"2020.2",
+	"licensee":"PassWall",
+	"key":"XXXXX-XXXXX-XXXXX-XXXXX",
+	"seats":5,
+	"purchase_date":"2020-08-01T00:00:00Z",
+	"expires_at":"2021-08-01T00:00:00Z",
+	"vendor_url":"https://www.jetbrains.com"
+}
+*/
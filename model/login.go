@@ -6,47 +6,71 @@ import (
 
 // Login ...
 type Login struct {
-	ID        uint       `gorm:"primary_key" json:"id"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at"`
-	Title     string     `json:"title"`
-	URL       string     `json:"url"`
-	Username  string     `json:"username" encrypt:"true"`
-	Password  string     `json:"password" encrypt:"true"`
-	Extra     string     `json:"extra" encrypt:"true"`
+	ID           uint         `gorm:"primary_key" json:"id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	DeletedAt    *time.Time   `json:"deleted_at"`
+	Revision     uint         `gorm:"not null;default:1" json:"revision"`
+	Title        string       `json:"title"`
+	URL          string       `json:"url"`
+	Username     string       `json:"username" encrypt:"true" search:"token"`
+	Password     string       `json:"password" encrypt:"true"`
+	TOTP         string       `json:"totp" encrypt:"true"`
+	Extra        string       `json:"extra" encrypt:"true"`
+	CustomFields CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex  string       `gorm:"type:text" json:"-"`
 }
 
 //LoginDTO DTO object for Login type
 type LoginDTO struct {
-	ID       uint   `json:"id"`
-	Title    string `json:"title"`
-	URL      string `json:"url"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Extra    string `json:"extra"`
+	ID           uint         `json:"id"`
+	Revision     uint         `json:"revision"`
+	Title        string       `json:"title"`
+	URL          string       `json:"url"`
+	Username     string       `json:"username"`
+	Password     string       `json:"password"`
+	TOTP         string       `json:"totp"`
+	Extra        string       `json:"extra"`
+	CustomFields CustomFields `json:"custom_fields"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `json:"tag_ids"`
 }
 
 // ToLogin ...
 func ToLogin(loginDTO *LoginDTO) *Login {
 	return &Login{
-		Title:    loginDTO.Title,
-		URL:      loginDTO.URL,
-		Username: loginDTO.Username,
-		Password: loginDTO.Password,
-		Extra:    loginDTO.Extra,
+		Title:        loginDTO.Title,
+		URL:          loginDTO.URL,
+		Username:     loginDTO.Username,
+		Password:     loginDTO.Password,
+		TOTP:         loginDTO.TOTP,
+		Extra:        loginDTO.Extra,
+		CustomFields: loginDTO.CustomFields,
+		Favorite:     loginDTO.Favorite,
+		FolderID:     loginDTO.FolderID,
+		TagIDs:       loginDTO.TagIDs,
 	}
 }
 
 // ToLoginDTO ...
 func ToLoginDTO(login *Login) *LoginDTO {
 	return &LoginDTO{
-		ID:       login.ID,
-		Title:    login.Title,
-		URL:      login.URL,
-		Username: login.Username,
-		Password: login.Password,
-		Extra:    login.Extra,
+		ID:           login.ID,
+		Revision:     login.Revision,
+		Title:        login.Title,
+		URL:          login.URL,
+		Username:     login.Username,
+		Password:     login.Password,
+		TOTP:         login.TOTP,
+		Extra:        login.Extra,
+		CustomFields: login.CustomFields,
+		Favorite:     login.Favorite,
+		FolderID:     login.FolderID,
+		TagIDs:       login.TagIDs,
 	}
 }
 
@@ -61,6 +85,13 @@ func ToLoginDTOs(logins []*Login) []*LoginDTO {
 	return loginDTOs
 }
 
+// TOTPCode is the current one-time code of a login
+type TOTPCode struct {
+	Code      string `json:"code"`
+	Period    int    `json:"period"`
+	Remaining int    `json:"remaining"`
+}
+
 // URLs ...
 type URLs struct {
 	Items []string `json:"urls"`
@@ -82,7 +113,8 @@ type Password struct {
 	"Title":"Dummy Title",
 	"URL":"http://dummywebsite.com",
 	"Username": "dummyuser",
-	"Password": "dummypassword"
+	"Password": "dummypassword",
+	"TOTP": "otpauth://totp/Dummy:dummyuser?secret=JBSWY3DPEHPK3PXP&issuer=Dummy",
 	"Extra": "additional information"
 }
 */
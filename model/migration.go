@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Migration is a versioned schema migration applied to a schema
+type Migration struct {
+	Version   uint      `gorm:"primary_key;auto_increment:false" json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// MigrationStatus tells if a known migration is applied to a schema
+type MigrationStatus struct {
+	Version   uint       `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// MigrationLock makes sure a single server instance runs the migrations
+type MigrationLock struct {
+	ID       uint   `gorm:"primary_key;auto_increment:false"`
+	Owner    string `gorm:"type:varchar(255)"`
+	LockedAt time.Time
+}
@@ -6,35 +6,55 @@ import (
 
 // Note ...
 type Note struct {
-	ID        uint       `gorm:"primary_key" json:"id"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at"`
-	Title     string     `json:"title"`
-	Note      string     `json:"note" encrypt:"true"`
+	ID           uint         `gorm:"primary_key" json:"id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	DeletedAt    *time.Time   `json:"deleted_at"`
+	Revision     uint         `gorm:"not null;default:1" json:"revision"`
+	Title        string       `json:"title"`
+	Note         string       `json:"note" encrypt:"true" search:"token"`
+	CustomFields CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex  string       `gorm:"type:text" json:"-"`
 }
 
 // NoteDTO ...
 type NoteDTO struct {
-	ID    uint   `json:"id"`
-	Title string `json:"title"`
-	Note  string `json:"note"`
+	ID           uint         `json:"id"`
+	Revision     uint         `json:"revision"`
+	Title        string       `json:"title"`
+	Note         string       `json:"note"`
+	CustomFields CustomFields `json:"custom_fields"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `json:"tag_ids"`
 }
 
 // ToNote ...
 func ToNote(noteDTO *NoteDTO) *Note {
 	return &Note{
-		Title: noteDTO.Title,
-		Note:  noteDTO.Note,
+		Title:        noteDTO.Title,
+		Note:         noteDTO.Note,
+		CustomFields: noteDTO.CustomFields,
+		Favorite:     noteDTO.Favorite,
+		FolderID:     noteDTO.FolderID,
+		TagIDs:       noteDTO.TagIDs,
 	}
 }
 
 // ToNoteDTO ...
 func ToNoteDTO(note *Note) *NoteDTO {
 	return &NoteDTO{
-		ID:    note.ID,
-		Title: note.Title,
-		Note:  note.Note,
+		ID:           note.ID,
+		Revision:     note.Revision,
+		Title:        note.Title,
+		Note:         note.Note,
+		CustomFields: note.CustomFields,
+		Favorite:     note.Favorite,
+		FolderID:     note.FolderID,
+		TagIDs:       note.TagIDs,
 	}
 }
 
@@ -6,3 +6,19 @@ type Response struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 }
+
+// BulkResult is the result of an item of a bulk request
+type BulkResult struct {
+	Index    int    `json:"index"`
+	ID       uint   `json:"id"`
+	Error    string `json:"error,omitempty"`
+	Revision uint   `json:"revision,omitempty"`
+}
+
+// BulkResponse is the response of a bulk request with the result of every item
+type BulkResponse struct {
+	Code    int          `json:"code"`
+	Status  string       `json:"status"`
+	Message string       `json:"message"`
+	Results []BulkResult `json:"results"`
+}
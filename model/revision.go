@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Revision is an encrypted snapshot of an item taken on every save.
+// Data is the JSON of the item as it is stored, so the item's own
+// encrypted fields stay encrypted inside the snapshot too.
+type Revision struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ItemType  string    `json:"item_type"`
+	ItemID    uint      `json:"item_id"`
+	Data      string    `gorm:"type:text" json:"-" encrypt:"true"`
+}
+
+// RevisionDTO DTO object for Revision type
+type RevisionDTO struct {
+	ID        uint        `json:"id"`
+	CreatedAt time.Time   `json:"created_at"`
+	ItemType  string      `json:"item_type"`
+	ItemID    uint        `json:"item_id"`
+	Item      interface{} `json:"item,omitempty"`
+}
+
+// ToRevisionDTO ...
+func ToRevisionDTO(revision *Revision) *RevisionDTO {
+	return &RevisionDTO{
+		ID:        revision.ID,
+		CreatedAt: revision.CreatedAt,
+		ItemType:  revision.ItemType,
+		ItemID:    revision.ItemID,
+	}
+}
+
+// FieldChange is a field which differs between a revision and the current item
+type FieldChange struct {
+	Field    string      `json:"field"`
+	Revision interface{} `json:"revision"`
+	Current  interface{} `json:"current"`
+}
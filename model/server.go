@@ -6,35 +6,46 @@ import (
 
 // Server ...
 type Server struct {
-	ID              uint       `gorm:"primary_key" json:"id"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
-	DeletedAt       *time.Time `json:"deleted_at"`
-	Title           string     `json:"title"`
-	IP              string     `json:"ip" encrypt:"true"`
-	Username        string     `json:"username" encrypt:"true"`
-	Password        string     `json:"password" encrypt:"true"`
-	URL             string     `json:"url"`
-	HostingUsername string     `json:"hosting_username" encrypt:"true"`
-	HostingPassword string     `json:"hosting_password" encrypt:"true"`
-	AdminUsername   string     `json:"admin_username" encrypt:"true"`
-	AdminPassword   string     `json:"admin_password" encrypt:"true"`
-	Extra           string     `json:"extra" encrypt:"true"`
+	ID              uint         `gorm:"primary_key" json:"id"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	DeletedAt       *time.Time   `json:"deleted_at"`
+	Revision        uint         `gorm:"not null;default:1" json:"revision"`
+	Title           string       `json:"title"`
+	IP              string       `json:"ip" encrypt:"true" search:"token"`
+	Username        string       `json:"username" encrypt:"true" search:"token"`
+	Password        string       `json:"password" encrypt:"true"`
+	URL             string       `json:"url"`
+	HostingUsername string       `json:"hosting_username" encrypt:"true" search:"token"`
+	HostingPassword string       `json:"hosting_password" encrypt:"true"`
+	AdminUsername   string       `json:"admin_username" encrypt:"true" search:"token"`
+	AdminPassword   string       `json:"admin_password" encrypt:"true"`
+	Extra           string       `json:"extra" encrypt:"true"`
+	CustomFields    CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite        bool         `json:"favorite"`
+	FolderID        *uint        `json:"folder_id"`
+	TagIDs          []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex     string       `gorm:"type:text" json:"-"`
 }
 
 //ServerDTO DTO object for Server type
 type ServerDTO struct {
-	ID              uint   `json:"id"`
-	Title           string `json:"title"`
-	IP              string `json:"ip"`
-	Username        string `json:"username"`
-	Password        string `json:"password"`
-	URL             string `json:"url"`
-	HostingUsername string `json:"hosting_username"`
-	HostingPassword string `json:"hosting_password"`
-	AdminUsername   string `json:"admin_username"`
-	AdminPassword   string `json:"admin_password"`
-	Extra           string `json:"extra"`
+	ID              uint         `json:"id"`
+	Revision        uint         `json:"revision"`
+	Title           string       `json:"title"`
+	IP              string       `json:"ip"`
+	Username        string       `json:"username"`
+	Password        string       `json:"password"`
+	URL             string       `json:"url"`
+	HostingUsername string       `json:"hosting_username"`
+	HostingPassword string       `json:"hosting_password"`
+	AdminUsername   string       `json:"admin_username"`
+	AdminPassword   string       `json:"admin_password"`
+	Extra           string       `json:"extra"`
+	CustomFields    CustomFields `json:"custom_fields"`
+	Favorite        bool         `json:"favorite"`
+	FolderID        *uint        `json:"folder_id"`
+	TagIDs          []uint       `json:"tag_ids"`
 }
 
 // ToServer ...
@@ -50,6 +61,10 @@ func ToServer(serverDTO *ServerDTO) *Server {
 		AdminUsername:   serverDTO.AdminUsername,
 		AdminPassword:   serverDTO.AdminPassword,
 		Extra:           serverDTO.Extra,
+		CustomFields:    serverDTO.CustomFields,
+		Favorite:        serverDTO.Favorite,
+		FolderID:        serverDTO.FolderID,
+		TagIDs:          serverDTO.TagIDs,
 	}
 }
 
@@ -57,6 +72,7 @@ func ToServer(serverDTO *ServerDTO) *Server {
 func ToServerDTO(server *Server) *ServerDTO {
 	return &ServerDTO{
 		ID:              server.ID,
+		Revision:        server.Revision,
 		Title:           server.Title,
 		IP:              server.IP,
 		Username:        server.Username,
@@ -67,6 +83,10 @@ func ToServerDTO(server *Server) *ServerDTO {
 		AdminUsername:   server.AdminUsername,
 		AdminPassword:   server.AdminPassword,
 		Extra:           server.Extra,
+		CustomFields:    server.CustomFields,
+		Favorite:        server.Favorite,
+		FolderID:        server.FolderID,
+		TagIDs:          server.TagIDs,
 	}
 }
 
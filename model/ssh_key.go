@@ -0,0 +1,117 @@
+package model
+
+import (
+	"time"
+)
+
+// SSHKey keeps an SSH key pair, optionally linked to the server it opens
+type SSHKey struct {
+	ID           uint         `gorm:"primary_key" json:"id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	DeletedAt    *time.Time   `json:"deleted_at"`
+	Revision     uint         `gorm:"not null;default:1" json:"revision"`
+	Title        string       `json:"title"`
+	PrivateKey   string       `gorm:"type:text" json:"private_key" encrypt:"true"`
+	Passphrase   string       `json:"passphrase" encrypt:"true"`
+	PublicKey    string       `gorm:"type:text" json:"public_key"`
+	Fingerprint  string       `json:"fingerprint"`
+	Comment      string       `json:"comment" encrypt:"true" search:"token"`
+	ServerID     *uint        `json:"server_id"`
+	Extra        string       `json:"extra" encrypt:"true"`
+	CustomFields CustomFields `gorm:"type:text" json:"custom_fields" encrypt:"true"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `gorm:"-" json:"tag_ids"`
+	SearchIndex  string       `gorm:"type:text" json:"-"`
+}
+
+// SSHKeyDTO ...
+type SSHKeyDTO struct {
+	ID           uint         `json:"id"`
+	Revision     uint         `json:"revision"`
+	Title        string       `json:"title"`
+	PrivateKey   string       `json:"private_key"`
+	Passphrase   string       `json:"passphrase"`
+	PublicKey    string       `json:"public_key"`
+	Fingerprint  string       `json:"fingerprint"`
+	Comment      string       `json:"comment"`
+	ServerID     *uint        `json:"server_id"`
+	Extra        string       `json:"extra"`
+	CustomFields CustomFields `json:"custom_fields"`
+	Favorite     bool         `json:"favorite"`
+	FolderID     *uint        `json:"folder_id"`
+	TagIDs       []uint       `json:"tag_ids"`
+}
+
+// SSHKeyGenerateDTO asks for a new key pair. Type is ed25519 or rsa, Bits
+// is the size of rsa keys.
+type SSHKeyGenerateDTO struct {
+	Title      string `json:"title"`
+	Type       string `json:"type"`
+	Bits       int    `json:"bits"`
+	Passphrase string `json:"passphrase"`
+	Comment    string `json:"comment"`
+	ServerID   *uint  `json:"server_id"`
+	FolderID   *uint  `json:"folder_id"`
+	TagIDs     []uint `json:"tag_ids"`
+}
+
+// ToSSHKey ...
+func ToSSHKey(sshKeyDTO *SSHKeyDTO) *SSHKey {
+	return &SSHKey{
+		Title:        sshKeyDTO.Title,
+		PrivateKey:   sshKeyDTO.PrivateKey,
+		Passphrase:   sshKeyDTO.Passphrase,
+		PublicKey:    sshKeyDTO.PublicKey,
+		Fingerprint:  sshKeyDTO.Fingerprint,
+		Comment:      sshKeyDTO.Comment,
+		ServerID:     sshKeyDTO.ServerID,
+		Extra:        sshKeyDTO.Extra,
+		CustomFields: sshKeyDTO.CustomFields,
+		Favorite:     sshKeyDTO.Favorite,
+		FolderID:     sshKeyDTO.FolderID,
+		TagIDs:       sshKeyDTO.TagIDs,
+	}
+}
+
+// ToSSHKeyDTO ...
+func ToSSHKeyDTO(sshKey *SSHKey) *SSHKeyDTO {
+	return &SSHKeyDTO{
+		ID:           sshKey.ID,
+		Revision:     sshKey.Revision,
+		Title:        sshKey.Title,
+		PrivateKey:   sshKey.PrivateKey,
+		Passphrase:   sshKey.Passphrase,
+		PublicKey:    sshKey.PublicKey,
+		Fingerprint:  sshKey.Fingerprint,
+		Comment:      sshKey.Comment,
+		ServerID:     sshKey.ServerID,
+		Extra:        sshKey.Extra,
+		CustomFields: sshKey.CustomFields,
+		Favorite:     sshKey.Favorite,
+		FolderID:     sshKey.FolderID,
+		TagIDs:       sshKey.TagIDs,
+	}
+}
+
+// ToSSHKeyDTOs ...
+func ToSSHKeyDTOs(sshKeys []*SSHKey) []*SSHKeyDTO {
+	sshKeyDTOs := make([]*SSHKeyDTO, len(sshKeys))
+
+	for i, itm := range sshKeys {
+		sshKeyDTOs[i] = ToSSHKeyDTO(itm)
+	}
+
+	return sshKeyDTOs
+}
+
+/* EXAMPLE JSON OBJECT
+{
+	"title":"Deploy key",
+	"type":"ed25519",
+	"passphrase":"dummypassphrase",
+	"comment":"deploy@passwall.io",
+	"server_id":1
+}
+*/
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// SyncItem is an item of any type created or updated since a sync
+type SyncItem struct {
+	Type      string      `json:"type"`
+	ID        uint        `json:"id"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	Item      interface{} `json:"item"`
+}
+
+// Sync is the changes of a vault since the sync of the token the client sent,
+// Token is sent with the next sync. Full is set when the token is older than
+// the tombstones which are kept, every item is sent and the client replaces
+// its copy of the vault.
+type Sync struct {
+	Token   string      `json:"token"`
+	Full    bool        `json:"full"`
+	Created []SyncItem  `json:"created"`
+	Updated []SyncItem  `json:"updated"`
+	Deleted []Tombstone `json:"deleted"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// Tag labels items of any type, an item can have many tags.
+// Names are encrypted since they can tell a lot about the items.
+type Tag struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" encrypt:"true"`
+}
+
+// TagDTO DTO object for Tag type
+type TagDTO struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name" validate:"required"`
+}
+
+// ToTag ...
+func ToTag(tagDTO *TagDTO) *Tag {
+	return &Tag{
+		Name: tagDTO.Name,
+	}
+}
+
+// ToTagDTO ...
+func ToTagDTO(tag *Tag) *TagDTO {
+	return &TagDTO{
+		ID:   tag.ID,
+		Name: tag.Name,
+	}
+}
+
+// ToTagDTOs ...
+func ToTagDTOs(tags []*Tag) []*TagDTO {
+	tagDTOs := make([]*TagDTO, len(tags))
+
+	for i, itm := range tags {
+		tagDTOs[i] = ToTagDTO(itm)
+	}
+
+	return tagDTOs
+}
+
+// ItemTag links a tag to an item
+type ItemTag struct {
+	ID       uint   `gorm:"primary_key" json:"id"`
+	TagID    uint   `json:"tag_id"`
+	ItemType string `json:"item_type"`
+	ItemID   uint   `json:"item_id"`
+}
+
+// MergeTagDTO merges a tag into the target tag
+type MergeTagDTO struct {
+	TargetID uint `json:"target_id" validate:"required"`
+}
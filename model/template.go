@@ -0,0 +1,98 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Template defines a custom item type. Items of the template are found
+// under its Type and their secret fields are encrypted.
+type Template struct {
+	ID        uint           `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Type      string         `json:"type"`
+	Name      string         `json:"name"`
+	Fields    TemplateFields `gorm:"type:text" json:"fields"`
+}
+
+// TemplateField is a field of the items of a template, its type is one of
+// the custom field types
+type TemplateField struct {
+	Name     string `json:"name" validate:"required"`
+	Type     string `json:"type" validate:"required"`
+	Secret   bool   `json:"secret"`
+	Required bool   `json:"required"`
+}
+
+// TemplateFields are the fields of a template, stored as JSON in a single column
+type TemplateFields []TemplateField
+
+// Value implements driver.Valuer
+func (f TemplateFields) Value() (driver.Value, error) {
+	if f == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]TemplateField(f))
+	return string(data), err
+}
+
+// Scan implements sql.Scanner
+func (f *TemplateFields) Scan(src interface{}) error {
+	data, err := columnBytes(src)
+	if err != nil || len(data) == 0 {
+		*f = nil
+		return err
+	}
+	return json.Unmarshal(data, (*[]TemplateField)(f))
+}
+
+// Field returns the field of the template with the name
+func (t *Template) Field(name string) (TemplateField, bool) {
+	for _, field := range t.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return TemplateField{}, false
+}
+
+// TemplateDTO DTO object for Template type
+type TemplateDTO struct {
+	ID     uint            `json:"id"`
+	Type   string          `json:"type" validate:"required"`
+	Name   string          `json:"name" validate:"required"`
+	Fields []TemplateField `json:"fields" validate:"required,dive"`
+}
+
+// ToTemplate ...
+func ToTemplate(templateDTO *TemplateDTO) *Template {
+	return &Template{
+		Type:   templateDTO.Type,
+		Name:   templateDTO.Name,
+		Fields: templateDTO.Fields,
+	}
+}
+
+// ToTemplateDTO ...
+func ToTemplateDTO(template *Template) *TemplateDTO {
+	return &TemplateDTO{
+		ID:     template.ID,
+		Type:   template.Type,
+		Name:   template.Name,
+		Fields: template.Fields,
+	}
+}
+
+/* EXAMPLE JSON OBJECT
+{
+	"type":"wifi-network",
+	"name":"Wi-Fi Network",
+	"fields":[
+		{"name":"ssid","type":"text","required":true},
+		{"name":"password","type":"hidden","secret":true},
+		{"name":"router","type":"link"}
+	]
+}
+*/
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Tombstone records an item purged from the trash, so clients which synced
+// before the item was deleted learn that it is gone
+type Tombstone struct {
+	ID        uint      `gorm:"primary_key" json:"-"`
+	CreatedAt time.Time `json:"deleted_at"`
+	ItemType  string    `json:"type"`
+	ItemID    uint      `json:"id"`
+}